@@ -1,15 +1,37 @@
 package main
 
 import (
+	"flag"
+	"fmt"
+	"io"
 	"log"
+	"os"
+	"strings"
+	"time"
 
+	"tomato_clock/internal/audio"
+	"tomato_clock/internal/dataio"
+	"tomato_clock/internal/ics"
 	"tomato_clock/internal/model"
+	"tomato_clock/internal/report"
 	"tomato_clock/internal/ui"
 
 	"fyne.io/fyne/v2/app"
 )
 
 func main() {
+	reportPeriod := flag.String("report", "", "生成统计报表后退出：week / lastweek / month / lastmonth")
+	reportFormat := flag.String("format", report.FormatMarkdown, "报表格式：md / html")
+	exportFormat := flag.String("export", "", "导出数据后退出：sessions-csv / tasks-csv / json")
+	from := flag.String("from", "", "导出开始日期 YYYY-MM-DD")
+	to := flag.String("to", "", "导出结束日期 YYYY-MM-DD（含当天）")
+	labels := flag.String("labels", "", "按标签筛选导出，多个用逗号分隔")
+	interrupted := flag.Bool("interrupted", false, "导出时包含被中断的记录")
+	importPath := flag.String("import", "", "从 JSON / CSV 文件导入数据后退出")
+	dryRun := flag.Bool("dry-run", false, "导入时仅校验，不写入数据")
+	outPath := flag.String("o", "", "输出文件路径，默认输出到标准输出")
+	flag.Parse()
+
 	log.Println("开始启动番茄钟应用...")
 
 	if err := model.Init(); err != nil {
@@ -17,6 +39,32 @@ func main() {
 	}
 	log.Println("数据初始化成功")
 
+	if *reportPeriod != "" {
+		if err := runReport(*reportPeriod, *reportFormat, *outPath); err != nil {
+			log.Fatalf("生成报表失败: %v", err)
+		}
+		return
+	}
+	if *exportFormat != "" {
+		if err := runExport(*exportFormat, *from, *to, *labels, *interrupted, *outPath); err != nil {
+			log.Fatalf("导出数据失败: %v", err)
+		}
+		return
+	}
+	if *importPath != "" {
+		if err := runImport(*importPath, *dryRun); err != nil {
+			log.Fatalf("导入数据失败: %v", err)
+		}
+		return
+	}
+
+	// 日历订阅文件随数据变更自动更新
+	if feedPath, err := ics.DefaultFeedPath(); err == nil {
+		if err := ics.EnableFeed(feedPath); err != nil {
+			log.Printf("[ERROR] 生成日历订阅文件失败: %v", err)
+		}
+	}
+
 	a := app.New()
 	log.Println("创建应用实例")
 
@@ -28,4 +76,89 @@ func main() {
 	log.Println("主窗口创建成功，准备显示...")
 
 	win.ShowAndRun()
+	audio.Shutdown()
+}
+
+// runReport 在命令行模式下生成报表，不启动图形界面
+func runReport(period, format, outPath string) error {
+	p, err := report.NewPeriod(period, time.Now())
+	if err != nil {
+		return err
+	}
+	r := report.Generate(p, report.DefaultGoal)
+
+	err = writeOutput(outPath, func(w io.Writer) error {
+		return report.Render(w, r, format)
+	})
+	if err != nil {
+		return err
+	}
+	log.Printf("[REPORT] %s 已生成", p.Title)
+	return nil
+}
+
+// writeOutput 将 write 的内容写入输出文件，路径为空时写到标准输出（不关闭）；
+// 写入文件时关闭失败也作为错误返回，避免数据未落盘却报告成功
+func writeOutput(path string, write func(io.Writer) error) error {
+	if path == "" {
+		return write(os.Stdout)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// runExport 在命令行模式下按筛选条件导出数据
+func runExport(format, from, to, labels string, interrupted bool, outPath string) error {
+	filter := dataio.Filter{IncludeInterrupted: interrupted}
+	var err error
+	if from != "" {
+		if filter.From, err = time.ParseInLocation("2006-01-02", from, time.Local); err != nil {
+			return err
+		}
+	}
+	if to != "" {
+		if filter.To, err = time.ParseInLocation("2006-01-02", to, time.Local); err != nil {
+			return err
+		}
+		filter.To = filter.To.AddDate(0, 0, 1)
+	}
+	for _, l := range strings.Split(labels, ",") {
+		if l = strings.TrimSpace(l); l != "" {
+			filter.Labels = append(filter.Labels, l)
+		}
+	}
+
+	return writeOutput(outPath, func(w io.Writer) error {
+		return dataio.Export(w, format, filter)
+	})
+}
+
+// runImport 在命令行模式下导入数据并打印结果
+func runImport(path string, dryRun bool) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	ds, err := dataio.ReadFile(f, path)
+	if err != nil {
+		return err
+	}
+	res, err := dataio.Import(ds, dataio.ImportOptions{DryRun: dryRun})
+	if err != nil {
+		return err
+	}
+	if dryRun {
+		fmt.Println("[试运行] 未写入任何数据")
+	}
+	fmt.Println(res.Summary())
+	return nil
 }
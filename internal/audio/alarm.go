@@ -0,0 +1,200 @@
+package audio
+
+import (
+	"time"
+
+	"tomato_clock/internal/config"
+
+	"github.com/faiface/beep"
+)
+
+// escalateRamp 升级音量时的过渡时长，避免音量突变
+const escalateRamp = 2 * time.Second
+
+// AlarmProfile 闹铃方式：淡入、无人理会时升级（加大音量或换声音）、最长响铃时间与贪睡
+type AlarmProfile struct {
+	FadeIn         time.Duration
+	EscalateAfter  time.Duration // 0 表示不升级
+	EscalateVolume int           // 升级后的音量百分比，0 表示不变
+	EscalateClip   string        // 升级后改放的声音，如 "synth:double_beep"，空表示不换
+	MaxDuration    time.Duration // 0 表示一直响到关闭
+	Snooze         time.Duration // 0 表示不提供贪睡
+}
+
+// NewAlarmProfile 由配置文件中的设置创建闹铃方式，越界的取值按 0 处理
+func NewAlarmProfile(c config.Alarm) AlarmProfile {
+	sec := func(n int) time.Duration {
+		if n < 0 {
+			return 0
+		}
+		return time.Duration(n) * time.Second
+	}
+	p := AlarmProfile{
+		FadeIn:        sec(c.FadeIn),
+		EscalateAfter: sec(c.EscalateAfter),
+		EscalateClip:  c.EscalateFile,
+		MaxDuration:   sec(c.MaxSeconds),
+		Snooze:        sec(c.SnoozeMinutes * 60),
+	}
+	if c.EscalateVolume > 0 {
+		p.EscalateVolume = min(c.EscalateVolume, 100)
+	}
+	return p
+}
+
+// AlarmPreset 预设的闹铃方式
+type AlarmPreset struct {
+	Name  string
+	Title string
+	Alarm config.Alarm
+}
+
+// AlarmPresets 设置界面中可选的预设，第一个与零值相同
+var AlarmPresets = []AlarmPreset{
+	{"classic", "持续循环", config.Alarm{}},
+	{"gentle", "渐强", config.Alarm{FadeIn: 10, EscalateAfter: 30, EscalateVolume: 100, MaxSeconds: 120, SnoozeMinutes: 5}},
+	{"insistent", "坚持唤醒", config.Alarm{EscalateAfter: 20, EscalateVolume: 100, EscalateFile: SynthPrefix + "double_beep", MaxSeconds: 300, SnoozeMinutes: 5}},
+}
+
+// alarmStream 按闹铃方式循环播放：淡入、到时升级、到最长时间后结束。
+// 时间按已输出的采样计算，与声卡的实际播放进度一致；由引擎在锁内调用。
+type alarmStream struct {
+	base, next beep.Streamer // 升级前后的声音，next 为 nil 时不换声音
+	ratio      float64       // 升级后音量相对原音量的倍数
+	fadeIn     int
+	escalate   int // 开始升级的采样位置，0 表示不升级
+	ramp       int
+	max        int // 0 表示不限
+	pos        int
+}
+
+// newAlarmStream 创建闹铃流；volume 为原音量百分比，next 为升级后改放的声音（可为 nil）
+func newAlarmStream(sr beep.SampleRate, clip, next *beep.Buffer, p AlarmProfile, volume int) *alarmStream {
+	gap := sr.N(loopGap)
+	s := &alarmStream{
+		base:     loopClip(clip, gap),
+		ratio:    1,
+		fadeIn:   sr.N(p.FadeIn),
+		escalate: sr.N(p.EscalateAfter),
+		ramp:     sr.N(escalateRamp),
+		max:      sr.N(p.MaxDuration),
+	}
+	if next != nil {
+		s.next = loopClip(next, gap)
+	}
+	if p.EscalateVolume > 0 && volume > 0 {
+		s.ratio = float64(p.EscalateVolume) / float64(volume)
+	}
+	return s
+}
+
+// gain 返回第 t 个采样的增益
+func (s *alarmStream) gain(t int) float64 {
+	g := 1.0
+	if t < s.fadeIn {
+		g = float64(t) / float64(s.fadeIn)
+	}
+	if s.escalate > 0 && t >= s.escalate {
+		k := 1.0
+		if d := t - s.escalate; d < s.ramp {
+			k = float64(d) / float64(s.ramp)
+		}
+		g *= 1 + (s.ratio-1)*k
+	}
+	return g
+}
+
+// Stream 实现 beep.Streamer，在升级的位置切换声音，到最长时间后结束
+func (s *alarmStream) Stream(samples [][2]float64) (int, bool) {
+	n := 0
+	for n < len(samples) {
+		seg := samples[n:]
+		if s.max > 0 {
+			if s.pos >= s.max {
+				break
+			}
+			seg = seg[:min(len(seg), s.max-s.pos)]
+		}
+		src := s.base
+		if s.escalate > 0 && s.pos < s.escalate {
+			seg = seg[:min(len(seg), s.escalate-s.pos)]
+		} else if s.escalate > 0 && s.next != nil {
+			src = s.next
+		}
+		k, _ := src.Stream(seg)
+		for i := range seg[:k] {
+			g := s.gain(s.pos + i)
+			seg[i][0] *= g
+			seg[i][1] *= g
+		}
+		s.pos += k
+		n += k
+		if k < len(seg) {
+			break
+		}
+	}
+	return n, n > 0
+}
+
+// Err 实现 beep.Streamer
+func (s *alarmStream) Err() error { return nil }
+
+// Alarm 正在响的闹铃
+type Alarm struct {
+	voice *Voice
+	// Snooze 贪睡时长，0 表示不提供贪睡
+	Snooze time.Duration
+}
+
+// Stop 确认并停止闹铃
+func (a *Alarm) Stop() {
+	if a.voice != nil {
+		a.voice.Stop()
+	}
+}
+
+// Done 返回闹铃结束（被停止或到达最长时间）时关闭的通道
+func (a *Alarm) Done() <-chan struct{} {
+	return a.voice.Done()
+}
+
+// Playing 返回闹铃是否还在响
+func (a *Alarm) Playing() bool {
+	return a.voice.Playing()
+}
+
+// PlayAlarm 按闹铃方式循环播放；已经在播放时返回 nil
+func (a *AlertPlayer) PlayAlarm(p AlarmProfile) (*Alarm, error) {
+	var next *beep.Buffer
+	if p.EscalateClip != "" && p.EscalateAfter > 0 {
+		np, err := newPlayer(p.EscalateClip, SoundDirs())
+		if err == nil {
+			err = np.LoadSound()
+		}
+		if err != nil {
+			return nil, err
+		}
+		next = np.buffer
+	}
+	var started *Voice
+	err := a.start(func(c *Channel, clip *beep.Buffer, cue Cue) *Voice {
+		cue.Loop = true
+		started = c.Play(newAlarmStream(c.e.sr, clip, next, p, cue.Volume), cue)
+		return started
+	})
+	if err != nil || started == nil {
+		return nil, err
+	}
+	return &Alarm{voice: started, Snooze: p.Snooze}, nil
+}
+
+// Alarm 按闹铃方式播放事件的提示音（忽略方案中的播放方式），事件没有设置声音时返回 nil
+func (b *Board) Alarm(e Event, p AlarmProfile) (*Alarm, error) {
+	b.mu.Lock()
+	pl := b.players[e]
+	b.mu.Unlock()
+	if pl == nil {
+		return nil, nil
+	}
+	return pl.PlayAlarm(p)
+}
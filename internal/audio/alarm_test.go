@@ -0,0 +1,97 @@
+package audio
+
+import (
+	"testing"
+	"time"
+
+	"tomato_clock/internal/config"
+
+	"github.com/faiface/beep"
+)
+
+// drainAlarm 读完闹铃流（只取左声道），每次读取 chunk 个采样
+func drainAlarm(s beep.Streamer, chunk int) []float64 {
+	var out []float64
+	buf := make([][2]float64, chunk)
+	for len(out) < 100000 {
+		n, ok := s.Stream(buf)
+		for _, v := range buf[:n] {
+			out = append(out, v[0])
+		}
+		if !ok {
+			break
+		}
+	}
+	return out
+}
+
+func TestAlarmStream(t *testing.T) {
+	p := AlarmProfile{
+		FadeIn:         100 * time.Millisecond,
+		EscalateAfter:  time.Second,
+		EscalateVolume: 100,
+		MaxDuration:    4 * time.Second,
+	}
+	// 原音量 50%，升级到 100% 即增益翻倍；升级后换成另一个声音
+	s := newAlarmStream(testRate, constClip(100, 0.5), constClip(100, 0.25), p, 50)
+	out := drainAlarm(s, 333)
+
+	if len(out) != 4000 {
+		t.Fatalf("alarm length = %d samples, want 4000", len(out))
+	}
+	for _, c := range []struct {
+		at   int
+		want float64
+	}{
+		{0, 0},
+		{50, 0.25},           // 淡入到一半
+		{99, 0.5 * 0.99},     // 淡入快结束
+		{200, 0},             // 两遍之间的间隔
+		{600, 0.5},           // 第二遍
+		{1000, 0.25},         // 升级：换成另一个声音，从头播放
+		{1050, 0.25 * 1.025}, // 音量在 2 秒内逐渐加大
+		{3200, 0},            // 另一个声音的间隔
+		{3400, 0.5},          // 音量已经翻倍
+		{3499, 0.5},
+	} {
+		if !near(out[c.at], c.want) {
+			t.Errorf("sample %d = %v, want %v", c.at, out[c.at], c.want)
+		}
+	}
+}
+
+func TestNewAlarmProfile(t *testing.T) {
+	p := NewAlarmProfile(config.Alarm{FadeIn: 10, EscalateAfter: -1, EscalateVolume: 150, MaxSeconds: 120, SnoozeMinutes: 5})
+	want := AlarmProfile{FadeIn: 10 * time.Second, EscalateVolume: 100, MaxDuration: 2 * time.Minute, Snooze: 5 * time.Minute}
+	if p != want {
+		t.Fatalf("profile = %+v, want %+v", p, want)
+	}
+	if NewAlarmProfile(AlarmPresets[0].Alarm) != (AlarmProfile{}) {
+		t.Fatal("first preset should be the classic endless loop")
+	}
+}
+
+func TestAlarmStopsAtMaxDuration(t *testing.T) {
+	rec := useRecording(t)
+	b, err := NewBoard(DefaultScheme())
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := b.Alarm(EventCountdownEnd, AlarmProfile{FadeIn: time.Second, MaxDuration: 2 * time.Second})
+	if err != nil || a == nil {
+		t.Fatalf("alarm = %v, %v", a, err)
+	}
+	// 正在响时不重复开始
+	if again, err := b.Alarm(EventCountdownEnd, AlarmProfile{}); again != nil || err != nil {
+		t.Fatalf("second alarm = %v, %v", again, err)
+	}
+	rec.Advance(3 * time.Second)
+
+	if a.Playing() || !isClosed(a.Done()) {
+		t.Fatal("alarm still playing after max duration")
+	}
+	records := rec.Records()
+	if len(records) != 1 || !records[0].Ended || records[0].Duration != 2*time.Second || !records[0].Loop {
+		t.Fatalf("records = %+v", records)
+	}
+}
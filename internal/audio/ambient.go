@@ -0,0 +1,143 @@
+package audio
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/faiface/beep"
+)
+
+// 背景音淡入淡出的时长
+const (
+	AmbienceFadeIn  = 2 * time.Second
+	AmbienceFadeOut = 3 * time.Second
+)
+
+// Ambience 专注时播放的背景音：生成的白/粉红/褐色噪音，或循环播放的声音文件（雨声、咖啡馆等），
+// 在引擎的背景音声道中播放。声音文件只在第一次播放时于后台解码一次，之后重复使用
+type Ambience struct {
+	mu     sync.Mutex
+	source string
+	volume int
+	voice  *Voice
+
+	buffer  *beep.Buffer // 解码后的声音文件，噪音或尚未解码时为 nil
+	loading bool         // 正在后台解码
+	wanted  bool         // 解码完成后是否开始播放（解码期间被淡出时取消）
+}
+
+// NewAmbience 创建背景音，source 为 "noise:pink" 这样的噪音或声音文件路径，volume 为音量百分比
+func NewAmbience(source string, volume int) *Ambience {
+	return &Ambience{source: source, volume: volume}
+}
+
+// Source 返回背景音设置
+func (a *Ambience) Source() string {
+	return a.source
+}
+
+// decodeAmbience 解码声音文件，较长的雨声、咖啡馆录音可能需要数秒，在后台调用
+func decodeAmbience(p *AlertPlayer, source string) (*beep.Buffer, error) {
+	if err := p.LoadSound(); err != nil {
+		return nil, err
+	}
+	if p.buffer.Len() == 0 {
+		return nil, fmt.Errorf("背景音文件为空: %s", source)
+	}
+	return p.buffer, nil
+}
+
+// play 用已有的声音创建无限长的背景音流并淡入，调用方需持有 a.mu
+func (a *Ambience) play() {
+	var s beep.Streamer
+	if IsNoise(a.source) {
+		s = Noise(strings.TrimPrefix(a.source, NoisePrefix), time.Now().UnixNano())
+	} else {
+		s = beep.Loop(-1, a.buffer.Streamer(0, a.buffer.Len()))
+	}
+	a.voice = Default().Channel(ChannelAmbience).PlayFadeIn(s, Cue{Clip: a.source, Volume: a.volume}, AmbienceFadeIn)
+}
+
+// Start 淡入开始播放；已经在播放时取消暂停与淡出。
+// 声音文件第一次播放时在后台解码，解码完成后才开始淡入，不阻塞调用方（界面线程）；
+// 设置无效（如没有这个内置声音）时立即返回错误，文件读取或解码失败只记录日志
+func (a *Ambience) Start() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	Default().Channel(ChannelAmbience).SetPaused(false)
+	if a.voice != nil && a.voice.Playing() {
+		a.voice.FadeTo(1, AmbienceFadeIn)
+		return nil
+	}
+	if IsNoise(a.source) || a.buffer != nil {
+		a.play()
+		return nil
+	}
+	a.wanted = true
+	if a.loading {
+		return nil
+	}
+	p, err := newPlayer(a.source, SoundDirs())
+	if err != nil {
+		a.wanted = false
+		return err
+	}
+	a.loading = true
+	go func() {
+		buf, err := decodeAmbience(p, a.source)
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		a.loading = false
+		if err != nil {
+			a.wanted = false
+			log.Printf("[AUDIO] 解码背景音失败: %v", err)
+			return
+		}
+		a.buffer = buf
+		if a.wanted {
+			a.wanted = false
+			a.play()
+		}
+	}()
+	return nil
+}
+
+// Playing 返回是否正在播放（含暂停、淡出中与等待解码完成）
+func (a *Ambience) Playing() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.wanted || a.voice != nil && a.voice.Playing()
+}
+
+// SetPaused 暂停或继续播放，随计时器的暂停一起调用
+func (a *Ambience) SetPaused(paused bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.voice != nil {
+		a.voice.c.SetPaused(paused)
+	}
+}
+
+// SetVolume 调整音量百分比，播放中立即生效
+func (a *Ambience) SetVolume(percent int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.volume = percent
+	if a.voice != nil {
+		a.voice.SetVolume(percent)
+	}
+}
+
+// FadeOut 在 d 内淡出并停止，暂停中时立即停止
+func (a *Ambience) FadeOut(d time.Duration) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.wanted = false
+	if a.voice != nil {
+		a.voice.FadeOut(d)
+		a.voice = nil
+	}
+}
@@ -0,0 +1,77 @@
+package audio
+
+import (
+	"testing"
+	"time"
+)
+
+// waitLoaded 等待背景音的后台解码结束
+func waitLoaded(t *testing.T, a *Ambience) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		a.mu.Lock()
+		loading := a.loading
+		a.mu.Unlock()
+		if !loading {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("ambience still decoding")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestAmbienceDecodesOnce(t *testing.T) {
+	useRecording(t)
+	a := NewAmbience(SynthPrefix+"bell", 50)
+	if err := a.Start(); err != nil {
+		t.Fatal(err)
+	}
+	waitLoaded(t, a)
+	if !a.Playing() {
+		t.Fatal("ambience should play after decoding")
+	}
+	first := a.buffer
+
+	a.FadeOut(0)
+	if err := a.Start(); err != nil {
+		t.Fatal(err)
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	// 第二次开始直接使用缓存的声音，不再解码
+	if a.loading || a.buffer != first || a.voice == nil {
+		t.Fatalf("second start should reuse the decoded buffer: loading=%v voice=%v", a.loading, a.voice)
+	}
+}
+
+func TestAmbienceFadeOutWhileDecoding(t *testing.T) {
+	useRecording(t)
+	a := NewAmbience(SynthPrefix+"bell", 50)
+	if err := a.Start(); err != nil {
+		t.Fatal(err)
+	}
+	a.FadeOut(AmbienceFadeOut)
+	waitLoaded(t, a)
+	if a.Playing() {
+		t.Fatal("ambience faded out during decoding should not start")
+	}
+}
+
+func TestAmbienceBadSource(t *testing.T) {
+	useRecording(t)
+	if err := NewAmbience(BuiltinPrefix+"nope", 50).Start(); err == nil {
+		t.Fatal("unknown builtin sound should fail immediately")
+	}
+	// 文件读不出来时在后台解码失败，不会开始播放
+	a := NewAmbience("/nonexistent/rain.mp3", 50)
+	if err := a.Start(); err != nil {
+		t.Fatal(err)
+	}
+	waitLoaded(t, a)
+	if a.Playing() {
+		t.Fatal("ambience that failed to decode should not be playing")
+	}
+}
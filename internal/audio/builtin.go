@@ -0,0 +1,46 @@
+package audio
+
+import (
+	"embed"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// builtinFS 编译进程序的默认声音，resources/sounds 缺失（如从桌面快捷方式启动）时也能播放
+//
+//go:embed sounds
+var builtinFS embed.FS
+
+// Builtins 返回内置声音的名称（不含扩展名），如 alarm、alert、tick
+func Builtins() []string {
+	entries, _ := builtinFS.ReadDir("sounds")
+	var names []string
+	for _, e := range entries {
+		names = append(names, strings.TrimSuffix(e.Name(), path.Ext(e.Name())))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// IsBuiltin 判断是否有该名称的内置声音
+func IsBuiltin(name string) bool {
+	for _, n := range Builtins() {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// builtinSound 返回内置声音的文件名与内容
+func builtinSound(name string) (string, []byte, error) {
+	for _, ext := range SupportedExtensions {
+		file := name + ext
+		if data, err := builtinFS.ReadFile(path.Join("sounds", file)); err == nil {
+			return file, data, nil
+		}
+	}
+	return "", nil, fmt.Errorf("没有名为 %s 的内置声音", name)
+}
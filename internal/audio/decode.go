@@ -0,0 +1,131 @@
+package audio
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/faiface/beep"
+	"github.com/faiface/beep/flac"
+	"github.com/faiface/beep/mp3"
+	"github.com/faiface/beep/vorbis"
+	"github.com/faiface/beep/wav"
+)
+
+// SampleRate 扬声器的采样率，加载的声音都会重采样到该采样率
+const SampleRate beep.SampleRate = 48000
+
+// 支持的音频格式
+const (
+	FormatMP3  = "mp3"
+	FormatWAV  = "wav"
+	FormatOGG  = "ogg"
+	FormatFLAC = "flac"
+)
+
+// SupportedExtensions 支持的音频文件扩展名，按查找默认提示音时的优先顺序排列
+var SupportedExtensions = []string{".mp3", ".wav", ".ogg", ".flac"}
+
+// ErrUnsupportedFormat 无法识别的音频格式
+var ErrUnsupportedFormat = errors.New("不支持的音频格式，仅支持 MP3、WAV、OGG/Vorbis 与 FLAC")
+
+// formatByExt 根据扩展名判断格式，无法识别时返回空字符串
+func formatByExt(name string) string {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".mp3":
+		return FormatMP3
+	case ".wav", ".wave":
+		return FormatWAV
+	case ".ogg", ".oga":
+		return FormatOGG
+	case ".flac":
+		return FormatFLAC
+	}
+	return ""
+}
+
+// formatByMagic 根据文件头判断格式，无法识别时返回空字符串
+func formatByMagic(data []byte) string {
+	switch {
+	case len(data) >= 12 && string(data[:4]) == "RIFF" && string(data[8:12]) == "WAVE":
+		return FormatWAV
+	case bytes.HasPrefix(data, []byte("OggS")):
+		return FormatOGG
+	case bytes.HasPrefix(data, []byte("fLaC")):
+		return FormatFLAC
+	case bytes.HasPrefix(data, []byte("ID3")):
+		return FormatMP3
+	case len(data) >= 2 && data[0] == 0xFF && data[1]&0xE0 == 0xE0:
+		// 没有 ID3 标签的 MP3 以帧同步字开头
+		return FormatMP3
+	}
+	return ""
+}
+
+// DetectFormat 判断音频格式：优先根据文件头识别，文件头无法识别时再看扩展名
+func DetectFormat(name string, data []byte) (string, error) {
+	if f := formatByMagic(data); f != "" {
+		return f, nil
+	}
+	if f := formatByExt(name); f != "" {
+		return f, nil
+	}
+	return "", fmt.Errorf("%s: %w", filepath.Base(name), ErrUnsupportedFormat)
+}
+
+// Decode 解码音频数据并重采样到 SampleRate，name 用于判断格式与错误信息
+func Decode(name string, data []byte) (*beep.Buffer, error) {
+	format, err := DetectFormat(name, data)
+	if err != nil {
+		return nil, err
+	}
+	rc := io.NopCloser(bytes.NewReader(data))
+	var (
+		streamer beep.StreamSeekCloser
+		f        beep.Format
+	)
+	switch format {
+	case FormatMP3:
+		streamer, f, err = mp3.Decode(rc)
+	case FormatWAV:
+		streamer, f, err = wav.Decode(rc)
+	case FormatOGG:
+		streamer, f, err = vorbis.Decode(rc)
+	case FormatFLAC:
+		streamer, f, err = flac.Decode(rc)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("解码 %s 失败（%s）: %w", filepath.Base(name), strings.ToUpper(format), err)
+	}
+	defer streamer.Close()
+
+	var s beep.Streamer = streamer
+	if f.SampleRate != SampleRate {
+		s = beep.Resample(4, f.SampleRate, SampleRate, streamer)
+	}
+	out := f
+	out.SampleRate = SampleRate
+	buffer := beep.NewBuffer(out)
+	buffer.Append(s)
+	if err := streamer.Err(); err != nil {
+		return nil, fmt.Errorf("解码 %s 失败: %w", filepath.Base(name), err)
+	}
+	if buffer.Len() == 0 {
+		return nil, fmt.Errorf("%s 中没有音频数据", filepath.Base(name))
+	}
+	return buffer, nil
+}
+
+// FindSound 在 dir 中查找名为 base 的提示音（如 alert.mp3、alert.wav），找不到时返回空字符串
+func FindSound(dir, base string) string {
+	for _, ext := range SupportedExtensions {
+		path := filepath.Join(dir, base+ext)
+		if fileExists(path) {
+			return path
+		}
+	}
+	return ""
+}
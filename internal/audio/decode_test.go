@@ -0,0 +1,87 @@
+package audio
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/faiface/beep"
+	"github.com/faiface/beep/wav"
+)
+
+// memFile 实现 io.WriteSeeker，用于把 wav.Encode 的结果写到内存
+type memFile struct {
+	buf []byte
+	pos int
+}
+
+func (m *memFile) Write(p []byte) (int, error) {
+	if need := m.pos + len(p); need > len(m.buf) {
+		m.buf = append(m.buf, make([]byte, need-len(m.buf))...)
+	}
+	copy(m.buf[m.pos:], p)
+	m.pos += len(p)
+	return len(p), nil
+}
+
+func (m *memFile) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+		m.pos = int(offset)
+	case io.SeekCurrent:
+		m.pos += int(offset)
+	case io.SeekEnd:
+		m.pos = len(m.buf) + int(offset)
+	}
+	return int64(m.pos), nil
+}
+
+func TestDetectFormat(t *testing.T) {
+	cases := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"a.wav", []byte("RIFF\x00\x00\x00\x00WAVEfmt "), FormatWAV},
+		{"a.mp3", []byte("OggS\x00\x02"), FormatOGG}, // 文件头优先于扩展名
+		{"a.bin", []byte("fLaC\x00\x00\x00\x22"), FormatFLAC},
+		{"a.bin", []byte("ID3\x04\x00"), FormatMP3},
+		{"a.bin", []byte{0xFF, 0xFB, 0x90, 0x00}, FormatMP3},
+		{"a.OGG", []byte("????"), FormatOGG}, // 文件头无法识别时看扩展名
+	}
+	for _, c := range cases {
+		got, err := DetectFormat(c.name, c.data)
+		if err != nil || got != c.want {
+			t.Errorf("DetectFormat(%s) = %q, %v; want %q", c.name, got, err, c.want)
+		}
+	}
+	if _, err := DetectFormat("a.aac", []byte("????")); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Fatalf("expected ErrUnsupportedFormat, got %v", err)
+	}
+}
+
+func TestDecodeResamples(t *testing.T) {
+	// 0.5 秒 44100Hz 的静音 WAV
+	src := beep.Format{SampleRate: 44100, NumChannels: 2, Precision: 2}
+	var f memFile
+	if err := wav.Encode(&f, beep.Take(22050, beep.Silence(-1)), src); err != nil {
+		t.Fatal(err)
+	}
+
+	buf, err := Decode("alert.wav", f.buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if buf.Format().SampleRate != SampleRate {
+		t.Fatalf("sample rate = %d", buf.Format().SampleRate)
+	}
+	// 重采样后时长不变：0.5 秒 = 24000 个采样，允许少量误差
+	if n := buf.Len(); n < 23900 || n > 24100 {
+		t.Fatalf("len = %d, want about 24000", n)
+	}
+
+	if _, err := Decode("broken.wav", append([]byte("RIFF\x00\x00\x00\x00WAVE"), bytes.Repeat([]byte{0}, 8)...)); err == nil {
+		t.Fatal("expected decode error")
+	}
+}
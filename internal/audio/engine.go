@@ -0,0 +1,455 @@
+package audio
+
+import (
+	"sync"
+	"time"
+
+	"github.com/faiface/beep"
+)
+
+// 混音器中的各路声音，每路同一时间只播放一个声音，播放新声音会替换旧的
+const (
+	ChannelAlert    = "alert"    // 倒计时结束、休息结束等提示音
+	ChannelHint     = "hint"     // 随机正念提示音
+	ChannelAmbience = "ambience" // 背景音
+	ChannelTick     = "tick"     // 滴答声
+)
+
+// Channels 引擎中的全部声道
+var Channels = []string{ChannelAlert, ChannelHint, ChannelAmbience, ChannelTick}
+
+// duckUnder 声道让位的规则：其中列出的声道有声音时，该声道自动静音，避免滴答声与提示音混在一起
+var duckUnder = map[string][]string{
+	ChannelTick: {ChannelAlert, ChannelHint},
+}
+
+// duckTime 让位静音与恢复的过渡时长，避免爆音
+const duckTime = 50 * time.Millisecond
+
+// Engine 音频引擎：用一个 beep.Mixer 混合各路声音，本身是一个 beep.Streamer，
+// 交给扬声器（或其它输出）拉取采样。所有状态都由 mu 保护，
+// 播放结束由 Stream 在读完最后一个采样时判定，不依赖按时长估算的 Sleep。
+type Engine struct {
+	mu       sync.Mutex
+	sr       beep.SampleRate
+	mixer    beep.Mixer
+	channels map[string]*Channel
+	closed   bool
+	nextID   int
+	observer Observer
+}
+
+// Cue 一次播放的说明：由哪个事件触发、播放什么、音量与是否循环，供录制后端记录
+type Cue struct {
+	Event   string // 触发的事件，如 countdown_end；与事件无关的声音为空
+	Clip    string // 声音名称：文件路径、builtin:alert、synth:bell 或 noise:pink
+	Channel string // 所在声道，由声道填写
+	Volume  int    // 音量百分比
+	Loop    bool   // 是否循环播放
+}
+
+// Observer 接收引擎中声音的开始与结束，id 区分同一个 Cue 的多次播放。
+// 在引擎持有锁时调用，实现中不能再调用引擎的方法。
+type Observer interface {
+	VoiceStarted(id int, cue Cue)
+	VoiceEnded(id int, played time.Duration)
+}
+
+// SetObserver 设置播放记录的接收者，nil 表示不记录
+func (e *Engine) SetObserver(o Observer) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.observer = o
+}
+
+// NewEngine 创建采样率为 sr 的引擎
+func NewEngine(sr beep.SampleRate) *Engine {
+	e := &Engine{sr: sr, channels: map[string]*Channel{}}
+	for _, name := range Channels {
+		c := &Channel{e: e, name: name, volume: 100, duck: 1}
+		e.channels[name] = c
+		e.mixer.Add(c)
+	}
+	for name, others := range duckUnder {
+		for _, o := range others {
+			e.channels[name].duckUnder = append(e.channels[name].duckUnder, e.channels[o])
+		}
+	}
+	return e
+}
+
+// SampleRate 返回引擎的采样率
+func (e *Engine) SampleRate() beep.SampleRate {
+	return e.sr
+}
+
+// Channel 返回指定的声道，名称未知时返回 nil
+func (e *Engine) Channel(name string) *Channel {
+	return e.channels[name]
+}
+
+// Stream 实现 beep.Streamer，混合各路声音；Shutdown 之后结束
+func (e *Engine) Stream(samples [][2]float64) (int, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.closed {
+		return 0, false
+	}
+	return e.mixer.Stream(samples)
+}
+
+// Err 实现 beep.Streamer
+func (e *Engine) Err() error { return nil }
+
+// Shutdown 停止全部声音，之后的播放请求都被忽略
+func (e *Engine) Shutdown() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.closed {
+		return
+	}
+	e.closed = true
+	for _, c := range e.channels {
+		c.stopLocked()
+	}
+}
+
+// Closed 返回引擎是否已经关闭
+func (e *Engine) Closed() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.closed
+}
+
+// Channel 引擎中的一路声音，方法都可以在任意协程中调用
+type Channel struct {
+	e      *Engine
+	name   string
+	volume int // 声道音量百分比，与声音自身的音量相乘
+	paused bool
+	voice  *Voice
+
+	duckUnder []*Channel // 这些声道有声音时本声道让位静音
+	duck      float64    // 让位的增益，1 为不让位
+}
+
+// Name 返回声道名称
+func (c *Channel) Name() string {
+	return c.name
+}
+
+// Voice 声道中正在播放的一个声音，用于单独停止、调节音量或等待播放结束
+type Voice struct {
+	c      *Channel
+	id     int
+	cue    Cue
+	s      beep.Streamer
+	gain   float64
+	fader  *Fader
+	played int // 已播放的采样数
+	done   chan struct{}
+	closed bool
+}
+
+func (c *Channel) newVoice(s beep.Streamer, cue Cue) *Voice {
+	cue.Channel = c.name
+	return &Voice{c: c, cue: cue, s: s, gain: gainOf(cue.Volume), done: make(chan struct{})}
+}
+
+// Play 在声道中播放 s（有限流播放完自动结束，无限流需要 Stop），替换声道中原来的声音。
+// cue.Volume 为该声音的音量百分比；引擎已关闭时返回的 Voice 已经结束。
+func (c *Channel) Play(s beep.Streamer, cue Cue) *Voice {
+	return c.play(c.newVoice(s, cue))
+}
+
+// PlayFadeIn 与 Play 相同，但在 d 内从静音淡入
+func (c *Channel) PlayFadeIn(s beep.Streamer, cue Cue, d time.Duration) *Voice {
+	v := c.newVoice(s, cue)
+	v.fader = NewFader(s)
+	v.fader.FadeTo(1, c.e.sr.N(d))
+	return c.play(v)
+}
+
+func (c *Channel) play(v *Voice) *Voice {
+	c.e.mu.Lock()
+	defer c.e.mu.Unlock()
+	if c.e.closed {
+		v.finishLocked()
+		return v
+	}
+	c.stopLocked()
+	c.e.nextID++
+	v.id = c.e.nextID
+	c.voice = v
+	if c.e.observer != nil {
+		c.e.observer.VoiceStarted(v.id, v.cue)
+	}
+	return v
+}
+
+// PlayClip 完整播放一次 clip
+func (c *Channel) PlayClip(clip *beep.Buffer, cue Cue) *Voice {
+	return c.Play(clip.Streamer(0, clip.Len()), cue)
+}
+
+// PlayFor 播放 clip 的前 d 时长，clip 比 d 短时播放完即结束
+func (c *Channel) PlayFor(clip *beep.Buffer, d time.Duration, cue Cue) *Voice {
+	return c.Play(beep.Take(c.e.sr.N(d), clip.Streamer(0, clip.Len())), cue)
+}
+
+// Loop 循环播放 clip，每遍之间间隔 gap，直到 Stop
+func (c *Channel) Loop(clip *beep.Buffer, gap time.Duration, cue Cue) *Voice {
+	cue.Loop = true
+	return c.Play(loopClip(clip, c.e.sr.N(gap)), cue)
+}
+
+// loopClip 无限循环 clip，每遍之后插入 gap 个采样的静音
+func loopClip(clip *beep.Buffer, gap int) beep.Streamer {
+	return beep.Iterate(func() beep.Streamer {
+		return beep.Seq(clip.Streamer(0, clip.Len()), beep.Silence(gap))
+	})
+}
+
+// Stop 停止声道中的声音，不影响其它声道
+func (c *Channel) Stop() {
+	c.e.mu.Lock()
+	defer c.e.mu.Unlock()
+	c.stopLocked()
+}
+
+func (c *Channel) stopLocked() {
+	if c.voice != nil {
+		c.voice.finishLocked()
+	}
+}
+
+// Playing 返回声道中是否有声音在播放（含暂停中）
+func (c *Channel) Playing() bool {
+	c.e.mu.Lock()
+	defer c.e.mu.Unlock()
+	return c.voice != nil
+}
+
+// SetVolume 设置声道音量百分比（0-100），立即生效
+func (c *Channel) SetVolume(percent int) {
+	c.e.mu.Lock()
+	defer c.e.mu.Unlock()
+	c.volume = percent
+}
+
+// Volume 返回声道音量百分比
+func (c *Channel) Volume() int {
+	c.e.mu.Lock()
+	defer c.e.mu.Unlock()
+	return c.volume
+}
+
+// SetPaused 暂停或继续声道，暂停时输出静音，声音的进度保持不变
+func (c *Channel) SetPaused(paused bool) {
+	c.e.mu.Lock()
+	defer c.e.mu.Unlock()
+	c.paused = paused
+}
+
+// Paused 返回声道是否暂停
+func (c *Channel) Paused() bool {
+	c.e.mu.Lock()
+	defer c.e.mu.Unlock()
+	return c.paused
+}
+
+// Stream 实现 beep.Streamer，由引擎在持有锁时调用；声道本身永不结束
+func (c *Channel) Stream(samples [][2]float64) (int, bool) {
+	v := c.voice
+	n := 0
+	if v != nil && !c.paused {
+		var ok bool
+		n, ok = v.stream(samples)
+		v.played += n
+		g := v.gain * gainOf(c.volume)
+		target, step := 1.0, 1/float64(max(c.e.sr.N(duckTime), 1))
+		if c.ducked() {
+			target = 0
+		}
+		for i := range samples[:n] {
+			if c.duck < target {
+				c.duck = min(c.duck+step, target)
+			} else if c.duck > target {
+				c.duck = max(c.duck-step, target)
+			}
+			samples[i][0] *= g * c.duck
+			samples[i][1] *= g * c.duck
+		}
+		if !ok || n < len(samples) {
+			v.finishLocked()
+		}
+	}
+	for i := range samples[n:] {
+		samples[n+i] = [2]float64{}
+	}
+	return len(samples), true
+}
+
+// Err 实现 beep.Streamer
+func (c *Channel) Err() error { return nil }
+
+// ducked 返回是否需要让位：需要让位的声道中有正在播放（未暂停）的声音
+func (c *Channel) ducked() bool {
+	for _, o := range c.duckUnder {
+		if o.voice != nil && !o.paused {
+			return true
+		}
+	}
+	return false
+}
+
+func (v *Voice) stream(samples [][2]float64) (int, bool) {
+	if v.fader != nil {
+		return v.fader.Stream(samples)
+	}
+	return v.s.Stream(samples)
+}
+
+// finishLocked 结束播放并通知等待者，调用时持有引擎的锁
+func (v *Voice) finishLocked() {
+	if v.closed {
+		return
+	}
+	v.closed = true
+	close(v.done)
+	if v.c.voice == v {
+		v.c.voice = nil
+	}
+	if o := v.c.e.observer; o != nil && v.id != 0 {
+		o.VoiceEnded(v.id, v.c.e.sr.D(v.played))
+	}
+}
+
+// Cue 返回播放说明
+func (v *Voice) Cue() Cue {
+	return v.cue
+}
+
+// Done 返回播放结束（播放完、被停止或被替换）时关闭的通道
+func (v *Voice) Done() <-chan struct{} {
+	return v.done
+}
+
+// Playing 返回是否仍在播放
+func (v *Voice) Playing() bool {
+	v.c.e.mu.Lock()
+	defer v.c.e.mu.Unlock()
+	return !v.closed
+}
+
+// Stop 停止该声音；声道已经在播放其它声音时不受影响
+func (v *Voice) Stop() {
+	v.c.e.mu.Lock()
+	defer v.c.e.mu.Unlock()
+	v.finishLocked()
+}
+
+// SetVolume 调整该声音的音量百分比
+func (v *Voice) SetVolume(percent int) {
+	v.c.e.mu.Lock()
+	defer v.c.e.mu.Unlock()
+	v.gain = gainOf(percent)
+}
+
+// FadeTo 在 d 内把声音的增益平滑变化到 target（0-1）；第一次调用时从静音开始，用于淡入
+func (v *Voice) FadeTo(target float64, d time.Duration) {
+	v.c.e.mu.Lock()
+	defer v.c.e.mu.Unlock()
+	if v.fader == nil {
+		v.fader = NewFader(v.s)
+	}
+	v.fader.FadeTo(target, v.c.e.sr.N(d))
+}
+
+// FadeOut 在 d 内淡出，之后声音结束；声道暂停中时立即结束
+func (v *Voice) FadeOut(d time.Duration) {
+	v.c.e.mu.Lock()
+	defer v.c.e.mu.Unlock()
+	if v.c.paused && v.c.voice == v {
+		v.finishLocked()
+		return
+	}
+	if v.fader == nil {
+		v.fader = NewFader(v.s)
+		v.fader.FadeTo(1, 0)
+	}
+	v.fader.FadeOut(v.c.e.sr.N(d))
+}
+
+// gainOf 把音量百分比换算为线性增益
+func gainOf(percent int) float64 {
+	switch {
+	case percent <= 0:
+		return 0
+	case percent >= 100:
+		return 1
+	}
+	return float64(percent) / 100
+}
+
+// Fader 平滑改变音量的包装流，用于淡入淡出；不加锁，由 Voice 在引擎的锁内调用
+type Fader struct {
+	s      beep.Streamer
+	gain   float64
+	target float64
+	step   float64 // 每个采样的变化量
+	ending bool    // 淡出到 0 后结束
+}
+
+// NewFader 创建从静音开始的 Fader
+func NewFader(s beep.Streamer) *Fader {
+	return &Fader{s: s}
+}
+
+// FadeTo 在 n 个采样内把增益线性变化到 target，n <= 0 时立即生效
+func (f *Fader) FadeTo(target float64, n int) {
+	f.target = target
+	f.ending = false
+	if n <= 0 {
+		f.gain = target
+		f.step = 0
+		return
+	}
+	f.step = (target - f.gain) / float64(n)
+}
+
+// FadeOut 在 n 个采样内淡出，之后流结束
+func (f *Fader) FadeOut(n int) {
+	f.FadeTo(0, n)
+	f.ending = true
+}
+
+// Gain 返回当前增益
+func (f *Fader) Gain() float64 {
+	return f.gain
+}
+
+// Stream 实现 beep.Streamer
+func (f *Fader) Stream(samples [][2]float64) (int, bool) {
+	if f.ending && f.gain == 0 {
+		return 0, false
+	}
+	n, ok := f.s.Stream(samples)
+	for i := 0; i < n; i++ {
+		if f.gain != f.target {
+			f.gain += f.step
+			if (f.step > 0 && f.gain > f.target) || (f.step < 0 && f.gain < f.target) || f.step == 0 {
+				f.gain = f.target
+			}
+		}
+		samples[i][0] *= f.gain
+		samples[i][1] *= f.gain
+		if f.ending && f.gain == 0 {
+			return i + 1, true
+		}
+	}
+	return n, ok
+}
+
+// Err 实现 beep.Streamer
+func (f *Fader) Err() error { return f.s.Err() }
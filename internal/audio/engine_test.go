@@ -0,0 +1,230 @@
+package audio
+
+import (
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/faiface/beep"
+)
+
+// 测试中采样率取 1000，每个采样 1 毫秒
+const testRate = beep.SampleRate(1000)
+
+// constClip 返回 n 个采样、值恒为 v 的声音
+func constClip(n int, v float64) *beep.Buffer {
+	samples := make([][2]float64, n)
+	for i := range samples {
+		samples[i] = [2]float64{v, v}
+	}
+	buf := beep.NewBuffer(beep.Format{SampleRate: testRate, NumChannels: 2, Precision: 2})
+	buf.Append(&sliceStreamer{samples: samples})
+	return buf
+}
+
+// pull 从引擎拉取 n 个采样（只取左声道），每次拉取 chunk 个
+func pull(t *testing.T, e *Engine, n, chunk int) []float64 {
+	t.Helper()
+	var out []float64
+	buf := make([][2]float64, chunk)
+	for len(out) < n {
+		k, ok := e.Stream(buf[:min(chunk, n-len(out))])
+		if !ok {
+			t.Fatal("engine stream ended")
+		}
+		for _, v := range buf[:k] {
+			out = append(out, v[0])
+		}
+	}
+	return out
+}
+
+// near 比较采样值，允许 16 位量化误差
+func near(got, want float64) bool {
+	return math.Abs(got-want) < 1e-3
+}
+
+func isClosed(ch <-chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
+
+func TestEngineClipTiming(t *testing.T) {
+	e := NewEngine(testRate)
+	v := e.Channel(ChannelAlert).PlayClip(constClip(10, 0.5), Cue{Volume: 100})
+
+	out := pull(t, e, 8, 4)
+	if !near(out[0], 0.5) || !near(out[7], 0.5) || isClosed(v.Done()) {
+		t.Fatalf("first 8 samples = %v, done = %v", out, isClosed(v.Done()))
+	}
+	// 读完最后一个采样时结束，不需要按时长估算
+	out = pull(t, e, 4, 4)
+	if !near(out[1], 0.5) || out[2] != 0 || out[3] != 0 || !isClosed(v.Done()) || v.Playing() {
+		t.Fatalf("tail = %v, done = %v", out, isClosed(v.Done()))
+	}
+	if e.Channel(ChannelAlert).Playing() {
+		t.Fatal("channel should be idle")
+	}
+
+	// PlayFor 截取前 d 时长
+	v = e.Channel(ChannelAlert).PlayFor(constClip(10, 0.5), 3*time.Millisecond, Cue{Volume: 100})
+	out = pull(t, e, 5, 5)
+	if !near(out[2], 0.5) || out[3] != 0 || !isClosed(v.Done()) {
+		t.Fatalf("play for = %v", out)
+	}
+}
+
+func TestEngineLoop(t *testing.T) {
+	e := NewEngine(testRate)
+	v := e.Channel(ChannelAlert).Loop(constClip(10, 0.5), 5*time.Millisecond, Cue{Volume: 100})
+	out := pull(t, e, 45, 7)
+	for i, got := range out {
+		want := 0.5
+		if i%15 >= 10 {
+			want = 0 // 每遍之间的间隔
+		}
+		if !near(got, want) || (want == 0 && got != 0) {
+			t.Fatalf("sample %d = %v, want %v", i, got, want)
+		}
+	}
+	v.Stop()
+	if out := pull(t, e, 10, 10); out[0] != 0 || !isClosed(v.Done()) {
+		t.Fatalf("after stop = %v", out)
+	}
+}
+
+func TestEngineChannelsIndependent(t *testing.T) {
+	e := NewEngine(testRate)
+	alert, ambience := e.Channel(ChannelAlert), e.Channel(ChannelAmbience)
+	bg := ambience.Play(NewOscillator(testRate, Triangle, 0, 1), Cue{Volume: 50}) // 恒为 -1
+	ambience.SetVolume(50)
+	a := alert.Loop(constClip(10, 0.5), 0, Cue{Volume: 100})
+
+	if out := pull(t, e, 1, 1); !near(out[0], 0.5-0.25) {
+		t.Fatalf("mixed = %v", out[0])
+	}
+	// 停止提示音不影响背景音（不再像 speaker.Clear 那样清掉全部声音）
+	a.Stop()
+	if out := pull(t, e, 1, 1); math.Abs(out[0]+0.25) > 1e-9 || !bg.Playing() {
+		t.Fatalf("after alert stop = %v", out[0])
+	}
+
+	// 暂停时输出静音，继续后从原来的位置播放
+	ambience.SetPaused(true)
+	if out := pull(t, e, 3, 3); out[0] != 0 || !bg.Playing() {
+		t.Fatalf("paused = %v", out)
+	}
+	ambience.SetPaused(false)
+
+	// 新声音替换声道中原来的声音
+	bg2 := ambience.Play(NewOscillator(testRate, Triangle, 0, 1), Cue{Volume: 100})
+	if !isClosed(bg.Done()) || !bg2.Playing() {
+		t.Fatal("old voice should be replaced")
+	}
+	// 已被替换的声音再 Stop 不影响新声音
+	bg.Stop()
+	if !bg2.Playing() {
+		t.Fatal("stale stop should not affect the new voice")
+	}
+
+	bg2.FadeOut(10 * time.Millisecond)
+	pull(t, e, 20, 4)
+	if bg2.Playing() {
+		t.Fatal("voice should end after fade out")
+	}
+}
+
+func TestEngineShutdown(t *testing.T) {
+	e := NewEngine(testRate)
+	v := e.Channel(ChannelHint).Loop(constClip(10, 0.5), 0, Cue{Volume: 100})
+	e.Shutdown()
+	if !isClosed(v.Done()) || !e.Closed() {
+		t.Fatal("shutdown should stop all voices")
+	}
+	if n, ok := e.Stream(make([][2]float64, 10)); n != 0 || ok {
+		t.Fatalf("stream after shutdown = %d, %v", n, ok)
+	}
+	if v := e.Channel(ChannelAlert).PlayClip(constClip(10, 0.5), Cue{Volume: 100}); v.Playing() {
+		t.Fatal("play after shutdown should be ignored")
+	}
+	e.Shutdown() // 重复调用无副作用
+}
+
+// TestEngineConcurrent 在输出线程拉取采样的同时从多个协程操作声道，用 -race 运行可检查数据竞争
+func TestEngineConcurrent(t *testing.T) {
+	e := NewEngine(testRate)
+	clip := constClip(20, 0.5)
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		buf := make([][2]float64, 16)
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				e.Stream(buf)
+			}
+		}
+	}()
+
+	var workers sync.WaitGroup
+	for _, name := range Channels {
+		c := e.Channel(name)
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for i := 0; i < 200; i++ {
+				var v *Voice
+				switch i % 3 {
+				case 0:
+					v = c.PlayClip(clip, Cue{Volume: 80})
+				case 1:
+					v = c.Loop(clip, time.Millisecond, Cue{Volume: 60})
+				default:
+					v = c.PlayFadeIn(NewOscillator(testRate, Sine, 50, 1), Cue{Volume: 40}, 5*time.Millisecond)
+				}
+				c.SetVolume(i % 100)
+				c.SetPaused(i%7 == 0)
+				v.SetVolume(50)
+				_ = v.Playing() && c.Playing()
+				if i%2 == 0 {
+					v.Stop()
+				} else {
+					v.FadeOut(time.Millisecond)
+				}
+			}
+		}()
+	}
+	workers.Wait()
+	e.Shutdown()
+	close(stop)
+	wg.Wait()
+}
+
+func TestFader(t *testing.T) {
+	const sr = beep.SampleRate(1000)
+	f := NewFader(NewOscillator(sr, Triangle, 0, 1)) // 恒为 -1
+	f.FadeTo(1, 100)
+	out := drain(beep.Take(200, f))
+	if math.Abs(-out[0]-0.01) > 1e-9 || math.Abs(-out[49]-0.5) > 1e-9 || -out[150] != 1 {
+		t.Fatalf("fade in = %v %v %v", out[0], out[49], out[150])
+	}
+
+	// 淡出结束后流结束，播放器会自动移除它
+	f.FadeOut(50)
+	out = drain(f)
+	if len(out) != 50 || out[len(out)-1] != 0 {
+		t.Fatalf("fade out len = %d, last = %v", len(out), out[len(out)-1])
+	}
+	if n, ok := f.Stream(make([][2]float64, 10)); n != 0 || ok {
+		t.Fatalf("stream after fade out = %d, %v", n, ok)
+	}
+}
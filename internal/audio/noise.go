@@ -0,0 +1,84 @@
+package audio
+
+import (
+	"math/rand"
+
+	"github.com/faiface/beep"
+)
+
+// NoisePrefix 生成的背景噪音的前缀，如 "noise:pink"
+const NoisePrefix = "noise:"
+
+// 噪音的颜色
+const (
+	NoiseWhite = "white" // 白噪音：各频率能量相同，类似电视雪花声
+	NoisePink  = "pink"  // 粉红噪音：低频更多，类似雨声
+	NoiseBrown = "brown" // 褐色噪音：低频为主，类似远处的瀑布、风声
+)
+
+// Noises 支持的噪音颜色
+var Noises = []string{NoiseWhite, NoisePink, NoiseBrown}
+
+// NoiseTitle 返回噪音的中文名称
+func NoiseTitle(kind string) string {
+	switch kind {
+	case NoiseWhite:
+		return "白噪音"
+	case NoisePink:
+		return "粉红噪音"
+	case NoiseBrown:
+		return "褐色噪音"
+	}
+	return kind
+}
+
+// IsNoise 判断背景音设置是否为生成的噪音
+func IsNoise(source string) bool {
+	for _, n := range Noises {
+		if source == NoisePrefix+n {
+			return true
+		}
+	}
+	return false
+}
+
+// noise 无限长的噪音流，左右声道各自独立生成以获得更开阔的声场
+type noise struct {
+	kind string
+	r    *rand.Rand
+	pink [2][3]float64 // 粉红噪音滤波器状态
+	last [2]float64    // 褐色噪音的积分值
+}
+
+// Noise 创建指定颜色的噪音流，未知颜色按白噪音处理；振幅大致在 ±0.5 以内
+func Noise(kind string, seed int64) beep.Streamer {
+	return &noise{kind: kind, r: rand.New(rand.NewSource(seed))}
+}
+
+func (n *noise) Stream(samples [][2]float64) (int, bool) {
+	for i := range samples {
+		for c := 0; c < 2; c++ {
+			w := n.r.Float64()*2 - 1
+			var v float64
+			switch n.kind {
+			case NoisePink:
+				// Paul Kellet 的简化滤波器，近似 -3dB/倍频程
+				b := &n.pink[c]
+				b[0] = 0.99765*b[0] + w*0.0990460
+				b[1] = 0.96300*b[1] + w*0.2965164
+				b[2] = 0.57000*b[2] + w*1.0526913
+				v = (b[0] + b[1] + b[2] + w*0.1848) * 0.11
+			case NoiseBrown:
+				// 带泄漏的积分，避免直流漂移
+				n.last[c] = (n.last[c] + 0.02*w) / 1.02
+				v = n.last[c] * 3.5
+			default:
+				v = w * 0.5
+			}
+			samples[i][c] = v
+		}
+	}
+	return len(samples), true
+}
+
+func (n *noise) Err() error { return nil }
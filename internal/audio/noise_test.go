@@ -0,0 +1,49 @@
+package audio
+
+import (
+	"math"
+	"testing"
+
+	"github.com/faiface/beep"
+)
+
+// lag1 返回相邻采样的自相关系数：白噪音接近 0，低频越多越接近 1
+func lag1(samples []float64) float64 {
+	var mean float64
+	for _, v := range samples {
+		mean += v
+	}
+	mean /= float64(len(samples))
+	var num, den float64
+	for i, v := range samples {
+		d := v - mean
+		den += d * d
+		if i > 0 {
+			num += d * (samples[i-1] - mean)
+		}
+	}
+	return num / den
+}
+
+func TestNoiseColors(t *testing.T) {
+	corr := map[string]float64{}
+	for _, kind := range Noises {
+		samples := drain(beep.Take(int(SampleRate), Noise(kind, 1)))
+		if len(samples) != int(SampleRate) {
+			t.Fatalf("%s: len = %d", kind, len(samples))
+		}
+		if p := peakOf(samples); p == 0 || p > 1 {
+			t.Fatalf("%s: peak = %v", kind, p)
+		}
+		corr[kind] = lag1(samples)
+	}
+	if math.Abs(corr[NoiseWhite]) > 0.05 {
+		t.Errorf("white lag-1 = %v", corr[NoiseWhite])
+	}
+	if !(corr[NoiseWhite] < corr[NoisePink] && corr[NoisePink] < corr[NoiseBrown] && corr[NoiseBrown] > 0.9) {
+		t.Errorf("lag-1 correlations = %v", corr)
+	}
+	if !IsNoise(NoisePrefix+NoisePink) || IsNoise("rain.mp3") || IsNoise(NoisePrefix+"blue") {
+		t.Error("IsNoise")
+	}
+}
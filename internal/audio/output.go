@@ -0,0 +1,219 @@
+package audio
+
+import (
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// Output 音频输出后端：从引擎拉取采样并送到某处（扬声器、丢弃或记录）
+type Output interface {
+	// Start 开始从 e 拉取采样，打不开输出设备时返回错误
+	Start(e *Engine) error
+	// Close 停止拉取并释放设备
+	Close()
+}
+
+// NullOutput 不发声的输出：按真实时间拉取并丢弃采样，使播放照常按时结束。
+// 用于没有声卡的机器或 CI。
+type NullOutput struct {
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewNullOutput 创建静音输出
+func NewNullOutput() *NullOutput {
+	return &NullOutput{}
+}
+
+// Start 实现 Output
+func (o *NullOutput) Start(e *Engine) error {
+	o.stop = make(chan struct{})
+	o.wg.Add(1)
+	go func(stop <-chan struct{}) {
+		defer o.wg.Done()
+		ticker := time.NewTicker(20 * time.Millisecond)
+		defer ticker.Stop()
+		last := time.Now()
+		buf := make([][2]float64, 512)
+		for {
+			select {
+			case <-stop:
+				return
+			case now := <-ticker.C:
+				if !drainEngine(e, e.SampleRate().N(now.Sub(last)), buf) {
+					return
+				}
+				last = now
+			}
+		}
+	}(o.stop)
+	return nil
+}
+
+// Close 实现 Output
+func (o *NullOutput) Close() {
+	if o.stop != nil {
+		close(o.stop)
+		o.wg.Wait()
+		o.stop = nil
+	}
+}
+
+// drainEngine 从引擎拉取 n 个采样并丢弃，引擎已关闭时返回 false
+func drainEngine(e *Engine, n int, buf [][2]float64) bool {
+	for n > 0 {
+		k := min(n, len(buf))
+		if _, ok := e.Stream(buf[:k]); !ok {
+			return false
+		}
+		n -= k
+	}
+	return true
+}
+
+// Record 录制后端记录的一次播放
+type Record struct {
+	Cue
+	Duration time.Duration // 实际播放的时长，结束前为到目前为止的时长
+	Ended    bool          // 是否已经结束（播放完、被停止或被替换）
+}
+
+// Recording 记录“本来会播放什么”的输出，不发声也不按真实时间推进：
+// 由 Advance 手动推进时间，便于测试断言某个事件触发了哪个声音、播放了多久
+type Recording struct {
+	mu      sync.Mutex
+	e       *Engine
+	records []Record
+	index   map[int]int // voice id -> records 下标
+}
+
+// NewRecording 创建录制输出
+func NewRecording() *Recording {
+	return &Recording{index: map[int]int{}}
+}
+
+// Start 实现 Output
+func (r *Recording) Start(e *Engine) error {
+	r.mu.Lock()
+	r.e = e
+	r.mu.Unlock()
+	e.SetObserver(r)
+	return nil
+}
+
+// Close 实现 Output
+func (r *Recording) Close() {
+	r.mu.Lock()
+	e := r.e
+	r.e = nil
+	r.mu.Unlock()
+	if e != nil {
+		e.SetObserver(nil)
+	}
+}
+
+// Advance 让引擎向前播放 d 时长
+func (r *Recording) Advance(d time.Duration) {
+	r.mu.Lock()
+	e := r.e
+	r.mu.Unlock()
+	if e != nil {
+		drainEngine(e, e.SampleRate().N(d), make([][2]float64, 512))
+	}
+}
+
+// VoiceStarted 实现 Observer
+func (r *Recording) VoiceStarted(id int, cue Cue) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.index[id] = len(r.records)
+	r.records = append(r.records, Record{Cue: cue})
+}
+
+// VoiceEnded 实现 Observer
+func (r *Recording) VoiceEnded(id int, played time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if i, ok := r.index[id]; ok {
+		r.records[i].Duration = played
+		r.records[i].Ended = true
+		delete(r.index, id)
+	}
+}
+
+// Records 返回到目前为止的播放记录，按开始顺序排列
+func (r *Recording) Records() []Record {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Record(nil), r.records...)
+}
+
+// Reset 清空记录
+func (r *Recording) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records = nil
+	r.index = map[int]int{}
+}
+
+// 全局引擎及其输出，第一次播放时初始化
+var (
+	defaultMu     sync.Mutex
+	defaultEngine *Engine
+	defaultOutput Output
+)
+
+// Default 返回全局引擎。第一次调用时打开输出：默认使用扬声器，
+// 环境变量 TOMATO_CLOCK_AUDIO=null 或打不开声音设备时改用静音输出，程序照常运行
+func Default() *Engine {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	if defaultEngine != nil {
+		return defaultEngine
+	}
+	out := defaultOutput
+	if out == nil {
+		if os.Getenv("TOMATO_CLOCK_AUDIO") == "null" {
+			out = NewNullOutput()
+		} else {
+			out = NewSpeakerOutput()
+		}
+	}
+	e := NewEngine(SampleRate)
+	defaultEngine, defaultOutput = e, startOutput(out, e)
+	return e
+}
+
+// startOutput 启动输出，失败时退回静音输出
+func startOutput(out Output, e *Engine) Output {
+	if err := out.Start(e); err != nil {
+		log.Printf("[AUDIO] 无法打开声音输出，改为静音输出: %v", err)
+		out = NewNullOutput()
+		_ = out.Start(e)
+	}
+	return out
+}
+
+// UseOutput 切换全局引擎的输出后端（如无界面运行或测试时使用录制输出），正在播放的声音继续
+func UseOutput(out Output) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	if defaultEngine == nil {
+		defaultOutput = out
+		return
+	}
+	defaultOutput.Close()
+	defaultOutput = startOutput(out, defaultEngine)
+}
+
+// Shutdown 停止全部声音并关闭输出，程序退出前调用
+func Shutdown() {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	if defaultEngine != nil {
+		defaultEngine.Shutdown()
+		defaultOutput.Close()
+	}
+}
@@ -0,0 +1,35 @@
+package audio
+
+import (
+	"time"
+
+	"github.com/faiface/beep/speaker"
+)
+
+// SpeakerOutput 通过系统声卡播放的输出
+type SpeakerOutput struct {
+	started bool
+}
+
+// NewSpeakerOutput 创建扬声器输出
+func NewSpeakerOutput() *SpeakerOutput {
+	return &SpeakerOutput{}
+}
+
+// Start 实现 Output：初始化扬声器（缓冲区约 0.1 秒）并播放引擎的混音
+func (o *SpeakerOutput) Start(e *Engine) error {
+	if err := speaker.Init(e.SampleRate(), e.SampleRate().N(100*time.Millisecond)); err != nil {
+		return err
+	}
+	speaker.Play(e)
+	o.started = true
+	return nil
+}
+
+// Close 实现 Output
+func (o *SpeakerOutput) Close() {
+	if o.started {
+		speaker.Close()
+		o.started = false
+	}
+}
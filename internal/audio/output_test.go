@@ -0,0 +1,75 @@
+package audio
+
+import (
+	"testing"
+	"time"
+)
+
+// useRecording 让全局引擎改用录制输出，测试结束后恢复
+func useRecording(t *testing.T) *Recording {
+	t.Helper()
+	rec := NewRecording()
+	defaultMu.Lock()
+	oldEngine, oldOutput := defaultEngine, defaultOutput
+	defaultEngine, defaultOutput = nil, rec
+	defaultMu.Unlock()
+	t.Cleanup(func() {
+		Shutdown()
+		defaultMu.Lock()
+		defaultEngine, defaultOutput = oldEngine, oldOutput
+		defaultMu.Unlock()
+	})
+	return rec
+}
+
+func TestCountdownEndTriggersAlertLoop(t *testing.T) {
+	rec := useRecording(t)
+	b, err := NewBoard(DefaultScheme())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Play(EventCountdownEnd); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Play(EventRandomHint); err != nil {
+		t.Fatal(err)
+	}
+	rec.Advance(3 * time.Second)
+
+	records := rec.Records()
+	if len(records) != 2 {
+		t.Fatalf("records = %+v", records)
+	}
+	want := Cue{Event: string(EventCountdownEnd), Clip: BuiltinPrefix + "alert", Channel: ChannelAlert, Volume: 100, Loop: true}
+	if records[0].Cue != want || records[0].Ended {
+		t.Fatalf("countdown end = %+v", records[0])
+	}
+	// 随机提示音在单独的声道，不会替换倒计时结束的提示音
+	if hint := records[1]; hint.Event != string(EventRandomHint) || hint.Channel != ChannelHint || hint.Loop {
+		t.Fatalf("random hint = %+v", hint)
+	}
+
+	// 关闭提示框时停止，只停止这一个声音
+	b.Stop(EventCountdownEnd)
+	records = rec.Records()
+	if !records[0].Ended || records[0].Duration != 3*time.Second || records[1].Ended {
+		t.Fatalf("after stop = %+v", records)
+	}
+}
+
+func TestNullOutputKeepsTime(t *testing.T) {
+	e := NewEngine(testRate)
+	out := NewNullOutput()
+	if err := out.Start(e); err != nil {
+		t.Fatal(err)
+	}
+	defer out.Close()
+
+	// 没有声卡时播放照常按时结束
+	v := e.Channel(ChannelAlert).PlayClip(constClip(50, 0.5), Cue{Volume: 100})
+	select {
+	case <-v.Done():
+	case <-time.After(2 * time.Second):
+		t.Fatal("clip did not finish with null output")
+	}
+}
@@ -2,159 +2,183 @@ package audio
 
 import (
 	"os"
+	"sync"
 	"time"
 
 	"github.com/faiface/beep"
-	"github.com/faiface/beep/mp3"
-	"github.com/faiface/beep/speaker"
 )
 
-// AlertPlayer 表示一个提示音播放器
+// loopGap 循环播放时每遍之间的间隔
+const loopGap = 500 * time.Millisecond
+
+// AlertPlayer 表示一个提示音播放器，在引擎的某个声道中播放同一个声音
 type AlertPlayer struct {
-	isInitialized bool
-	soundFile     string
-	buffer        *beep.Buffer
-	isPlaying     bool
-	stopChan      chan struct{}
+	mu        sync.Mutex
+	engine    *Engine
+	channel   string
+	event     string // 触发播放的事件，用于播放记录
+	clip      string // 声音设置，如 builtin:alert；为空时使用 soundFile
+	soundFile string
+	data      []byte // 内存中的声音数据，非空时不读取 soundFile
+	buffer    *beep.Buffer
+	volume    int // 音量百分比，0-100
+	voice     *Voice
 }
 
-// NewAlertPlayer 创建一个新的提示音播放器
+// NewAlertPlayer 创建一个新的提示音播放器，默认在提示音声道播放
 func NewAlertPlayer(soundFile string) *AlertPlayer {
 	return &AlertPlayer{
+		channel:   ChannelAlert,
 		soundFile: soundFile,
-		stopChan:  make(chan struct{}),
+		volume:    100,
 	}
 }
 
-// Init 初始化音频播放器
+// NewAlertPlayerFromData 创建播放内存中声音数据的播放器（如内置声音），name 用于识别格式与错误信息
+func NewAlertPlayerFromData(name string, data []byte) *AlertPlayer {
+	p := NewAlertPlayer(name)
+	p.data = data
+	return p
+}
+
+// NewAlertPlayerFromBuffer 创建播放已生成采样的播放器（如合成音色）
+func NewAlertPlayerFromBuffer(name string, buffer *beep.Buffer) *AlertPlayer {
+	p := NewAlertPlayer(name)
+	p.buffer = buffer
+	return p
+}
+
+// Init 连接到全局引擎；没有声音设备时引擎使用静音输出，不会返回错误
 func (a *AlertPlayer) Init() error {
-	if a.isInitialized {
-		return nil
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.engine == nil {
+		a.engine = Default()
 	}
+	return nil
+}
 
-	// 初始化音频播放器，采样率44100，缓冲区大小512
-	err := speaker.Init(48000, 5120)
-	if err != nil {
-		return err
+// SetChannel 设置播放所用的声道，见 Channel* 常量
+func (a *AlertPlayer) SetChannel(name string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.channel = name
+}
+
+// SetCue 设置播放记录中的事件与声音名称
+func (a *AlertPlayer) SetCue(event, clip string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.event, a.clip = event, clip
+}
+
+// cueLocked 返回本次播放的说明
+func (a *AlertPlayer) cueLocked() Cue {
+	clip := a.clip
+	if clip == "" {
+		clip = a.soundFile
 	}
+	return Cue{Event: a.event, Clip: clip, Volume: a.volume}
+}
 
-	a.isInitialized = true
-	return nil
+// SetVolume 设置音量百分比（0-100），正在播放的声音立即生效
+func (a *AlertPlayer) SetVolume(percent int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.volume = percent
+	if a.voice != nil {
+		a.voice.SetVolume(percent)
+	}
 }
 
-// LoadSound 加载提示音文件
+// LoadSound 加载提示音文件，支持 MP3、WAV、OGG/Vorbis 与 FLAC，并重采样到扬声器的采样率
 func (a *AlertPlayer) LoadSound() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.loadLocked()
+}
+
+func (a *AlertPlayer) loadLocked() error {
 	if a.buffer != nil {
 		return nil // 已经加载过了
 	}
 
-	f, err := os.Open(a.soundFile)
-	if err != nil {
-		return err
+	data := a.data
+	if data == nil {
+		var err error
+		if data, err = os.ReadFile(a.soundFile); err != nil {
+			return err
+		}
 	}
-	defer f.Close()
-
-	streamer, format, err := mp3.Decode(f)
+	buffer, err := Decode(a.soundFile, data)
 	if err != nil {
 		return err
 	}
-	defer streamer.Close()
-
-	// 创建一个缓冲区以存储所有音频
-	buffer := beep.NewBuffer(format)
-	buffer.Append(streamer)
 	a.buffer = buffer
 
 	return nil
 }
 
-// PlayLoop 循环播放提示音，直到调用Stop
-func (a *AlertPlayer) PlayLoop() error {
-	// 确保已初始化
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+// start 确保已连接引擎并加载声音，正在播放时不重复播放，否则用 play 开始播放
+func (a *AlertPlayer) start(play func(c *Channel, clip *beep.Buffer, cue Cue) *Voice) error {
 	if err := a.Init(); err != nil {
 		return err
 	}
-
-	// 确保声音文件已加载
-	if err := a.LoadSound(); err != nil {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := a.loadLocked(); err != nil {
 		return err
 	}
-
-	if a.isPlaying {
-		return nil // 已经在播放中
+	// 已经在播放中，避免重叠播放
+	if a.voice != nil && a.voice.Playing() {
+		return nil
 	}
-
-	a.isPlaying = true
-	a.stopChan = make(chan struct{})
-
-	// 在新协程中处理循环播放
-	go func() {
-		for {
-			select {
-			case <-a.stopChan:
-				a.isPlaying = false
-				return
-			default:
-				streamer := a.buffer.Streamer(0, a.buffer.Len())
-				speaker.Play(beep.Seq(streamer, beep.Callback(func() {
-					// 播放完成后延迟一小段时间
-					time.Sleep(500 * time.Millisecond)
-				})))
-
-				// 等待当前播放完成后再继续循环
-				time.Sleep(time.Duration(a.buffer.Len()) * time.Second / 44100)
-				time.Sleep(500 * time.Millisecond)
-			}
-		}
-	}()
-
+	a.voice = play(a.engine.Channel(a.channel), a.buffer, a.cueLocked())
 	return nil
 }
 
-// Stop 停止循环播放
-func (a *AlertPlayer) Stop() {
-	if a.isPlaying {
-		// 通知播放协程退出
-		close(a.stopChan)
-		a.isPlaying = false
+// PlayLoop 循环播放提示音，直到调用 Stop
+func (a *AlertPlayer) PlayLoop() error {
+	return a.start(func(c *Channel, clip *beep.Buffer, cue Cue) *Voice {
+		return c.Loop(clip, loopGap, cue)
+	})
+}
 
-		// 使用speaker.Clear()清除所有正在播放的音频
-		speaker.Clear()
-	}
+// PlayOnce 完整播放一次提示音
+func (a *AlertPlayer) PlayOnce() error {
+	return a.start(func(c *Channel, clip *beep.Buffer, cue Cue) *Voice {
+		return c.PlayClip(clip, cue)
+	})
 }
 
 // PlayFor 播放提示音的前 duration 时长，播放一次后自动停止。
 // 如果当前正在播放（循环或单次），将直接返回不做处理。
 func (a *AlertPlayer) PlayFor(duration time.Duration) error {
-	// 确保已初始化
-	if err := a.Init(); err != nil {
-		return err
-	}
+	return a.start(func(c *Channel, clip *beep.Buffer, cue Cue) *Voice {
+		return c.PlayFor(clip, duration, cue)
+	})
+}
 
-	// 确保声音文件已加载
-	if err := a.LoadSound(); err != nil {
-		return err
-	}
+// Playing 返回是否正在播放
+func (a *AlertPlayer) Playing() bool {
+	a.mu.Lock()
+	v := a.voice
+	a.mu.Unlock()
+	return v != nil && v.Playing()
+}
 
-	if a.isPlaying {
-		// 已经在播放中，避免重叠播放
-		return nil
+// Stop 停止本播放器的声音，同一声道之后播放的其它声音不受影响
+func (a *AlertPlayer) Stop() {
+	a.mu.Lock()
+	v := a.voice
+	a.voice = nil
+	a.mu.Unlock()
+	if v != nil {
+		v.Stop()
 	}
-
-	// 计算需要播放的采样数量
-	format := a.buffer.Format()
-	samples := format.SampleRate.N(duration)
-
-	streamer := a.buffer.Streamer(0, a.buffer.Len())
-	// 截取前 duration 的采样
-	limited := beep.Take(samples, streamer)
-
-	a.isPlaying = true
-
-	// 播放并在结束后重置状态
-	speaker.Play(beep.Seq(limited, beep.Callback(func() {
-		a.isPlaying = false
-	})))
-
-	return nil
 }
@@ -0,0 +1,274 @@
+package audio
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"tomato_clock/internal/config"
+)
+
+// Event 需要播放提示音的事件
+type Event string
+
+const (
+	EventSessionStart Event = "session_start" // 开始专注
+	EventCountdownEnd Event = "countdown_end" // 倒计时结束
+	EventBreakEnd     Event = "break_end"     // 休息结束
+	EventRandomHint   Event = "random_hint"   // 随机提示音
+	EventGoalReached  Event = "goal_reached"  // 达成今日目标
+	EventTaskDue      Event = "task_due"      // 任务截止提醒
+)
+
+// Events 全部事件，按设置界面中的顺序排列
+var Events = []Event{EventSessionStart, EventCountdownEnd, EventBreakEnd, EventRandomHint, EventGoalReached, EventTaskDue}
+
+// Title 返回事件的中文名称
+func (e Event) Title() string {
+	switch e {
+	case EventSessionStart:
+		return "开始专注"
+	case EventCountdownEnd:
+		return "倒计时结束"
+	case EventBreakEnd:
+		return "休息结束"
+	case EventRandomHint:
+		return "随机提示音"
+	case EventGoalReached:
+		return "达成今日目标"
+	case EventTaskDue:
+		return "任务截止提醒"
+	}
+	return string(e)
+}
+
+// Channel 返回事件提示音所用的声道：随机提示音单独一路，不会打断其它提示音
+func (e Event) Channel() string {
+	if e == EventRandomHint {
+		return ChannelHint
+	}
+	return ChannelAlert
+}
+
+// 播放方式
+const (
+	PlayOnce  = "once"  // 完整播放一次
+	PlayLoop  = "loop"  // 循环播放，直到关闭提示
+	PlayFirst = "first" // 只播放前 N 秒
+)
+
+// BuiltinPrefix 内置声音的前缀，如 "builtin:alert"
+const BuiltinPrefix = "builtin:"
+
+// DefaultScheme 返回默认的声音方案
+func DefaultScheme() map[Event]config.Sound {
+	return map[Event]config.Sound{
+		EventSessionStart: {Volume: 80, Mode: PlayOnce},
+		EventCountdownEnd: {File: BuiltinPrefix + "alert", Volume: 100, Mode: PlayLoop},
+		EventBreakEnd:     {File: BuiltinPrefix + "alert", Volume: 100, Mode: PlayFirst, Seconds: 5},
+		EventRandomHint:   {File: BuiltinPrefix + "alarm", Volume: 80, Mode: PlayFirst, Seconds: 10},
+		EventGoalReached:  {File: BuiltinPrefix + "alert", Volume: 80, Mode: PlayFirst, Seconds: 5},
+		EventTaskDue:      {File: BuiltinPrefix + "alarm", Volume: 80, Mode: PlayFirst, Seconds: 3},
+	}
+}
+
+// Scheme 把配置文件中的方案与默认方案合并，并修正越界的取值
+func Scheme(saved map[string]config.Sound) map[Event]config.Sound {
+	scheme := DefaultScheme()
+	for _, e := range Events {
+		s, ok := saved[string(e)]
+		if !ok {
+			continue
+		}
+		if s.Volume < 0 {
+			s.Volume = 0
+		} else if s.Volume > 100 {
+			s.Volume = 100
+		}
+		switch s.Mode {
+		case PlayOnce, PlayLoop:
+		case PlayFirst:
+			if s.Seconds <= 0 {
+				s.Seconds = 5
+			}
+		default:
+			s.Mode = PlayOnce
+		}
+		scheme[e] = s
+	}
+	return scheme
+}
+
+// SoundDirs 返回查找内置声音的目录：可执行文件旁的 resources/sounds 优先，其次是当前目录下的 resources/sounds。
+// 这样从其它目录启动程序时也能找到声音文件。
+func SoundDirs() []string {
+	var dirs []string
+	if exe, err := os.Executable(); err == nil {
+		if exe, err := filepath.EvalSymlinks(exe); err == nil {
+			dirs = append(dirs, filepath.Join(filepath.Dir(exe), "resources", "sounds"))
+		}
+	}
+	if wd, err := os.Getwd(); err == nil {
+		dir := filepath.Join(wd, "resources", "sounds")
+		if len(dirs) == 0 || dirs[0] != dir {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// ResolveFile 把方案中的文件设置解析为实际路径：内置声音在 dirs 中查找用户放置的同名文件，
+// 没有时返回空字符串表示使用程序内置的声音；"~/" 开头的路径相对于用户目录，
+// 其它相对路径相对于 dirs 中的第一个目录
+func ResolveFile(file string, dirs []string) (string, error) {
+	switch {
+	case strings.HasPrefix(file, BuiltinPrefix):
+		name := strings.TrimPrefix(file, BuiltinPrefix)
+		for _, dir := range dirs {
+			if path := FindSound(dir, name); path != "" {
+				return path, nil
+			}
+		}
+		if !IsBuiltin(name) {
+			return "", fmt.Errorf("没有名为 %s 的内置声音", name)
+		}
+		return "", nil
+	case strings.HasPrefix(file, "~/"):
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(home, file[2:]), nil
+	case !filepath.IsAbs(file) && len(dirs) > 0:
+		return filepath.Join(dirs[0], file), nil
+	}
+	return file, nil
+}
+
+// Board 按声音方案为每个事件准备好的播放器
+type Board struct {
+	mu      sync.Mutex
+	scheme  map[Event]config.Sound
+	players map[Event]*AlertPlayer
+}
+
+// NewBoard 按方案加载全部事件的声音。某个事件加载失败时该事件不播放，
+// 其它事件照常使用，错误合并后返回以便在界面上提示
+func NewBoard(scheme map[Event]config.Sound) (*Board, error) {
+	b := &Board{scheme: scheme, players: map[Event]*AlertPlayer{}}
+	dirs := SoundDirs()
+	var errs []error
+	for _, e := range Events {
+		p, err := loadPlayer(scheme[e], dirs)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", e.Title(), err))
+			continue
+		}
+		if p != nil {
+			p.SetChannel(e.Channel())
+			p.SetCue(string(e), scheme[e].File)
+			b.players[e] = p
+		}
+	}
+	return b, errors.Join(errs...)
+}
+
+// loadPlayer 加载一个声音设置对应的播放器，没有设置声音时返回 nil
+func loadPlayer(s config.Sound, dirs []string) (*AlertPlayer, error) {
+	if strings.TrimSpace(s.File) == "" {
+		return nil, nil
+	}
+	p, err := newPlayer(s.File, dirs)
+	if err != nil {
+		return nil, err
+	}
+	p.SetVolume(s.Volume)
+	p.SetCue("", s.File)
+	if err := p.Init(); err != nil {
+		return nil, err
+	}
+	if err := p.LoadSound(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// newPlayer 按文件设置创建播放器：合成音色直接生成采样，内置声音没有用户文件时读取程序内的数据
+func newPlayer(file string, dirs []string) (*AlertPlayer, error) {
+	if strings.HasPrefix(file, SynthPrefix) {
+		buf, err := Synth(strings.TrimPrefix(file, SynthPrefix))
+		if err != nil {
+			return nil, err
+		}
+		return NewAlertPlayerFromBuffer(file, buf), nil
+	}
+	path, err := ResolveFile(file, dirs)
+	if err != nil {
+		return nil, err
+	}
+	if path != "" {
+		return NewAlertPlayer(path), nil
+	}
+	name, data, err := builtinSound(strings.TrimPrefix(file, BuiltinPrefix))
+	if err != nil {
+		return nil, err
+	}
+	return NewAlertPlayerFromData(name, data), nil
+}
+
+// play 按播放方式播放
+func play(p *AlertPlayer, s config.Sound) error {
+	switch s.Mode {
+	case PlayLoop:
+		return p.PlayLoop()
+	case PlayFirst:
+		return p.PlayFor(time.Duration(s.Seconds) * time.Second)
+	}
+	return p.PlayOnce()
+}
+
+// Play 播放事件的提示音，事件没有设置声音时什么也不做
+func (b *Board) Play(e Event) error {
+	b.mu.Lock()
+	p, s := b.players[e], b.scheme[e]
+	b.mu.Unlock()
+	if p == nil {
+		return nil
+	}
+	return play(p, s)
+}
+
+// Stop 停止事件的提示音（如关闭倒计时结束的提示框时）
+func (b *Board) Stop(e Event) {
+	b.mu.Lock()
+	p := b.players[e]
+	b.mu.Unlock()
+	if p != nil {
+		p.Stop()
+	}
+}
+
+// StopAll 停止全部提示音
+func (b *Board) StopAll() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, p := range b.players {
+		p.Stop()
+	}
+}
+
+// Preview 试听一个声音设置，返回的播放器用于停止试听
+func Preview(s config.Sound) (*AlertPlayer, error) {
+	p, err := loadPlayer(s, SoundDirs())
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return nil, fmt.Errorf("未选择声音文件")
+	}
+	return p, play(p, s)
+}
@@ -0,0 +1,78 @@
+package audio
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"tomato_clock/internal/config"
+)
+
+func TestSchemeMergesDefaults(t *testing.T) {
+	scheme := Scheme(map[string]config.Sound{
+		string(EventCountdownEnd): {File: "/tmp/bell.wav", Volume: 150, Mode: PlayFirst},
+		string(EventRandomHint):   {File: "", Volume: 50, Mode: "unknown"},
+		"unknown_event":           {File: "x.mp3"},
+	})
+	if len(scheme) != len(Events) {
+		t.Fatalf("scheme has %d events", len(scheme))
+	}
+	end := scheme[EventCountdownEnd]
+	if end.File != "/tmp/bell.wav" || end.Volume != 100 || end.Seconds != 5 {
+		t.Fatalf("countdown end = %+v", end)
+	}
+	if hint := scheme[EventRandomHint]; hint.File != "" || hint.Mode != PlayOnce {
+		t.Fatalf("random hint = %+v", hint)
+	}
+	if due := scheme[EventTaskDue]; due != DefaultScheme()[EventTaskDue] {
+		t.Fatalf("task due should keep default, got %+v", due)
+	}
+}
+
+func TestResolveFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "alarm.ogg"), []byte("OggS"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	dirs := []string{filepath.Join(dir, "missing"), dir}
+
+	if got, err := ResolveFile(BuiltinPrefix+"alarm", dirs); err != nil || got != filepath.Join(dir, "alarm.ogg") {
+		t.Fatalf("builtin alarm = %q, %v", got, err)
+	}
+	// 没有用户文件时使用编译进程序的声音
+	if got, err := ResolveFile(BuiltinPrefix+"alert", dirs); err != nil || got != "" {
+		t.Fatalf("builtin alert = %q, %v", got, err)
+	}
+	if _, err := ResolveFile(BuiltinPrefix+"nope", dirs); err == nil {
+		t.Fatal("expected error for unknown builtin sound")
+	}
+	if got, _ := ResolveFile("bell.wav", dirs); got != filepath.Join(dirs[0], "bell.wav") {
+		t.Fatalf("relative path = %q", got)
+	}
+	abs := filepath.Join(dir, "x.flac")
+	if got, _ := ResolveFile(abs, dirs); got != abs {
+		t.Fatalf("absolute path = %q", got)
+	}
+}
+
+func TestBuiltinSoundsDecode(t *testing.T) {
+	names := Builtins()
+	for _, want := range []string{"alarm", "alert", "tick"} {
+		if !IsBuiltin(want) {
+			t.Fatalf("missing builtin %s in %v", want, names)
+		}
+	}
+	for _, name := range names {
+		file, data, err := builtinSound(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		buf, err := Decode(file, data)
+		if err != nil {
+			t.Fatalf("decode %s: %v", file, err)
+		}
+		if buf.Format().SampleRate != SampleRate || buf.Len() == 0 {
+			t.Fatalf("%s: rate=%d len=%d", file, buf.Format().SampleRate, buf.Len())
+		}
+	}
+}
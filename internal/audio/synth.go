@@ -0,0 +1,266 @@
+package audio
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/faiface/beep"
+)
+
+// SynthPrefix 合成音色的前缀，如 "synth:bell"，声音方案中与文件路径一样引用
+const SynthPrefix = "synth:"
+
+// Waveform 振荡器的波形
+type Waveform int
+
+// 支持的波形
+const (
+	Sine Waveform = iota
+	Triangle
+)
+
+// Oscillator 无限长的单音振荡器，左右声道相同
+type Oscillator struct {
+	Wave  Waveform
+	Freq  float64 // 频率（Hz）
+	Amp   float64 // 振幅，0-1
+	sr    beep.SampleRate
+	phase float64 // 当前相位，0-1
+}
+
+// NewOscillator 创建振荡器
+func NewOscillator(sr beep.SampleRate, wave Waveform, freq, amp float64) *Oscillator {
+	return &Oscillator{Wave: wave, Freq: freq, Amp: amp, sr: sr}
+}
+
+// Stream 实现 beep.Streamer
+func (o *Oscillator) Stream(samples [][2]float64) (int, bool) {
+	step := o.Freq / float64(o.sr)
+	for i := range samples {
+		var v float64
+		switch o.Wave {
+		case Triangle:
+			v = 1 - 4*math.Abs(o.phase-0.5)
+		default:
+			v = math.Sin(2 * math.Pi * o.phase)
+		}
+		v *= o.Amp
+		samples[i] = [2]float64{v, v}
+		o.phase += step
+		o.phase -= math.Floor(o.phase)
+	}
+	return len(samples), true
+}
+
+// Err 实现 beep.Streamer
+func (o *Oscillator) Err() error { return nil }
+
+// ADSR 包络：起音、衰减、持续电平与释音
+type ADSR struct {
+	Attack  time.Duration
+	Decay   time.Duration
+	Sustain float64 // 持续阶段的电平，0-1
+	Release time.Duration
+}
+
+// level 返回按下 t 时（释音之前）的电平
+func (e ADSR) level(t time.Duration) float64 {
+	switch {
+	case t < e.Attack:
+		return float64(t) / float64(e.Attack)
+	case t < e.Attack+e.Decay:
+		return 1 - (1-e.Sustain)*float64(t-e.Attack)/float64(e.Decay)
+	}
+	return e.Sustain
+}
+
+// Gain 返回音符持续 hold 后松开时，第 t 时刻的增益
+func (e ADSR) Gain(t, hold time.Duration) float64 {
+	if t < hold {
+		return e.level(t)
+	}
+	if t >= hold+e.Release {
+		return 0
+	}
+	return e.level(hold) * (1 - float64(t-hold)/float64(e.Release))
+}
+
+// Apply 用包络截取 s，返回长度为 hold+Release 的有限流
+func (e ADSR) Apply(sr beep.SampleRate, hold time.Duration, s beep.Streamer) beep.Streamer {
+	return &shaper{s: s, sr: sr, n: sr.N(hold + e.Release), gain: func(t time.Duration) float64 {
+		return e.Gain(t, hold)
+	}}
+}
+
+// Ring 让 s 按时间常数 tau 指数衰减（如钟、木块的余音），length 后结束
+func Ring(sr beep.SampleRate, tau, length time.Duration, s beep.Streamer) beep.Streamer {
+	return &shaper{s: s, sr: sr, n: sr.N(length), gain: func(t time.Duration) float64 {
+		return math.Exp(-float64(t) / float64(tau))
+	}}
+}
+
+// shaper 按时间变化的增益包装音频流，播放 n 个采样后结束
+type shaper struct {
+	s    beep.Streamer
+	sr   beep.SampleRate
+	n    int
+	pos  int
+	gain func(t time.Duration) float64
+}
+
+func (sh *shaper) Stream(samples [][2]float64) (int, bool) {
+	if sh.pos >= sh.n {
+		return 0, false
+	}
+	if rest := sh.n - sh.pos; len(samples) > rest {
+		samples = samples[:rest]
+	}
+	n, ok := sh.s.Stream(samples)
+	for i := 0; i < n; i++ {
+		g := sh.gain(sh.sr.D(sh.pos + i))
+		samples[i][0] *= g
+		samples[i][1] *= g
+	}
+	sh.pos += n
+	return n, ok || n > 0
+}
+
+func (sh *shaper) Err() error { return sh.s.Err() }
+
+// Partial 钟声的一个泛音
+type Partial struct {
+	Ratio float64       // 相对基频的倍数
+	Amp   float64       // 振幅
+	Tau   time.Duration // 衰减时间常数
+}
+
+// bellPartials 简化的钟声泛音（非整数倍频带来金属感）
+var bellPartials = []Partial{
+	{1, 1, 1200 * time.Millisecond},
+	{2, 0.5, 900 * time.Millisecond},
+	{2.76, 0.35, 600 * time.Millisecond},
+	{5.4, 0.2, 300 * time.Millisecond},
+	{8.93, 0.1, 150 * time.Millisecond},
+}
+
+// Additive 把若干指数衰减的正弦泛音叠加成一个音，总长 length；
+// 起音处加极短的渐入、最后十分之一淡出，以免首尾爆音
+func Additive(sr beep.SampleRate, freq float64, partials []Partial, length time.Duration) beep.Streamer {
+	var voices []beep.Streamer
+	for _, p := range partials {
+		voices = append(voices, Ring(sr, p.Tau, length, NewOscillator(sr, Sine, freq*p.Ratio, p.Amp)))
+	}
+	edge := ADSR{Attack: 5 * time.Millisecond, Sustain: 1, Release: length / 10}
+	return edge.Apply(sr, length-edge.Release, beep.Mix(voices...))
+}
+
+// at 在 d 之后开始播放 s
+func at(sr beep.SampleRate, d time.Duration, s beep.Streamer) beep.Streamer {
+	return beep.Seq(beep.Silence(sr.N(d)), s)
+}
+
+// synthPreset 一个合成音色
+type synthPreset struct {
+	title string
+	build func(sr beep.SampleRate) beep.Streamer
+}
+
+// synthPresets 内置的合成音色
+var synthPresets = map[string]synthPreset{
+	"bell": {"柔和钟声", func(sr beep.SampleRate) beep.Streamer {
+		return Additive(sr, 784, bellPartials, 3*time.Second)
+	}},
+	"double_beep": {"双响提示", func(sr beep.SampleRate) beep.Streamer {
+		env := ADSR{Attack: 10 * time.Millisecond, Decay: 20 * time.Millisecond, Sustain: 0.8, Release: 40 * time.Millisecond}
+		tone := func() beep.Streamer {
+			return env.Apply(sr, 110*time.Millisecond, NewOscillator(sr, Sine, 988, 0.8))
+		}
+		return beep.Mix(at(sr, 0, tone()), at(sr, 220*time.Millisecond, tone()))
+	}},
+	"chime": {"上升和弦", func(sr beep.SampleRate) beep.Streamer {
+		env := ADSR{Attack: 10 * time.Millisecond, Decay: 150 * time.Millisecond, Sustain: 0.5, Release: 400 * time.Millisecond}
+		var notes []beep.Streamer
+		for i, freq := range []float64{1046.5, 1318.5, 1568} { // C6 E6 G6
+			note := env.Apply(sr, 200*time.Millisecond, NewOscillator(sr, Triangle, freq, 0.5))
+			notes = append(notes, at(sr, time.Duration(i)*160*time.Millisecond, note))
+		}
+		return beep.Mix(notes...)
+	}},
+	"woodblock": {"木鱼", func(sr beep.SampleRate) beep.Streamer {
+		return Additive(sr, 880, []Partial{
+			{1, 1, 25 * time.Millisecond},
+			{2.4, 0.4, 12 * time.Millisecond},
+		}, 150*time.Millisecond)
+	}},
+}
+
+// SynthPresets 返回合成音色的名称，如 bell、chime
+func SynthPresets() []string {
+	var names []string
+	for name := range synthPresets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SynthTitle 返回合成音色的中文名称，未知音色返回名称本身
+func SynthTitle(name string) string {
+	if p, ok := synthPresets[name]; ok {
+		return p.title
+	}
+	return name
+}
+
+// Synth 以 SampleRate 生成合成音色，峰值超过 0.9 时整体压低以免削波
+func Synth(name string) (*beep.Buffer, error) {
+	return synthesize(name, SampleRate)
+}
+
+func synthesize(name string, sr beep.SampleRate) (*beep.Buffer, error) {
+	p, ok := synthPresets[name]
+	if !ok {
+		return nil, fmt.Errorf("没有名为 %s 的合成音色", name)
+	}
+	s := p.build(sr)
+	var samples [][2]float64
+	chunk := make([][2]float64, 512)
+	for {
+		n, ok := s.Stream(chunk)
+		samples = append(samples, chunk[:n]...)
+		if !ok {
+			break
+		}
+	}
+	peak := 0.0
+	for _, v := range samples {
+		peak = math.Max(peak, math.Max(math.Abs(v[0]), math.Abs(v[1])))
+	}
+	if peak > 0.9 {
+		for i := range samples {
+			samples[i][0] *= 0.9 / peak
+			samples[i][1] *= 0.9 / peak
+		}
+	}
+	buf := beep.NewBuffer(beep.Format{SampleRate: sr, NumChannels: 2, Precision: 2})
+	buf.Append(&sliceStreamer{samples: samples})
+	return buf, nil
+}
+
+// sliceStreamer 播放内存中的采样
+type sliceStreamer struct {
+	samples [][2]float64
+}
+
+func (s *sliceStreamer) Stream(samples [][2]float64) (int, bool) {
+	if len(s.samples) == 0 {
+		return 0, false
+	}
+	n := copy(samples, s.samples)
+	s.samples = s.samples[n:]
+	return n, true
+}
+
+func (s *sliceStreamer) Err() error { return nil }
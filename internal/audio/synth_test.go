@@ -0,0 +1,117 @@
+package audio
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/faiface/beep"
+)
+
+// drain 读出有限流的全部采样（只取左声道）
+func drain(s beep.Streamer) []float64 {
+	var out []float64
+	buf := make([][2]float64, 300)
+	for {
+		n, ok := s.Stream(buf)
+		for _, v := range buf[:n] {
+			out = append(out, v[0])
+		}
+		if !ok {
+			return out
+		}
+	}
+}
+
+func peakOf(samples []float64) float64 {
+	peak := 0.0
+	for _, v := range samples {
+		peak = math.Max(peak, math.Abs(v))
+	}
+	return peak
+}
+
+func TestOscillator(t *testing.T) {
+	const sr = beep.SampleRate(48000)
+	sine := drain(beep.Take(int(sr), NewOscillator(sr, Sine, 1000, 0.5)))
+	if len(sine) != int(sr) {
+		t.Fatalf("len = %d", len(sine))
+	}
+	// 1 秒 1000 Hz 的正弦波过零 2000 次左右
+	crossings := 0
+	for i := 1; i < len(sine); i++ {
+		if (sine[i-1] < 0) != (sine[i] < 0) {
+			crossings++
+		}
+	}
+	if crossings < 1998 || crossings > 2002 {
+		t.Fatalf("crossings = %d", crossings)
+	}
+	if p := peakOf(sine); math.Abs(p-0.5) > 0.001 {
+		t.Fatalf("sine peak = %v", p)
+	}
+
+	// 三角波在一个周期内线性上升再下降：100 Hz 时 1/4 周期处为 0
+	tri := drain(beep.Take(480, NewOscillator(sr, Triangle, 100, 1)))
+	if tri[0] != -1 || math.Abs(tri[120]) > 1e-9 || math.Abs(tri[240]-1) > 1e-9 {
+		t.Fatalf("triangle = %v %v %v", tri[0], tri[120], tri[240])
+	}
+}
+
+func TestADSR(t *testing.T) {
+	const sr = beep.SampleRate(1000) // 每个采样 1 毫秒，便于按时间取值
+	env := ADSR{Attack: 100 * time.Millisecond, Decay: 100 * time.Millisecond, Sustain: 0.5, Release: 200 * time.Millisecond}
+	// 0 Hz 的三角波相位不变，恒为 -1
+	out := drain(env.Apply(sr, 400*time.Millisecond, NewOscillator(sr, Triangle, 0, 1)))
+	if len(out) != 600 {
+		t.Fatalf("len = %d, want hold + release", len(out))
+	}
+	for i, want := range map[int]float64{0: 0, 50: 0.5, 100: 1, 150: 0.75, 300: 0.5, 500: 0.25, 599: 0.0025} {
+		if got := -out[i]; math.Abs(got-want) > 1e-9 {
+			t.Errorf("gain at %d ms = %v, want %v", i, got, want)
+		}
+	}
+
+	// 在衰减阶段就松开时从当时的电平开始释音
+	if g := env.Gain(250*time.Millisecond, 150*time.Millisecond); math.Abs(g-0.75*0.5) > 1e-9 {
+		t.Fatalf("early release gain = %v", g)
+	}
+}
+
+func TestSynthPresets(t *testing.T) {
+	for _, name := range []string{"bell", "double_beep", "chime", "woodblock"} {
+		buf, err := Synth(name)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		samples := drain(buf.Streamer(0, buf.Len()))
+		if len(samples) == 0 || buf.Format().SampleRate != SampleRate {
+			t.Fatalf("%s: %d samples at %d Hz", name, len(samples), buf.Format().SampleRate)
+		}
+		if p := peakOf(samples); p < 0.3 || p > 0.91 {
+			t.Errorf("%s: peak = %v", name, p)
+		}
+		// 结尾已经衰减到几乎无声，循环播放时不会有爆音
+		if p := peakOf(samples[len(samples)-SampleRate.N(time.Millisecond):]); p > 0.03 {
+			t.Errorf("%s: tail peak = %v", name, p)
+		}
+		if SynthTitle(name) == name {
+			t.Errorf("%s: missing title", name)
+		}
+	}
+
+	// 双响之间有一段静音
+	buf, _ := Synth("double_beep")
+	samples := drain(buf.Streamer(0, buf.Len()))
+	gap := samples[SampleRate.N(160*time.Millisecond):SampleRate.N(215*time.Millisecond)]
+	if p := peakOf(gap); p != 0 {
+		t.Fatalf("double beep gap peak = %v", p)
+	}
+	if want := SampleRate.N(370 * time.Millisecond); len(samples) != want {
+		t.Fatalf("double beep len = %d, want %d", len(samples), want)
+	}
+
+	if _, err := Synth("nope"); err == nil {
+		t.Fatal("expected error for unknown preset")
+	}
+}
@@ -0,0 +1,151 @@
+package audio
+
+import (
+	"sync"
+	"time"
+
+	"tomato_clock/internal/config"
+
+	"github.com/faiface/beep"
+)
+
+// 滴答声的播放时段
+const (
+	TickAlways = "always" // 整个专注阶段
+	TickLast   = "last"   // 只在倒计时的最后 N 分钟
+	TickFirst  = "first"  // 只在开始后的前 N 分钟，帮助进入状态
+)
+
+// TickModes 设置界面中可选的播放时段
+var TickModes = []string{TickAlways, TickLast, TickFirst}
+
+// TickModeTitle 返回播放时段的中文名称
+func TickModeTitle(mode string) string {
+	switch mode {
+	case TickLast:
+		return "最后 N 分钟"
+	case TickFirst:
+		return "开头 N 分钟"
+	}
+	return "整个专注"
+}
+
+// metronome 每隔 period 个采样从头播放一次 clip 的无限流；clip 比间隔长时截断，不会与下一下重叠
+func metronome(clip *beep.Buffer, period int) beep.Streamer {
+	n := min(clip.Len(), period)
+	return beep.Iterate(func() beep.Streamer {
+		return beep.Seq(clip.Streamer(0, n), beep.Silence(period-n))
+	})
+}
+
+// TickTrack 专注时的滴答声，在引擎的滴答声声道中播放，由计时器的状态驱动：
+// 每次计时更新时调用 Update，按播放时段开始或停止；暂停计时时一起暂停。
+// 提示音或正念铃声响起时滴答声自动让位静音，节拍保持不变。
+type TickTrack struct {
+	mu      sync.Mutex
+	setting config.Tick
+	every   time.Duration
+	player  *AlertPlayer
+	voice   *Voice
+}
+
+// NewTickTrack 按设置创建滴答声，声音在第一次播放时加载
+func NewTickTrack(c config.Tick) *TickTrack {
+	t := &TickTrack{setting: c, every: time.Duration(max(c.Every, 1)) * time.Second}
+	if c.Mode == "" {
+		t.setting.Mode = TickAlways
+	}
+	return t
+}
+
+// Setting 返回滴答声设置
+func (t *TickTrack) Setting() config.Tick {
+	return t.setting
+}
+
+// Active 判断已专注 elapsed 秒、倒计时还剩 remain 秒时是否应该有滴答声；
+// 正计时（countdown 为 false）没有“最后 N 分钟”，该时段下不响
+func (t *TickTrack) Active(elapsed, remain int, countdown bool) bool {
+	window := t.setting.Minutes * 60
+	switch t.setting.Mode {
+	case TickLast:
+		return countdown && remain > 0 && remain <= window
+	case TickFirst:
+		return elapsed < window
+	}
+	return true
+}
+
+// Update 按计时器的状态开始、继续、暂停或停止滴答声
+func (t *TickTrack) Update(elapsed, remain int, countdown, paused bool) error {
+	if !t.Active(elapsed, remain, countdown) {
+		t.Stop()
+		return nil
+	}
+	t.SetPaused(paused)
+	return t.start()
+}
+
+// start 没有在播放时开始播放
+func (t *TickTrack) start() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.voice != nil && t.voice.Playing() {
+		return nil
+	}
+	if t.player == nil {
+		p, err := newPlayer(t.setting.Sound, SoundDirs())
+		if err != nil {
+			return err
+		}
+		if err := p.LoadSound(); err != nil {
+			return err
+		}
+		t.player = p
+	}
+	clip := t.player.buffer
+	if clip.Len() == 0 {
+		return nil
+	}
+	c := Default().Channel(ChannelTick)
+	cue := Cue{Clip: t.setting.Sound, Volume: t.setting.Volume, Loop: true}
+	t.voice = c.Play(metronome(clip, c.e.sr.N(t.every)), cue)
+	return nil
+}
+
+// SetPaused 暂停或继续滴答声，随计时器的暂停一起调用
+func (t *TickTrack) SetPaused(paused bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.voice != nil {
+		t.voice.c.SetPaused(paused)
+	}
+}
+
+// SetVolume 调整音量百分比，播放中立即生效
+func (t *TickTrack) SetVolume(percent int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.setting.Volume = percent
+	if t.voice != nil {
+		t.voice.SetVolume(percent)
+	}
+}
+
+// Playing 返回是否正在播放（含暂停中）
+func (t *TickTrack) Playing() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.voice != nil && t.voice.Playing()
+}
+
+// Stop 停止滴答声，并取消声道的暂停以便下次播放
+func (t *TickTrack) Stop() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.voice != nil {
+		t.voice.Stop()
+		t.voice.c.SetPaused(false)
+		t.voice = nil
+	}
+}
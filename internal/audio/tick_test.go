@@ -0,0 +1,77 @@
+package audio
+
+import (
+	"testing"
+
+	"tomato_clock/internal/config"
+)
+
+func TestMetronome(t *testing.T) {
+	e := NewEngine(testRate)
+	e.Channel(ChannelTick).Play(metronome(constClip(30, 0.5), 100), Cue{Volume: 100})
+	out := pull(t, e, 300, 64)
+	for _, c := range []struct {
+		at   int
+		want float64
+	}{{0, 0.5}, {29, 0.5}, {30, 0}, {99, 0}, {100, 0.5}, {229, 0.5}, {230, 0}} {
+		if !near(out[c.at], c.want) {
+			t.Errorf("sample %d = %v, want %v", c.at, out[c.at], c.want)
+		}
+	}
+
+	// 声音比间隔长时截断，每一下都从头开始
+	e.Channel(ChannelTick).Play(metronome(constClip(150, 0.5), 100), Cue{Volume: 100})
+	if out := pull(t, e, 200, 64); !near(out[99], 0.5) || !near(out[100], 0.5) {
+		t.Fatalf("long clip = %v, %v", out[99], out[100])
+	}
+}
+
+func TestTickDucksUnderAlert(t *testing.T) {
+	e := NewEngine(testRate)
+	e.Channel(ChannelTick).Play(metronome(constClip(100, 0.5), 100), Cue{Volume: 100})
+	// 无声的提示音，只看滴答声是否让位
+	e.Channel(ChannelAlert).PlayClip(constClip(200, 0), Cue{Volume: 100})
+	out := pull(t, e, 400, 10)
+	if out[0] < 0.45 || !near(out[100], 0) || !near(out[199], 0) {
+		t.Fatalf("tick while alert plays = %v, %v, %v", out[0], out[100], out[199])
+	}
+	// 提示音结束后在 50 毫秒内恢复
+	if !near(out[300], 0.5) {
+		t.Fatalf("tick after alert = %v", out[300])
+	}
+}
+
+func TestTickTrackModes(t *testing.T) {
+	rec := useRecording(t)
+	last := NewTickTrack(config.Tick{Sound: BuiltinPrefix + "tick", Volume: 40, Mode: TickLast, Minutes: 5})
+	if last.Active(0, 1500, true) || !last.Active(1200, 300, true) || last.Active(1200, 300, false) {
+		t.Fatal("last 5 minutes mode")
+	}
+	first := NewTickTrack(config.Tick{Mode: TickFirst, Minutes: 5})
+	if !first.Active(299, 0, false) || first.Active(300, 0, false) {
+		t.Fatal("first 5 minutes mode")
+	}
+	if !NewTickTrack(config.Tick{}).Active(5000, 0, false) {
+		t.Fatal("default mode should tick during the whole session")
+	}
+
+	if err := last.Update(1000, 500, true, false); err != nil || last.Playing() {
+		t.Fatalf("ticking before the last 5 minutes: %v", err)
+	}
+	if err := last.Update(1200, 300, true, false); err != nil || !last.Playing() {
+		t.Fatalf("not ticking in the last 5 minutes: %v", err)
+	}
+	last.SetPaused(true)
+	if !Default().Channel(ChannelTick).Paused() {
+		t.Fatal("tick channel not paused with the timer")
+	}
+	last.Stop()
+	if last.Playing() || Default().Channel(ChannelTick).Paused() {
+		t.Fatal("tick still playing or paused after stop")
+	}
+	records := rec.Records()
+	want := Cue{Clip: BuiltinPrefix + "tick", Channel: ChannelTick, Volume: 40, Loop: true}
+	if len(records) != 1 || records[0].Cue != want || !records[0].Ended {
+		t.Fatalf("records = %+v", records)
+	}
+}
@@ -6,7 +6,7 @@ import (
 	"path/filepath"
 )
 
-// Config 表示持久化的应用配置（当前仅包含 DeepSeek API Key）
+// Config 表示持久化的应用配置（DeepSeek API Key 与界面设置）
 // 可根据需要在此结构体中添加更多字段。
 //
 // 保存路径：$HOME/.tomato_clock_config.json
@@ -17,6 +17,78 @@ import (
 
 type Config struct {
 	APIKey string `json:"api_key"`
+	// TaskView 任务列表的排序与筛选，下次启动时恢复
+	TaskView TaskView `json:"task_view"`
+	// Sounds 各事件的提示音方案，键见 audio.Event* 常量；未设置的事件使用默认方案
+	Sounds map[string]Sound `json:"sounds,omitempty"`
+	// Ambience 专注时播放的背景音
+	Ambience Ambience `json:"ambience"`
+	// Alarm 倒计时结束时提示音的闹铃方式，零值为一直循环直到关闭
+	Alarm Alarm `json:"alarm"`
+	// Bell 专注中随机响起的正念铃声
+	Bell Bell `json:"bell"`
+	// Tick 专注时的滴答声
+	Tick Tick `json:"tick"`
+}
+
+// Tick 滴答声设置
+type Tick struct {
+	// Sound "builtin:tick"、"synth:woodblock" 或声音文件路径；空字符串表示关闭
+	Sound   string `json:"sound,omitempty"`
+	Every   int    `json:"every,omitempty"`   // 每隔几秒响一下，0 按 1 秒处理
+	Volume  int    `json:"volume"`            // 音量百分比，0-100
+	Mode    string `json:"mode,omitempty"`    // "always"、"last" 或 "first"，空表示 always
+	Minutes int    `json:"minutes,omitempty"` // last/first 模式的分钟数
+}
+
+// Bell 正念铃声的调度设置，时间均以分钟为单位；Distribution 为空表示未设置过，使用默认值
+type Bell struct {
+	Enabled      bool   `json:"enabled"`
+	Distribution string `json:"distribution,omitempty"` // "fixed"、"uniform" 或 "poisson"
+	Interval     int    `json:"interval,omitempty"`     // 固定间隔，或泊松分布的平均间隔
+	Min          int    `json:"min,omitempty"`          // 均匀分布的最短间隔
+	Max          int    `json:"max,omitempty"`          // 均匀分布的最长间隔
+	QuietStart   int    `json:"quiet_start,omitempty"`  // 开始后多久内不响
+	QuietEnd     int    `json:"quiet_end,omitempty"`    // 倒计时结束前多久内不响
+	MaxCount     int    `json:"max_count,omitempty"`    // 每次专注最多响几次，0 表示不限
+}
+
+// Alarm 闹铃方式，除贪睡外时间均以秒为单位，0 表示不启用该项
+type Alarm struct {
+	FadeIn         int    `json:"fade_in,omitempty"`         // 从静音淡入的秒数
+	EscalateAfter  int    `json:"escalate_after,omitempty"`  // 多少秒后仍未关闭则升级
+	EscalateVolume int    `json:"escalate_volume,omitempty"` // 升级后的音量百分比，0 表示不变
+	EscalateFile   string `json:"escalate_file,omitempty"`   // 升级后改放的声音，空表示不换
+	MaxSeconds     int    `json:"max_seconds,omitempty"`     // 最多响多少秒后自动停止
+	SnoozeMinutes  int    `json:"snooze_minutes,omitempty"`  // 贪睡的分钟数，0 表示不提供贪睡
+}
+
+// Ambience 背景音设置
+type Ambience struct {
+	// Source "noise:white"、"noise:pink"、"noise:brown" 或循环播放的声音文件路径；空字符串表示关闭
+	Source string `json:"source,omitempty"`
+	Volume int    `json:"volume"` // 音量百分比，0-100
+}
+
+// Sound 某个事件的提示音设置
+type Sound struct {
+	// File 声音文件路径，或 "builtin:alert" 这样的内置声音；空字符串表示不播放
+	File    string `json:"file"`
+	Volume  int    `json:"volume"`            // 音量百分比，0-100
+	Mode    string `json:"mode,omitempty"`    // 播放方式，见 audio.Play* 常量
+	Seconds int    `json:"seconds,omitempty"` // 只播放前 N 秒时的秒数
+}
+
+// TaskView 任务列表的视图设置
+type TaskView struct {
+	SortMode    string `json:"sort_mode,omitempty"`  // 排序方式，见 model.Sort* 常量
+	HideDone    bool   `json:"hide_done,omitempty"`  // 隐藏已完成任务
+	Tag         string `json:"tag,omitempty"`        // 只显示带该标签的任务，空表示全部
+	ProjectID   int64  `json:"project_id,omitempty"` // 只显示该项目的任务，0 表示全部
+	OverdueOnly bool   `json:"overdue_only,omitempty"`
+	NextActions bool   `json:"next_actions,omitempty"` // 只显示未完成且未被前置任务阻塞的任务
+	// ArchiveAfterDays 已完成任务在完成多少天后自动归档，0 表示默认值，-1 表示不归档
+	ArchiveAfterDays int `json:"archive_after_days,omitempty"`
 }
 
 // configPath 返回配置文件完整路径。
@@ -47,20 +119,32 @@ func Load() (*Config, error) {
 	return &cfg, nil
 }
 
-// Save 将给定 APIKey 写入配置文件（若文件不存在则创建）。
+// Save 将给定 APIKey 写入配置文件（若文件不存在则创建），其它配置保持不变。
 func Save(apiKey string) error {
+	return Update(func(cfg *Config) { cfg.APIKey = apiKey })
+}
+
+// Update 读取现有配置（不存在时从零值开始），交给 fn 修改后写回。
+func Update(fn func(cfg *Config)) error {
 	path, err := configPath()
 	if err != nil {
 		return err
 	}
-	cfg := Config{APIKey: apiKey}
+	cfg, err := Load()
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return err
+		}
+		cfg = &Config{}
+	}
+	fn(cfg)
 
 	tmpPath := path + ".tmp"
 	f, err := os.Create(tmpPath)
 	if err != nil {
 		return err
 	}
-	if err := json.NewEncoder(f).Encode(&cfg); err != nil {
+	if err := json.NewEncoder(f).Encode(cfg); err != nil {
 		f.Close()
 		return err
 	}
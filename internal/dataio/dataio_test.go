@@ -0,0 +1,145 @@
+package dataio
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"tomato_clock/internal/model"
+)
+
+func TestExportImportRoundTrip(t *testing.T) {
+	// 使用临时目录作为用户目录，避免污染真实数据
+	t.Setenv("HOME", t.TempDir())
+	if err := model.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	base := time.Date(2025, 7, 1, 9, 0, 0, 0, time.UTC)
+	src := []model.Task{
+		{ID: 10, Title: "写周报", Label: "工作"},
+		{ID: 11, Title: "背单词", Label: "学习"},
+	}
+	id10, id11 := int64(10), int64(11)
+	sessions := []model.TimerSession{
+		{ID: 1, TaskID: &id10, Mode: "countdown", StartedAt: base, EndedAt: base.Add(25 * time.Minute), DurationSec: 1500},
+		{ID: 2, TaskID: &id11, Mode: "countup", StartedAt: base.Add(time.Hour), EndedAt: base.Add(2 * time.Hour), DurationSec: 3600},
+		{ID: 3, TaskID: &id11, Mode: "countdown", StartedAt: base.Add(3 * time.Hour), EndedAt: base.Add(3*time.Hour + 5*time.Minute), Interrupted: true, DurationSec: 300},
+		{ID: 4, Mode: "countup", StartedAt: base.AddDate(0, 0, 3), EndedAt: base.AddDate(0, 0, 3).Add(time.Hour), DurationSec: 3600},
+	}
+
+	// 筛选：只要 7 月 1 日的“学习”记录（含中断）
+	ds := Collect(src, sessions, Filter{
+		From:               base.Add(-time.Hour),
+		To:                 base.AddDate(0, 0, 1),
+		Labels:             []string{"学习"},
+		IncludeInterrupted: true,
+	})
+	if len(ds.Sessions) != 2 || len(ds.Tasks) != 1 || ds.Tasks[0].Title != "背单词" {
+		t.Fatalf("unexpected filtered dataset: %d sessions, %+v", len(ds.Sessions), ds.Tasks)
+	}
+
+	var csvBuf bytes.Buffer
+	if err := WriteSessionsCSV(&csvBuf, Collect(src, sessions, Filter{})); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(csvBuf.String(), ",写周报,工作,countdown,") {
+		t.Fatalf("csv should contain resolved task title and label:\n%s", csvBuf.String())
+	}
+
+	// 试运行不写入数据
+	fromCSV, err := ReadFile(bytes.NewReader(csvBuf.Bytes()), "sessions.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := Import(fromCSV, ImportOptions{DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.TasksAdded != 2 || res.SessionsAdded != 3 || len(model.AllTasks()) != 0 {
+		t.Fatalf("unexpected dry run: %+v, local tasks=%d", res, len(model.AllTasks()))
+	}
+
+	// 正式导入，ID 被重新映射
+	res, err = Import(fromCSV, ImportOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.TaskIDMap[10] != 1 || res.TaskIDMap[11] != 2 {
+		t.Fatalf("task ids should be remapped: %+v", res.TaskIDMap)
+	}
+	if got := len(model.AllSessions()); got != 3 {
+		t.Fatalf("expected 3 imported sessions, got %d", got)
+	}
+
+	// 再次导入 JSON：任务复用、记录全部判定为重复
+	var jsonBuf bytes.Buffer
+	if err := WriteJSON(&jsonBuf, Collect(src, sessions, Filter{})); err != nil {
+		t.Fatal(err)
+	}
+	fromJSON, err := ReadFile(&jsonBuf, "data.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err = Import(fromJSON, ImportOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.TasksMatched != 2 || res.TasksAdded != 0 || res.SessionsDuplicate != 3 || res.SessionsAdded != 0 {
+		t.Fatalf("re-import should detect duplicates: %+v", res)
+	}
+}
+
+func TestImportTasksCSVWithoutIDs(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	if err := model.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	ds, err := ReadFile(strings.NewReader("title,label\nA,学习\nB,学习\nC,工作\n"), "tasks.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := Import(ds, ImportOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.TasksAdded != 3 || len(res.Problems) != 0 {
+		t.Fatalf("result = %+v", res)
+	}
+	// 没有 ID 的任务都是顶层任务，不会挂到最后一个任务下面
+	for _, task := range model.AllTasks() {
+		if task.ParentID != 0 || len(task.BlockedBy) != 0 {
+			t.Fatalf("task %q got parent %d, blockers %v", task.Title, task.ParentID, task.BlockedBy)
+		}
+	}
+	if _, ok := res.TaskIDMap[0]; ok {
+		t.Fatalf("TaskIDMap should not map ID 0: %v", res.TaskIDMap)
+	}
+}
+
+func TestReadSessionsCSVTaskIDs(t *testing.T) {
+	csv := "id,task_id,task_title,label,started_at,ended_at\n" +
+		"1,5,周报,工作,2025-09-01T09:00:00Z,2025-09-01T09:25:00Z\n" +
+		// 重复任务的下一期：同名同标签，但是另一个任务
+		"2,9,周报,工作,2025-09-08T09:00:00Z,2025-09-08T09:25:00Z\n" +
+		// 任务改名后同一个 task_id
+		"3,9,周报（改）,工作,2025-09-09T09:00:00Z,2025-09-09T09:25:00Z\n" +
+		"4,,读书,,2025-09-10T09:00:00Z,2025-09-10T09:25:00Z\n" +
+		"5,,读书,,2025-09-11T09:00:00Z,2025-09-11T09:25:00Z\n"
+	ds, err := ReadFile(strings.NewReader(csv), "sessions.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ds.Tasks) != 3 || ds.Tasks[0].ID != 5 || ds.Tasks[1].ID != 9 || ds.Tasks[2].ID >= 0 {
+		t.Fatalf("tasks = %+v", ds.Tasks)
+	}
+	var ids []int64
+	for _, s := range ds.Sessions {
+		ids = append(ids, *s.TaskID)
+	}
+	if ids[0] != 5 || ids[1] != 9 || ids[2] != 9 || ids[3] != ids[4] || ids[3] >= 0 {
+		t.Fatalf("session task ids = %v", ids)
+	}
+}
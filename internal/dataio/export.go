@@ -0,0 +1,251 @@
+package dataio
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"tomato_clock/internal/model"
+)
+
+// 导出格式
+const (
+	FormatSessionsCSV = "sessions-csv"
+	FormatTasksCSV    = "tasks-csv"
+	FormatJSON        = "json"
+)
+
+// datasetVersion 规范化 JSON 的版本号，结构不兼容时递增
+const datasetVersion = 1
+
+// freeTimerTitle 未关联任务的计时记录在导出文件中的名称
+const freeTimerTitle = "自由计时"
+
+// 时间字段统一使用 RFC3339，便于表格软件与其它机器解析
+const timeLayout = time.RFC3339
+
+var sessionHeader = []string{"id", "task_id", "task_title", "label", "mode", "target_seconds", "started_at", "ended_at", "duration_sec", "interrupted"}
+var taskHeader = []string{"id", "title", "label", "tags", "project", "is_done", "repeat_rule", "estimate_pomodoros", "due_date", "created_at", "updated_at", "completed_at", "note"}
+
+// Filter 描述导出的筛选条件，零值表示导出全部已结束且未中断的记录
+type Filter struct {
+	From               time.Time // 与 [From, To) 有交集的记录，零值表示不限
+	To                 time.Time
+	Labels             []string // 按任务标签筛选（任一标签匹配即可），自由计时对应 model.DefaultLabel
+	TaskIDs            []int64
+	IncludeInterrupted bool
+}
+
+// Dataset 规范化的导出数据：任务与计时记录分开保存，通过 task_id 关联
+type Dataset struct {
+	Version    int                  `json:"version"`
+	ExportedAt time.Time            `json:"exported_at"`
+	Tasks      []model.Task         `json:"tasks"`
+	Sessions   []model.TimerSession `json:"sessions"`
+	Projects   []model.Project      `json:"projects,omitempty"` // 任务引用的项目
+}
+
+// tagSeparator CSV 中多个标签之间的分隔符
+const tagSeparator = ";"
+
+// labelOf 返回任务用于筛选与导出的标签
+func labelOf(t *model.Task) string {
+	if t == nil || t.Label == "" {
+		return model.DefaultLabel
+	}
+	return t.Label
+}
+
+func (f Filter) matchTask(t *model.Task) bool {
+	if len(f.TaskIDs) > 0 {
+		if t == nil || !slices.Contains(f.TaskIDs, t.ID) {
+			return false
+		}
+	}
+	if len(f.Labels) > 0 && !slices.ContainsFunc(tagsOf(t), func(tag string) bool { return slices.Contains(f.Labels, tag) }) {
+		return false
+	}
+	return true
+}
+
+// tagsOf 返回任务用于筛选的全部标签，没有标签时为 model.DefaultLabel
+func tagsOf(t *model.Task) []string {
+	if t == nil || len(t.Tags) == 0 {
+		return []string{labelOf(t)}
+	}
+	return t.Tags
+}
+
+// referencedProjects 返回 tasks 引用到的项目
+func referencedProjects(tasks []model.Task, projects []model.Project) []model.Project {
+	used := map[int64]bool{}
+	for _, t := range tasks {
+		used[t.ProjectID] = true
+	}
+	var res []model.Project
+	for _, p := range projects {
+		if used[p.ID] {
+			res = append(res, p)
+		}
+	}
+	return res
+}
+
+func (f Filter) matchSession(s model.TimerSession, t *model.Task) bool {
+	if s.EndedAt.IsZero() {
+		return false
+	}
+	if s.Interrupted && !f.IncludeInterrupted {
+		return false
+	}
+	if !f.From.IsZero() && !s.EndedAt.After(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && !s.StartedAt.Before(f.To) {
+		return false
+	}
+	return f.matchTask(t)
+}
+
+// Collect 按筛选条件从给定数据中选出任务与计时记录。
+// 被选中记录引用的任务总会包含在结果中，保证导出文件自洽。
+func Collect(tasks []model.Task, sessions []model.TimerSession, f Filter) Dataset {
+	ds := Dataset{Version: datasetVersion, ExportedAt: time.Now()}
+
+	byID := make(map[int64]*model.Task, len(tasks))
+	for i := range tasks {
+		byID[tasks[i].ID] = &tasks[i]
+	}
+
+	included := map[int64]bool{}
+	for _, s := range sessions {
+		var t *model.Task
+		if s.TaskID != nil {
+			t = byID[*s.TaskID]
+		}
+		if !f.matchSession(s, t) {
+			continue
+		}
+		ds.Sessions = append(ds.Sessions, s)
+		if t != nil {
+			included[t.ID] = true
+		}
+	}
+	for i := range tasks {
+		if included[tasks[i].ID] || f.matchTask(&tasks[i]) {
+			ds.Tasks = append(ds.Tasks, tasks[i])
+		}
+	}
+	return ds
+}
+
+// Export 使用当前已加载的数据按格式导出
+func Export(w io.Writer, format string, f Filter) error {
+	ds := Collect(model.AllTasks(), model.AllSessions(), f)
+	ds.Projects = referencedProjects(ds.Tasks, model.AllProjects())
+	switch format {
+	case FormatSessionsCSV:
+		return WriteSessionsCSV(w, ds)
+	case FormatTasksCSV:
+		return WriteTasksCSV(w, ds)
+	case FormatJSON:
+		return WriteJSON(w, ds)
+	default:
+		return fmt.Errorf("unknown export format %q", format)
+	}
+}
+
+// WriteJSON 输出规范化 JSON
+func WriteJSON(w io.Writer, ds Dataset) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(ds)
+}
+
+// WriteSessionsCSV 输出计时记录 CSV，附带解析后的任务标题与标签
+func WriteSessionsCSV(w io.Writer, ds Dataset) error {
+	byID := make(map[int64]*model.Task, len(ds.Tasks))
+	for i := range ds.Tasks {
+		byID[ds.Tasks[i].ID] = &ds.Tasks[i]
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(sessionHeader); err != nil {
+		return err
+	}
+	for _, s := range ds.Sessions {
+		taskID, title := "", freeTimerTitle
+		var t *model.Task
+		if s.TaskID != nil {
+			taskID = strconv.FormatInt(*s.TaskID, 10)
+			if t = byID[*s.TaskID]; t != nil {
+				title = t.Title
+			}
+		}
+		record := []string{
+			strconv.FormatInt(s.ID, 10),
+			taskID,
+			title,
+			labelOf(t),
+			s.Mode,
+			strconv.Itoa(s.TargetSeconds),
+			s.StartedAt.Format(timeLayout),
+			s.EndedAt.Format(timeLayout),
+			strconv.Itoa(s.DurationSec),
+			strconv.FormatBool(s.Interrupted),
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteTasksCSV 输出任务 CSV
+func WriteTasksCSV(w io.Writer, ds Dataset) error {
+	projectNames := map[int64]string{}
+	for _, p := range ds.Projects {
+		projectNames[p.ID] = p.Name
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(taskHeader); err != nil {
+		return err
+	}
+	for _, t := range ds.Tasks {
+		due := ""
+		if t.DueDate != nil {
+			due = t.DueDate.Format(timeLayout)
+		}
+		completed := ""
+		if t.CompletedAt != nil {
+			completed = t.CompletedAt.Format(timeLayout)
+		}
+		record := []string{
+			strconv.FormatInt(t.ID, 10),
+			t.Title,
+			t.Label,
+			strings.Join(t.Tags, tagSeparator),
+			projectNames[t.ProjectID],
+			strconv.FormatBool(t.IsDone),
+			t.RepeatRule,
+			strconv.Itoa(t.EstimatePomodoros),
+			due,
+			t.CreatedAt.Format(timeLayout),
+			t.UpdatedAt.Format(timeLayout),
+			completed,
+			t.Note,
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,442 @@
+package dataio
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"tomato_clock/internal/model"
+)
+
+// ImportOptions 控制导入行为
+type ImportOptions struct {
+	DryRun bool // 仅校验并统计，不写入数据
+}
+
+// ImportResult 汇总一次导入（或试运行）的结果
+type ImportResult struct {
+	TasksAdded        int
+	TasksMatched      int // 与本地已有任务重复（标题与标签相同），直接复用
+	SessionsAdded     int
+	SessionsDuplicate int // 与本地或文件内其它记录重复而跳过
+	SessionsInvalid   int // 校验失败而跳过
+	// TaskIDMap 导入文件中的任务 ID -> 本地任务 ID；试运行时新任务映射为 0
+	TaskIDMap map[int64]int64
+	Problems  []string
+}
+
+// Summary 返回便于展示的一段结果描述
+func (r ImportResult) Summary() string {
+	lines := []string{
+		fmt.Sprintf("新增任务 %d 个，复用已有任务 %d 个", r.TasksAdded, r.TasksMatched),
+		fmt.Sprintf("新增计时记录 %d 条，重复 %d 条，无效 %d 条", r.SessionsAdded, r.SessionsDuplicate, r.SessionsInvalid),
+	}
+	lines = append(lines, r.Problems...)
+	return strings.Join(lines, "\n")
+}
+
+// ReadFile 根据扩展名读取 JSON 或 CSV（自动识别任务 / 计时记录表头）
+func ReadFile(r io.Reader, name string) (Dataset, error) {
+	if strings.EqualFold(filepath.Ext(name), ".json") {
+		return ReadJSON(r)
+	}
+	rows, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return Dataset{}, err
+	}
+	if len(rows) == 0 {
+		return Dataset{}, fmt.Errorf("empty csv file")
+	}
+	if columnIndex(rows[0])["started_at"] >= 0 {
+		return parseSessionRows(rows)
+	}
+	return parseTaskRows(rows)
+}
+
+// ReadJSON 读取 WriteJSON 导出的规范化 JSON
+func ReadJSON(r io.Reader) (Dataset, error) {
+	var ds Dataset
+	if err := json.NewDecoder(r).Decode(&ds); err != nil {
+		return Dataset{}, err
+	}
+	if ds.Version > datasetVersion {
+		return Dataset{}, fmt.Errorf("unsupported dataset version %d", ds.Version)
+	}
+	return ds, nil
+}
+
+// ReadSessionsCSV 读取计时记录 CSV。列顺序不限，按表头识别；
+// 任务通过 task_id 或 task_title 关联，并据此生成待导入的任务。
+func ReadSessionsCSV(r io.Reader) (Dataset, error) {
+	rows, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return Dataset{}, err
+	}
+	return parseSessionRows(rows)
+}
+
+// ReadTasksCSV 读取任务 CSV
+func ReadTasksCSV(r io.Reader) (Dataset, error) {
+	rows, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return Dataset{}, err
+	}
+	return parseTaskRows(rows)
+}
+
+// columnIndex 返回表头名到列下标的映射，缺失的列为 -1
+func columnIndex(header []string) map[string]int {
+	idx := map[string]int{}
+	for _, name := range append(append([]string{}, sessionHeader...), taskHeader...) {
+		idx[name] = -1
+	}
+	for i, h := range header {
+		idx[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+	return idx
+}
+
+// cell 取出指定列的值，列缺失或越界时返回空字符串
+func cell(row []string, idx map[string]int, name string) string {
+	i := idx[name]
+	if i < 0 || i >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[i])
+}
+
+func parseTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(timeLayout, s); err == nil {
+		return t, nil
+	}
+	// 兼容表格软件改写后的本地时间格式
+	return time.ParseInLocation("2006-01-02 15:04:05", s, time.Local)
+}
+
+func parseSessionRows(rows [][]string) (Dataset, error) {
+	ds := Dataset{Version: datasetVersion}
+	if len(rows) == 0 {
+		return ds, nil
+	}
+	idx := columnIndex(rows[0])
+	if idx["started_at"] < 0 || idx["ended_at"] < 0 {
+		return ds, fmt.Errorf("csv missing started_at/ended_at columns")
+	}
+
+	// 有 task_id 时按 ID 区分任务（同名的重复任务各期是不同的任务），
+	// 没有时按标题+标签归为同一任务，并生成临时负数 ID
+	taskKeys := map[string]int64{}
+	nextTemp := int64(-1)
+
+	for n, row := range rows[1:] {
+		line := n + 2
+		startedAt, err := parseTime(cell(row, idx, "started_at"))
+		if err != nil {
+			return ds, fmt.Errorf("line %d: %w", line, err)
+		}
+		endedAt, err := parseTime(cell(row, idx, "ended_at"))
+		if err != nil {
+			return ds, fmt.Errorf("line %d: %w", line, err)
+		}
+		s := model.TimerSession{
+			Mode:        cell(row, idx, "mode"),
+			StartedAt:   startedAt,
+			EndedAt:     endedAt,
+			Interrupted: cell(row, idx, "interrupted") == "true",
+		}
+		s.ID, _ = strconv.ParseInt(cell(row, idx, "id"), 10, 64)
+		s.TargetSeconds, _ = strconv.Atoi(cell(row, idx, "target_seconds"))
+		s.DurationSec, _ = strconv.Atoi(cell(row, idx, "duration_sec"))
+		if s.Mode == "" {
+			s.Mode = "countup"
+		}
+
+		title := cell(row, idx, "task_title")
+		label := cell(row, idx, "label")
+		if title != "" && title != freeTimerTitle {
+			id, _ := strconv.ParseInt(cell(row, idx, "task_id"), 10, 64)
+			key := "title:" + title + "\x00" + label
+			if id > 0 {
+				key = "id:" + strconv.FormatInt(id, 10)
+			}
+			if known, ok := taskKeys[key]; ok {
+				id = known
+			} else {
+				if id <= 0 {
+					id = nextTemp
+					nextTemp--
+				}
+				taskKeys[key] = id
+				ds.Tasks = append(ds.Tasks, model.Task{ID: id, Title: title, Label: label, RepeatRule: model.RepeatNone})
+			}
+			s.TaskID = &id
+		}
+		ds.Sessions = append(ds.Sessions, s)
+	}
+	return ds, nil
+}
+
+func parseTaskRows(rows [][]string) (Dataset, error) {
+	ds := Dataset{Version: datasetVersion}
+	if len(rows) == 0 {
+		return ds, nil
+	}
+	idx := columnIndex(rows[0])
+	if idx["title"] < 0 {
+		return ds, fmt.Errorf("csv missing title column")
+	}
+	// CSV 中项目以名称保存，这里为每个名称分配文件内的临时 ID
+	projectIDs := map[string]int64{}
+	for n, row := range rows[1:] {
+		line := n + 2
+		t := model.Task{
+			Title:      cell(row, idx, "title"),
+			Label:      cell(row, idx, "label"),
+			IsDone:     cell(row, idx, "is_done") == "true",
+			RepeatRule: cell(row, idx, "repeat_rule"),
+			Note:       cell(row, idx, "note"),
+		}
+		t.ID, _ = strconv.ParseInt(cell(row, idx, "id"), 10, 64)
+		t.EstimatePomodoros, _ = strconv.Atoi(cell(row, idx, "estimate_pomodoros"))
+		if tags := cell(row, idx, "tags"); tags != "" {
+			t.Tags = model.NormalizeTags(strings.Split(tags, tagSeparator))
+		}
+		if name := cell(row, idx, "project"); name != "" {
+			id, ok := projectIDs[name]
+			if !ok {
+				id = int64(len(projectIDs) + 1)
+				projectIDs[name] = id
+				ds.Projects = append(ds.Projects, model.Project{ID: id, Name: name})
+			}
+			t.ProjectID = id
+		}
+		if t.RepeatRule == "" {
+			t.RepeatRule = model.RepeatNone
+		}
+		due, err := parseTime(cell(row, idx, "due_date"))
+		if err != nil {
+			return ds, fmt.Errorf("line %d: %w", line, err)
+		}
+		if !due.IsZero() {
+			t.DueDate = &due
+		}
+		if t.CreatedAt, err = parseTime(cell(row, idx, "created_at")); err != nil {
+			return ds, fmt.Errorf("line %d: %w", line, err)
+		}
+		if t.UpdatedAt, err = parseTime(cell(row, idx, "updated_at")); err != nil {
+			return ds, fmt.Errorf("line %d: %w", line, err)
+		}
+		completed, err := parseTime(cell(row, idx, "completed_at"))
+		if err != nil {
+			return ds, fmt.Errorf("line %d: %w", line, err)
+		}
+		if !completed.IsZero() {
+			t.CompletedAt = &completed
+		}
+		ds.Tasks = append(ds.Tasks, t)
+	}
+	return ds, nil
+}
+
+// taskKey 判断任务重复所用的键
+func taskKey(t model.Task) string {
+	return t.Title + "\x00" + t.Label
+}
+
+// sessionKey 判断计时记录重复所用的键（精确到秒），taskRef 标识关联的任务
+func sessionKey(s model.TimerSession, taskRef string) string {
+	return fmt.Sprintf("%s|%d|%d", taskRef, s.StartedAt.Unix(), s.EndedAt.Unix())
+}
+
+// localTaskRef 返回本地任务（或自由计时）在去重键中的标识
+func localTaskRef(taskID *int64) string {
+	if taskID == nil {
+		return "free"
+	}
+	return strconv.FormatInt(*taskID, 10)
+}
+
+// Import 将数据集合并到当前数据中。
+// 任务按标题+标签去重并复用本地 ID，新任务重新分配 ID；
+// 计时记录按任务与起止时间去重，无效记录跳过并记录在 Problems 中。
+func Import(ds Dataset, opts ImportOptions) (ImportResult, error) {
+	res := ImportResult{TaskIDMap: map[int64]int64{}}
+
+	existingTasks := model.AllTasks()
+	localByKey := make(map[string]int64, len(existingTasks))
+	for _, t := range existingTasks {
+		localByKey[taskKey(t)] = t.ID
+	}
+
+	// 1. 任务：复用或新增
+	var newTasks []model.Task
+	var newTaskOldIDs []int64
+	pendingByKey := map[string]int64{} // 文件内重复的任务 -> 首次出现的原 ID
+	firstOf := map[int64]int64{}       // 新任务的原 ID -> 首次出现的原 ID
+	for _, t := range ds.Tasks {
+		if strings.TrimSpace(t.Title) == "" {
+			res.Problems = append(res.Problems, fmt.Sprintf("任务 #%d 缺少标题，已跳过", t.ID))
+			continue
+		}
+		// 没有 id 列的 CSV 中 ID 都是 0，不能作为引用，不记入映射
+		hasID := t.ID != 0
+		if id, ok := localByKey[taskKey(t)]; ok {
+			if hasID {
+				res.TaskIDMap[t.ID] = id
+			}
+			res.TasksMatched++
+			continue
+		}
+		if first, ok := pendingByKey[taskKey(t)]; ok {
+			if hasID {
+				res.TaskIDMap[t.ID] = 0
+				firstOf[t.ID] = first
+			}
+			continue
+		}
+		pendingByKey[taskKey(t)] = t.ID
+		if hasID {
+			firstOf[t.ID] = t.ID
+			res.TaskIDMap[t.ID] = 0
+		}
+		newTasks = append(newTasks, t)
+		newTaskOldIDs = append(newTaskOldIDs, t.ID)
+	}
+	res.TasksAdded = len(newTasks)
+
+	if !opts.DryRun && len(newTasks) > 0 {
+		// 项目按名称合并到本地项目
+		projectNames := map[int64]string{}
+		for _, p := range ds.Projects {
+			projectNames[p.ID] = p.Name
+		}
+		for i := range newTasks {
+			name, ok := projectNames[newTasks[i].ProjectID]
+			if !ok {
+				newTasks[i].ProjectID = 0
+				continue
+			}
+			p, err := model.AddProject(name)
+			if err != nil {
+				return res, err
+			}
+			newTasks[i].ProjectID = p.ID
+		}
+
+		// 父任务、前置任务与重复系列引用的是文件中的 ID，插入后再重新映射
+		oldParents := make([]int64, len(newTasks))
+		oldBlockers := make([][]int64, len(newTasks))
+		for i := range newTasks {
+			oldParents[i] = newTasks[i].ParentID
+			oldBlockers[i] = newTasks[i].BlockedBy
+			newTasks[i].ParentID = 0
+			newTasks[i].BlockedBy = nil
+			newTasks[i].SeriesID = 0
+			newTasks[i].OccurrenceDate = nil
+		}
+		ids, err := model.AddTasks(newTasks)
+		if err != nil {
+			return res, err
+		}
+		for i, old := range newTaskOldIDs {
+			if old != 0 {
+				res.TaskIDMap[old] = ids[i]
+			}
+		}
+		// 文件内重复的任务指向同一个新 ID
+		for old, first := range firstOf {
+			res.TaskIDMap[old] = res.TaskIDMap[first]
+		}
+		for i, t := range newTasks {
+			// 原 ID 为 0 表示没有父任务或前置任务
+			var parent int64
+			if oldParents[i] != 0 {
+				parent = res.TaskIDMap[oldParents[i]]
+			}
+			var blockers []int64
+			for _, old := range oldBlockers[i] {
+				if old == 0 {
+					continue
+				}
+				if id := res.TaskIDMap[old]; id != 0 {
+					blockers = append(blockers, id)
+				}
+			}
+			if parent == 0 && len(blockers) == 0 {
+				continue
+			}
+			t.ID = ids[i]
+			t.ParentID = parent
+			t.BlockedBy = blockers
+			if err := model.UpdateTask(t); err != nil {
+				res.Problems = append(res.Problems, fmt.Sprintf("任务 #%d 的父任务或前置任务无法恢复: %v", newTaskOldIDs[i], err))
+			}
+		}
+	}
+
+	// 2. 计时记录：校验、重映射、去重
+	seen := map[string]bool{}
+	for _, s := range model.AllSessions() {
+		seen[sessionKey(s, localTaskRef(s.TaskID))] = true
+	}
+
+	var newSessions []model.TimerSession
+	for _, s := range ds.Sessions {
+		if s.EndedAt.IsZero() || s.EndedAt.Before(s.StartedAt) {
+			res.SessionsInvalid++
+			res.Problems = append(res.Problems, fmt.Sprintf("计时记录 #%d 起止时间无效，已跳过", s.ID))
+			continue
+		}
+		if s.DurationSec <= 0 {
+			s.DurationSec = int(s.EndedAt.Sub(s.StartedAt).Seconds())
+		}
+
+		// 新任务在试运行时还没有本地 ID，以其在文件中的首个原 ID 作为去重标识
+		ref := "free"
+		if s.TaskID != nil {
+			local, ok := res.TaskIDMap[*s.TaskID]
+			if !ok {
+				res.SessionsInvalid++
+				res.Problems = append(res.Problems, fmt.Sprintf("计时记录 #%d 引用了不存在的任务 #%d，已跳过", s.ID, *s.TaskID))
+				continue
+			}
+			if local == 0 {
+				ref = fmt.Sprintf("new:%d", firstOf[*s.TaskID])
+			} else {
+				ref = strconv.FormatInt(local, 10)
+				s.TaskID = &local
+			}
+		}
+
+		key := sessionKey(s, ref)
+		if seen[key] {
+			res.SessionsDuplicate++
+			continue
+		}
+		seen[key] = true
+		newSessions = append(newSessions, s)
+	}
+	res.SessionsAdded = len(newSessions)
+
+	if opts.DryRun {
+		log.Printf("[Import] dry run: tasks +%d, sessions +%d", res.TasksAdded, res.SessionsAdded)
+		return res, nil
+	}
+	if len(newSessions) > 0 {
+		if err := model.AddSessions(newSessions); err != nil {
+			return res, err
+		}
+	}
+	log.Printf("[Import] tasks +%d (matched %d), sessions +%d (dup %d, invalid %d)",
+		res.TasksAdded, res.TasksMatched, res.SessionsAdded, res.SessionsDuplicate, res.SessionsInvalid)
+	return res, nil
+}
@@ -0,0 +1,162 @@
+package ics
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"tomato_clock/internal/model"
+)
+
+// prodID 日历产品标识
+const prodID = "-//tomato_clock//Focus Calendar//ZH"
+
+// eventYears 无法用 RRULE 表示的每年重复倒数日（农历、2 月 29 日）展开为具体日期的年数，
+// 从上一年起算
+const eventYears = 10
+
+// uidDomain 生成 UID 时使用的域名部分，保证 UID 在重新生成时保持稳定
+const uidDomain = "tomato-clock.local"
+
+// Options 控制日历内容
+type Options struct {
+	// DueAsTodo 为 true 时截止日期输出为 VTODO，否则输出为全天 VEVENT
+	DueAsTodo bool
+	// Now 用作 DTSTAMP，零值时取当前时间
+	Now time.Time
+}
+
+// Build 将已完成的计时记录、带截止日期的任务与倒数日写为 iCalendar。
+// 计时记录的 SUMMARY 为任务标题、CATEGORIES 为标签；被中断或未结束的记录不输出。
+// 倒数日输出为全天 VEVENT，每年重复的公历日期使用 RRULE，农历日期展开为具体日期。
+func Build(out io.Writer, tasks []model.Task, sessions []model.TimerSession, events []model.Event, opts Options) error {
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	byID := make(map[int64]model.Task, len(tasks))
+	for _, t := range tasks {
+		byID[t.ID] = t
+	}
+
+	w := NewWriter(out)
+	w.Begin("VCALENDAR")
+	w.Line("VERSION", "2.0")
+	w.Line("PRODID", prodID)
+	w.Line("CALSCALE", "GREGORIAN")
+	w.Text("X-WR-CALNAME", "番茄钟专注记录")
+
+	for _, s := range sessions {
+		if s.Interrupted || s.EndedAt.IsZero() {
+			continue
+		}
+		title, categories := "自由计时", []string{model.DefaultLabel}
+		if s.TaskID != nil {
+			if t, ok := byID[*s.TaskID]; ok {
+				title = t.Title
+				if tags := taskCategories(t); len(tags) > 0 {
+					categories = tags
+				}
+			}
+		}
+		mode := "正计时"
+		if s.Mode == "countdown" {
+			mode = "倒计时"
+		}
+
+		w.Begin("VEVENT")
+		w.Line("UID", fmt.Sprintf("session-%d@%s", s.ID, uidDomain))
+		w.Time("DTSTAMP", now)
+		w.Time("DTSTART", s.StartedAt)
+		w.Time("DTEND", s.EndedAt)
+		w.Text("SUMMARY", "🍅 "+title)
+		w.TextList("CATEGORIES", categories)
+		w.Text("DESCRIPTION", fmt.Sprintf("%s，专注 %s", mode, model.FormatDuration(s.DurationSec)))
+		w.Line("TRANSP", "TRANSPARENT")
+		w.End("VEVENT")
+	}
+
+	for _, t := range tasks {
+		if t.DueDate == nil {
+			continue
+		}
+		due := *t.DueDate
+		uid := fmt.Sprintf("task-%d@%s", t.ID, uidDomain)
+		if opts.DueAsTodo {
+			w.Begin("VTODO")
+			w.Line("UID", uid)
+			w.Time("DTSTAMP", now)
+			w.Date("DUE", due)
+			w.Text("SUMMARY", t.Title)
+			if tags := taskCategories(t); len(tags) > 0 {
+				w.TextList("CATEGORIES", tags)
+			}
+			if t.IsDone {
+				w.Line("STATUS", "COMPLETED")
+			} else {
+				w.Line("STATUS", "NEEDS-ACTION")
+			}
+			w.End("VTODO")
+			continue
+		}
+		w.Begin("VEVENT")
+		w.Line("UID", uid)
+		w.Time("DTSTAMP", now)
+		w.Date("DTSTART", due)
+		w.Date("DTEND", due.AddDate(0, 0, 1))
+		w.Text("SUMMARY", t.Title)
+		if tags := taskCategories(t); len(tags) > 0 {
+			w.TextList("CATEGORIES", tags)
+		}
+		w.Line("TRANSP", "TRANSPARENT")
+		w.End("VEVENT")
+	}
+
+	for _, e := range events {
+		writeEvent(w, e, now)
+	}
+
+	w.End("VCALENDAR")
+	return w.Flush()
+}
+
+// writeEvent 写入一个倒数日。2 月 29 日在平年按 2 月 28 日计算，与农历日期一样无法用 RRULE 表示，
+// 展开为上一年起 eventYears 年内的具体日期
+func writeEvent(w *Writer, e model.Event, now time.Time) {
+	day := func(uid string, date time.Time, rrule bool) {
+		w.Begin("VEVENT")
+		w.Line("UID", uid)
+		w.Time("DTSTAMP", now)
+		w.Date("DTSTART", date)
+		w.Date("DTEND", date.AddDate(0, 0, 1))
+		if rrule {
+			w.Line("RRULE", "FREQ=YEARLY")
+		}
+		w.Text("SUMMARY", e.Title)
+		w.Text("DESCRIPTION", e.DateText())
+		w.Line("TRANSP", "TRANSPARENT")
+		w.End("VEVENT")
+	}
+	uid := fmt.Sprintf("event-%d@%s", e.ID, uidDomain)
+	leapDay := e.Date.Month() == time.February && e.Date.Day() == 29
+	if !e.Yearly || (!e.Lunar && !leapDay) {
+		day(uid, e.Date, e.Yearly)
+		return
+	}
+	from := model.StartOfDay(now).AddDate(-1, 0, 0)
+	for _, d := range e.OccurrencesBetween(from, from.AddDate(eventYears, 0, 0)) {
+		day(fmt.Sprintf("event-%d-%s@%s", e.ID, d.Format(dateLayout), uidDomain), d, false)
+	}
+}
+
+// taskCategories 返回任务的全部标签，兼容只有旧版 Label 的任务
+func taskCategories(t model.Task) []string {
+	if len(t.Tags) > 0 {
+		return t.Tags
+	}
+	if t.Label != "" {
+		return []string{t.Label}
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package ics
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"tomato_clock/internal/model"
+)
+
+// feedFileName 订阅文件保存在用户目录下的文件名
+const feedFileName = ".tomato_clock.ics"
+
+// feedMu 保证同一时间只有一个协程在重写订阅文件
+var feedMu sync.Mutex
+
+// DefaultFeedPath 返回默认的订阅文件路径：$HOME/.tomato_clock.ics
+func DefaultFeedPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, feedFileName), nil
+}
+
+// WriteFile 使用当前数据生成日历并原子替换 path
+func WriteFile(path string, opts Options) error {
+	feedMu.Lock()
+	defer feedMu.Unlock()
+
+	tmpPath := path + ".tmp"
+	f, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+	if err := Build(f, model.AllTasks(), model.AllSessions(), model.AllEvents(time.Now()), opts); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// EnableFeed 立即生成一次订阅文件，并在每次数据变更后重新生成，
+// 日历客户端可以通过 file:// 地址订阅该文件。
+func EnableFeed(path string) error {
+	if err := WriteFile(path, Options{}); err != nil {
+		return err
+	}
+	model.OnChange(func() {
+		if err := WriteFile(path, Options{}); err != nil {
+			log.Printf("[ICS] 更新日历订阅文件失败: %v", err)
+		}
+	})
+	log.Printf("[ICS] 日历订阅文件: %s", path)
+	return nil
+}
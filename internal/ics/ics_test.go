@@ -0,0 +1,118 @@
+package ics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"tomato_clock/internal/model"
+)
+
+func TestEscapeAndFold(t *testing.T) {
+	if got := EscapeText("a,b;c\\d\ne"); got != `a\,b\;c\\d\ne` {
+		t.Fatalf("unexpected escape: %s", got)
+	}
+
+	line := "SUMMARY:" + strings.Repeat("番茄", 30)
+	folded := fold(line)
+	if !strings.HasSuffix(folded, "\r\n") {
+		t.Fatalf("folded line must end with CRLF")
+	}
+	parts := strings.Split(strings.TrimSuffix(folded, "\r\n"), "\r\n")
+	if len(parts) < 2 {
+		t.Fatalf("long line should be folded, got %q", folded)
+	}
+	var unfolded string
+	for i, p := range parts {
+		if len(p) > maxLineOctets {
+			t.Fatalf("physical line %d has %d octets", i, len(p))
+		}
+		if !utf8.ValidString(p) {
+			t.Fatalf("fold split a multi-byte character in line %d", i)
+		}
+		if i > 0 {
+			if p[0] != ' ' {
+				t.Fatalf("continuation line must start with a space")
+			}
+			p = p[1:]
+		}
+		unfolded += p
+	}
+	if unfolded != line {
+		t.Fatalf("unfolding should restore the original line")
+	}
+}
+
+func TestBuild(t *testing.T) {
+	id := int64(7)
+	due := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+	tasks := []model.Task{
+		{ID: id, Title: "写周报, 第一版", Label: "工作"},
+		{ID: 8, Title: "国庆", DueDate: &due, IsDone: true},
+	}
+	start := time.Date(2025, 7, 1, 9, 0, 0, 0, time.FixedZone("CST", 8*3600))
+	sessions := []model.TimerSession{
+		{ID: 1, TaskID: &id, Mode: "countdown", StartedAt: start, EndedAt: start.Add(25 * time.Minute), DurationSec: 1500},
+		{ID: 2, TaskID: &id, Mode: "countdown", StartedAt: start, EndedAt: start.Add(time.Minute), Interrupted: true},
+	}
+
+	var buf bytes.Buffer
+	if err := Build(&buf, tasks, sessions, nil, Options{DueAsTodo: true, Now: start}); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"UID:session-1@tomato-clock.local\r\n",
+		"DTSTART:20250701T010000Z\r\n",
+		`SUMMARY:🍅 写周报\, 第一版`,
+		"CATEGORIES:工作\r\n",
+		"BEGIN:VTODO\r\n",
+		"DUE;VALUE=DATE:20251001\r\n",
+		"STATUS:COMPLETED\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("calendar missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "session-2@") {
+		t.Fatalf("interrupted sessions should not be exported")
+	}
+}
+
+func TestBuildEvents(t *testing.T) {
+	date := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.Local) }
+	events := []model.Event{
+		{ID: 1, Title: "考试", Date: date(2025, 12, 20)},
+		{ID: 2, Title: "结婚纪念日", Date: date(2020, 5, 20), Yearly: true},
+		// 2000-09-12 为农历八月十五
+		{ID: 3, Title: "中秋", Date: date(2000, 9, 12), Yearly: true, Lunar: true},
+	}
+	var buf bytes.Buffer
+	if err := Build(&buf, nil, nil, events, Options{Now: date(2025, 9, 1)}); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"UID:event-1@tomato-clock.local\r\nDTSTAMP:",
+		"DTSTART;VALUE=DATE:20251220\r\nDTEND;VALUE=DATE:20251221\r\nSUMMARY:考试\r\n",
+		"DTSTART;VALUE=DATE:20200520\r\nDTEND;VALUE=DATE:20200521\r\nRRULE:FREQ=YEARLY\r\nSUMMARY:结婚纪念日\r\n",
+		// 农历日期每年不同，展开为具体日期：2024、2025、2026 年的中秋
+		"UID:event-3-20240917@tomato-clock.local\r\n",
+		"DTSTART;VALUE=DATE:20251006\r\n",
+		"DTSTART;VALUE=DATE:20260925\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("calendar missing %q:\n%s", want, out)
+		}
+	}
+	if n := strings.Count(out, "SUMMARY:中秋"); n != eventYears {
+		t.Fatalf("lunar event expanded to %d dates, want %d", n, eventYears)
+	}
+	if strings.Count(out, "RRULE") != 1 {
+		t.Fatalf("only the yearly solar event should use RRULE:\n%s", out)
+	}
+}
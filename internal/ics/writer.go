@@ -0,0 +1,111 @@
+package ics
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// maxLineOctets RFC 5545 §3.1：内容行不应超过 75 个字节（不含换行）
+const maxLineOctets = 75
+
+// 时间格式：UTC 日期时间与全天日期
+const (
+	utcLayout  = "20060102T150405Z"
+	dateLayout = "20060102"
+)
+
+// Writer 按 RFC 5545 输出内容行：CRLF 换行、长行折叠、文本转义
+type Writer struct {
+	w   *bufio.Writer
+	err error
+}
+
+// NewWriter 创建写入 w 的 Writer，写完后需调用 Flush
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: bufio.NewWriter(w)}
+}
+
+// Line 写入一行 "NAME:value"，value 原样输出（调用方负责转义）
+func (w *Writer) Line(name, value string) {
+	w.write(fold(name + ":" + value))
+}
+
+// Text 写入文本类型的属性，自动转义特殊字符
+func (w *Writer) Text(name, value string) {
+	w.Line(name, EscapeText(value))
+}
+
+// TextList 写入以逗号分隔的多值文本属性（如 CATEGORIES），每个值单独转义
+func (w *Writer) TextList(name string, values []string) {
+	escaped := make([]string, len(values))
+	for i, v := range values {
+		escaped[i] = EscapeText(v)
+	}
+	w.Line(name, strings.Join(escaped, ","))
+}
+
+// Time 写入 UTC 时间属性，如 DTSTART:20250701T010000Z
+func (w *Writer) Time(name string, t time.Time) {
+	w.Line(name, t.UTC().Format(utcLayout))
+}
+
+// Date 写入全天日期属性，如 DTSTART;VALUE=DATE:20250701
+func (w *Writer) Date(name string, t time.Time) {
+	w.Line(name+";VALUE=DATE", t.Format(dateLayout))
+}
+
+// Begin 写入 BEGIN:component
+func (w *Writer) Begin(component string) { w.Line("BEGIN", component) }
+
+// End 写入 END:component
+func (w *Writer) End(component string) { w.Line("END", component) }
+
+// Flush 刷新缓冲并返回写入过程中遇到的第一个错误
+func (w *Writer) Flush() error {
+	if w.err != nil {
+		return w.err
+	}
+	return w.w.Flush()
+}
+
+func (w *Writer) write(s string) {
+	if w.err != nil {
+		return
+	}
+	_, w.err = w.w.WriteString(s)
+}
+
+// EscapeText 按 RFC 5545 §3.3.11 转义 TEXT 值中的反斜杠、分号、逗号与换行
+func EscapeText(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", `\n`,
+	).Replace(s)
+}
+
+// fold 将内容行折叠为不超过 75 字节的物理行，续行以一个空格开头，
+// 折叠点不会落在 UTF-8 多字节字符中间。返回值包含结尾的 CRLF。
+func fold(line string) string {
+	var b strings.Builder
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		limit = maxLineOctets - 1 // 续行的前导空格占一个字节
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+	return b.String()
+}
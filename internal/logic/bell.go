@@ -0,0 +1,154 @@
+package logic
+
+import (
+	"log"
+	"math/rand"
+	"time"
+
+	"tomato_clock/internal/config"
+)
+
+// 正念铃声间隔的分布
+const (
+	BellFixed   = "fixed"   // 固定间隔
+	BellUniform = "uniform" // 在最短与最长间隔之间均匀随机
+	BellPoisson = "poisson" // 泊松过程：间隔服从指数分布，难以预料下一次何时响起
+)
+
+// minBellGap 泊松分布下两次铃声的最短间隔，避免接连响起
+const minBellGap = time.Minute
+
+// BellSchedule 正念铃声的调度方式，时间都按专注时长计算（暂停的时间不算）
+type BellSchedule struct {
+	Distribution string
+	Interval     time.Duration // 固定间隔，或泊松分布的平均间隔
+	Min, Max     time.Duration // 均匀分布的间隔范围
+	QuietStart   time.Duration // 开始后这段时间内不响
+	QuietEnd     time.Duration // 倒计时剩余不足这段时间时不再响
+	MaxCount     int           // 每次专注最多响几次，0 表示不限
+}
+
+// DefaultBellSchedule 默认每 5-10 分钟随机响一次，倒计时的最后 10 分钟不响
+func DefaultBellSchedule() BellSchedule {
+	return BellSchedule{
+		Distribution: BellUniform,
+		Interval:     8 * time.Minute,
+		Min:          5 * time.Minute,
+		Max:          10 * time.Minute,
+		QuietEnd:     10 * time.Minute,
+	}
+}
+
+// NewBellSchedule 由配置文件中的设置创建调度方式，未设置过时使用默认值
+func NewBellSchedule(c config.Bell) BellSchedule {
+	if c.Distribution == "" {
+		return DefaultBellSchedule()
+	}
+	minutes := func(n int) time.Duration {
+		return time.Duration(max(n, 0)) * time.Minute
+	}
+	s := BellSchedule{
+		Distribution: c.Distribution,
+		Interval:     minutes(c.Interval),
+		Min:          minutes(c.Min),
+		Max:          minutes(c.Max),
+		QuietStart:   minutes(c.QuietStart),
+		QuietEnd:     minutes(c.QuietEnd),
+		MaxCount:     max(c.MaxCount, 0),
+	}
+	if s.Max < s.Min {
+		s.Min, s.Max = s.Max, s.Min
+	}
+	if s.Interval <= 0 {
+		s.Interval = time.Minute
+	}
+	if s.Max <= 0 {
+		s.Max = time.Minute
+	}
+	return s
+}
+
+// draw 抽取下一次铃声前的间隔
+func (s BellSchedule) draw(r *rand.Rand) time.Duration {
+	switch s.Distribution {
+	case BellFixed:
+		return s.Interval
+	case BellPoisson:
+		return max(time.Duration(r.ExpFloat64()*float64(s.Interval)), minBellGap)
+	}
+	return s.Min + time.Duration(r.Int63n(int64(s.Max-s.Min)+1))
+}
+
+// BellPlanner 决定一次专注中每次铃声的时刻。第一次铃声在开头的安静期之后再抽取间隔；
+// 错过的铃声（如计时暂停）不补响，从响铃时起重新计算间隔
+type BellPlanner struct {
+	s     BellSchedule
+	r     *rand.Rand
+	next  time.Duration
+	count int
+}
+
+// NewBellPlanner 按调度方式创建一次专注的铃声计划，seed 为随机种子
+func NewBellPlanner(s BellSchedule, seed int64) *BellPlanner {
+	p := &BellPlanner{s: s, r: rand.New(rand.NewSource(seed))}
+	p.next = s.QuietStart + s.draw(p.r)
+	return p
+}
+
+// Next 返回下一次铃声的专注时长
+func (p *BellPlanner) Next() time.Duration { return p.next }
+
+// Count 返回已经响过的次数
+func (p *BellPlanner) Count() int { return p.count }
+
+// Due 判断专注了 elapsed 时是否该响铃，target 为倒计时的目标时长（正计时为 0）。
+// 返回 true 时计入次数并安排下一次
+func (p *BellPlanner) Due(elapsed, target time.Duration) bool {
+	if p.Finished(elapsed, target) || elapsed < p.next {
+		return false
+	}
+	p.count++
+	p.next = elapsed + p.s.draw(p.r)
+	return true
+}
+
+// Finished 返回本次专注是否不会再响铃：次数已满，或倒计时已进入结尾的安静期
+func (p *BellPlanner) Finished(elapsed, target time.Duration) bool {
+	if p.s.MaxCount > 0 && p.count >= p.s.MaxCount {
+		return true
+	}
+	return target > 0 && elapsed > target-p.s.QuietEnd
+}
+
+// RunBells 在计时过程中按计划响铃，直到 stop 关闭、计时结束或不会再响铃；
+// ring 收到第几次铃声与响铃时的专注秒数
+func RunBells(t *Timer, p *BellPlanner, stop <-chan struct{}, ring func(n, elapsedSec int)) {
+	target := time.Duration(0)
+	if t.Mode == ModeCountDown {
+		target = time.Duration(t.TargetSeconds) * time.Second
+	}
+	log.Printf("[RANDOM] 正念铃声调度已启动，模式=%s，目标时长=%d秒，第一次在专注 %v 时", t.Mode, t.TargetSeconds, p.Next())
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			log.Printf("[RANDOM] 收到取消信号，正念铃声调度结束，共响 %d 次", p.Count())
+			return
+		case <-ticker.C:
+			if t.Paused() {
+				continue
+			}
+			sec := t.ElapsedSeconds()
+			elapsed := time.Duration(sec) * time.Second
+			if p.Due(elapsed, target) {
+				log.Printf("[RANDOM] 第 %d 次正念铃声，专注 %d 秒，下一次在专注 %v 时", p.Count(), sec, p.Next())
+				ring(p.Count(), sec)
+			}
+			if p.Finished(elapsed, target) {
+				log.Printf("[RANDOM] 次数已满或进入结尾的安静期，正念铃声调度结束，共响 %d 次", p.Count())
+				return
+			}
+		}
+	}
+}
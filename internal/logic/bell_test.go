@@ -0,0 +1,76 @@
+package logic
+
+import (
+	"testing"
+	"time"
+
+	"tomato_clock/internal/config"
+)
+
+// simulate 按秒推进专注时长，返回每次铃声的时刻
+func simulate(p *BellPlanner, length, target time.Duration) []time.Duration {
+	var rings []time.Duration
+	for e := time.Duration(0); e <= length; e += time.Second {
+		if p.Due(e, target) {
+			rings = append(rings, e)
+		}
+	}
+	return rings
+}
+
+func TestBellFixed(t *testing.T) {
+	s := BellSchedule{Distribution: BellFixed, Interval: 5 * time.Minute, QuietStart: 2 * time.Minute, QuietEnd: 5 * time.Minute}
+	rings := simulate(NewBellPlanner(s, 1), time.Hour, 25*time.Minute)
+	want := []time.Duration{7 * time.Minute, 12 * time.Minute, 17 * time.Minute}
+	if len(rings) != len(want) {
+		t.Fatalf("rings = %v, want %v", rings, want)
+	}
+	for i := range want {
+		if rings[i] != want[i] {
+			t.Fatalf("rings = %v, want %v", rings, want)
+		}
+	}
+}
+
+func TestBellUniformAndCount(t *testing.T) {
+	s := BellSchedule{Distribution: BellUniform, Min: 5 * time.Minute, Max: 10 * time.Minute, MaxCount: 4}
+	for seed := int64(0); seed < 20; seed++ {
+		p := NewBellPlanner(s, seed)
+		rings := simulate(p, 3*time.Hour, 0)
+		if len(rings) != 4 || !p.Finished(3*time.Hour, 0) {
+			t.Fatalf("seed %d: rings = %v", seed, rings)
+		}
+		last := time.Duration(0)
+		for _, r := range rings {
+			if gap := r - last; gap < 5*time.Minute || gap > 10*time.Minute+time.Second {
+				t.Fatalf("seed %d: gap %v out of range, rings = %v", seed, gap, rings)
+			}
+			last = r
+		}
+	}
+}
+
+func TestBellPoisson(t *testing.T) {
+	s := BellSchedule{Distribution: BellPoisson, Interval: 5 * time.Minute}
+	rings := simulate(NewBellPlanner(s, 42), 100*time.Hour, 0)
+	// 平均间隔约 5 分钟，100 小时约 1200 次
+	if n := len(rings); n < 1000 || n > 1400 {
+		t.Fatalf("got %d bells in 100h, want about 1200", n)
+	}
+	for i := 1; i < len(rings); i++ {
+		if rings[i]-rings[i-1] < minBellGap {
+			t.Fatalf("bells %v and %v too close", rings[i-1], rings[i])
+		}
+	}
+}
+
+func TestNewBellSchedule(t *testing.T) {
+	if got := NewBellSchedule(config.Bell{Enabled: true}); got != DefaultBellSchedule() {
+		t.Fatalf("unset schedule = %+v, want default", got)
+	}
+	got := NewBellSchedule(config.Bell{Distribution: BellUniform, Min: 10, Max: 3, QuietEnd: -1, MaxCount: 2})
+	want := BellSchedule{Distribution: BellUniform, Interval: time.Minute, Min: 3 * time.Minute, Max: 10 * time.Minute, MaxCount: 2}
+	if got != want {
+		t.Fatalf("schedule = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,56 @@
+package logic
+
+import (
+	"sync"
+	"time"
+)
+
+// Snooze 倒计时结束后的贪睡：到时只再次提醒，不开始新的计时，也不产生专注记录。
+// 零值可直接使用，同一时间只有一次贪睡
+type Snooze struct {
+	mu    sync.Mutex
+	timer *time.Timer
+	until time.Time
+}
+
+// Start 在 d 之后调用 ring，之前未到时的贪睡被取消
+func (s *Snooze) Start(d time.Duration, ring func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.timer != nil {
+		s.timer.Stop()
+	}
+	var timer *time.Timer
+	timer = time.AfterFunc(d, func() {
+		s.mu.Lock()
+		current := s.timer == timer
+		if current {
+			s.timer = nil
+		}
+		s.mu.Unlock()
+		if current {
+			ring()
+		}
+	})
+	s.timer = timer
+	s.until = time.Now().Add(d)
+}
+
+// Cancel 取消未到时的贪睡，返回是否有贪睡被取消
+func (s *Snooze) Cancel() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.timer == nil {
+		return false
+	}
+	s.timer.Stop()
+	s.timer = nil
+	return true
+}
+
+// Until 返回贪睡结束的时刻，没有贪睡时 ok 为 false
+func (s *Snooze) Until() (until time.Time, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.until, s.timer != nil
+}
@@ -0,0 +1,50 @@
+package logic
+
+import (
+	"testing"
+	"time"
+
+	"tomato_clock/internal/model"
+)
+
+func TestSnoozeRingsWithoutSession(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	if err := model.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	var s Snooze
+	rang := make(chan struct{}, 2)
+	s.Start(20*time.Millisecond, func() { rang <- struct{}{} })
+	if _, ok := s.Until(); !ok {
+		t.Fatal("snooze not pending")
+	}
+	select {
+	case <-rang:
+	case <-time.After(time.Second):
+		t.Fatal("snooze did not ring")
+	}
+	if _, ok := s.Until(); ok || s.Cancel() {
+		t.Fatal("snooze still pending after ringing")
+	}
+	// 贪睡只再次提醒，不开始计时，也不写专注记录
+	if sessions := model.AllSessions(); len(sessions) != 0 {
+		t.Fatalf("snooze recorded sessions: %+v", sessions)
+	}
+}
+
+func TestSnoozeCancelAndRestart(t *testing.T) {
+	var s Snooze
+	rang := make(chan int, 3)
+	s.Start(20*time.Millisecond, func() { rang <- 1 })
+	if !s.Cancel() {
+		t.Fatal("cancel pending snooze")
+	}
+	// 重新贪睡时取代之前的一次
+	s.Start(time.Hour, func() { rang <- 2 })
+	s.Start(20*time.Millisecond, func() { rang <- 3 })
+	time.Sleep(100 * time.Millisecond)
+	if len(rang) != 1 || <-rang != 3 {
+		t.Fatal("only the latest snooze should ring")
+	}
+}
@@ -21,17 +21,20 @@ type Tick struct {
 // Timer 实现可暂停/继续的计数器
 // Start 后会每秒向 Chan 发送 Tick
 // Stop 后 Chan 会关闭
+// 已用时间按时钟计算，暂停的时长不计入
 
 type Timer struct {
 	Mode          string
 	TargetSeconds int
 
-	tickCh  chan Tick
-	stopCh  chan struct{}
-	pauseCh chan bool
+	tickCh   chan Tick
+	stopCh   chan struct{}
+	stopOnce sync.Once
+	now      func() time.Time
 
 	mu         sync.Mutex
-	startedAt  time.Time
+	startedAt  time.Time // 开始时刻，继续时顺延暂停的时长
+	pausedAt   time.Time
 	paused     bool
 	elapsedSec int
 }
@@ -43,7 +46,7 @@ func NewTimer(mode string, targetSeconds int) *Timer {
 		TargetSeconds: targetSeconds,
 		tickCh:        make(chan Tick, 1),
 		stopCh:        make(chan struct{}),
-		pauseCh:       make(chan bool),
+		now:           time.Now,
 	}
 }
 
@@ -52,15 +55,37 @@ func (t *Timer) Chan() <-chan Tick { return t.tickCh }
 
 // Start 启动计时协程
 func (t *Timer) Start() {
-	t.startedAt = time.Now()
+	t.startedAt = t.now()
 	go t.loop()
 }
 
-// Pause 暂停或继续
-func (t *Timer) Pause() { t.pauseCh <- true }
+// Pause 暂停或继续，返回后 Paused 即为新的状态；计时已结束时什么也不做
+func (t *Timer) Pause() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	select {
+	case <-t.stopCh:
+		return
+	default:
+	}
+	t.paused = !t.paused
+	// 暂停的时长不计入已用时间
+	if t.paused {
+		t.pausedAt = t.now()
+	} else {
+		t.startedAt = t.startedAt.Add(t.now().Sub(t.pausedAt))
+	}
+}
 
-// Stop 停止计时并关闭通道
-func (t *Timer) Stop() { close(t.stopCh) }
+// Paused 返回是否处于暂停状态（线程安全）
+func (t *Timer) Paused() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.paused
+}
+
+// Stop 停止计时并关闭通道；计时已结束或重复调用时什么也不做
+func (t *Timer) Stop() { t.stopOnce.Do(func() { close(t.stopCh) }) }
 
 func (t *Timer) loop() {
 	ticker := time.NewTicker(time.Second)
@@ -70,17 +95,13 @@ func (t *Timer) loop() {
 		case <-t.stopCh:
 			close(t.tickCh)
 			return
-		case <-t.pauseCh:
-			t.mu.Lock()
-			t.paused = !t.paused
-			t.mu.Unlock()
-		case now := <-ticker.C:
+		case <-ticker.C:
 			t.mu.Lock()
 			if t.paused {
 				t.mu.Unlock()
 				continue
 			}
-			t.elapsedSec = int(now.Sub(t.startedAt).Seconds())
+			t.elapsedSec = int(t.now().Sub(t.startedAt).Seconds())
 			elapsed := t.elapsedSec
 			remain := t.TargetSeconds - elapsed
 			done := false
@@ -92,7 +113,7 @@ func (t *Timer) loop() {
 			tick := Tick{ElapsedSeconds: elapsed, RemainSeconds: remain, Done: done}
 			t.tickCh <- tick
 			if done {
-				close(t.stopCh)
+				t.Stop()
 				close(t.tickCh)
 				return
 			}
@@ -0,0 +1,85 @@
+package logic
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock 测试用的时钟，由测试手动推进
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	c.mu.Unlock()
+}
+
+func newTestTimer(mode string, target int) (*Timer, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2025, 9, 1, 9, 0, 0, 0, time.Local)}
+	t := NewTimer(mode, target)
+	t.now = clock.Now
+	return t, clock
+}
+
+func TestTimerPauseExcludesPausedTime(t *testing.T) {
+	timer, clock := newTestTimer(ModeCountUp, 0)
+	timer.Start()
+	defer timer.Stop()
+
+	clock.Advance(10 * time.Second)
+	timer.Pause()
+	if !timer.Paused() {
+		t.Fatal("timer not paused")
+	}
+	clock.Advance(100 * time.Second)
+	timer.Pause()
+	if timer.Paused() {
+		t.Fatal("timer not resumed")
+	}
+	clock.Advance(5 * time.Second)
+
+	tick := <-timer.Chan()
+	if tick.ElapsedSeconds != 15 || timer.ElapsedSeconds() != 15 {
+		t.Fatalf("elapsed = %d, want 15 without the paused time", tick.ElapsedSeconds)
+	}
+}
+
+func TestTimerPauseAfterDone(t *testing.T) {
+	timer, clock := newTestTimer(ModeCountDown, 3)
+	timer.Start()
+	clock.Advance(5 * time.Second)
+
+	tick := <-timer.Chan()
+	if !tick.Done || tick.RemainSeconds != 0 {
+		t.Fatalf("tick = %+v, want done", tick)
+	}
+	if _, ok := <-timer.Chan(); ok {
+		t.Fatal("channel still open after done")
+	}
+
+	paused := make(chan struct{})
+	go func() {
+		timer.Pause()
+		close(paused)
+	}()
+	select {
+	case <-paused:
+	case <-time.After(time.Second):
+		t.Fatal("Pause blocked after the timer finished")
+	}
+	if timer.Paused() {
+		t.Fatal("finished timer reports paused")
+	}
+	// 结束后再停止不会重复关闭通道
+	timer.Stop()
+}
@@ -0,0 +1,181 @@
+package model
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"time"
+)
+
+// DefaultArchiveAfterDays 已完成任务默认在完成多少天后自动归档
+const DefaultArchiveAfterDays = 7
+
+// setCompletedLocked 设置完成状态并维护 CompletedAt：完成时记录时间（已有时保留），
+// 取消完成时清除完成时间并取消归档
+func setCompletedLocked(t *Task, done bool, now time.Time) {
+	t.IsDone = done
+	if !done {
+		t.CompletedAt = nil
+		t.Archived = false
+		t.UnarchivedAt = nil
+		return
+	}
+	if t.CompletedAt == nil {
+		completed := now
+		t.CompletedAt = &completed
+	}
+}
+
+// SetTaskDone 勾选或取消勾选任务的完成状态
+func SetTaskDone(id int64, done bool) error {
+	mu.Lock()
+	found := false
+	for i := range data.Tasks {
+		if data.Tasks[i].ID != id {
+			continue
+		}
+		t := &data.Tasks[i]
+		if t.IsDone == done {
+			mu.Unlock()
+			return nil
+		}
+		now := nowFunc()
+		setCompletedLocked(t, done, now)
+		t.UpdatedAt = now
+		found = true
+		break
+	}
+	mu.Unlock()
+	if !found {
+		return fmt.Errorf("task id %d not found", id)
+	}
+	log.Printf("[SetTaskDone] id=%d done=%v", id, done)
+	return Save()
+}
+
+// ArchiveCompleted 归档完成时间早于 now 往前 days 天的任务，返回新归档的数量。
+// 只有任务及其全部子任务都满足条件时才归档，避免未完成的子任务随父任务一起被隐藏。
+// 手动取消归档过的任务从取消归档时起重新计算。
+func ArchiveCompleted(now time.Time, days int) (int, error) {
+	if days <= 0 {
+		return 0, nil
+	}
+	cutoff := now.AddDate(0, 0, -days)
+	mu.Lock()
+	children := ChildIndex(data.Tasks)
+	byID := taskMapLocked()
+	var eligible func(id int64) bool
+	eligible = func(id int64) bool {
+		t := byID[id]
+		if !t.IsDone || t.CompletedAt == nil || t.CompletedAt.After(cutoff) {
+			return false
+		}
+		if t.UnarchivedAt != nil && t.UnarchivedAt.After(cutoff) {
+			return false
+		}
+		for _, c := range children[id] {
+			if !eligible(c) {
+				return false
+			}
+		}
+		return true
+	}
+	n := 0
+	for i := range data.Tasks {
+		if t := &data.Tasks[i]; !t.Archived && eligible(t.ID) {
+			t.Archived = true
+			n++
+		}
+	}
+	mu.Unlock()
+	if n == 0 {
+		return 0, nil
+	}
+	log.Printf("[Archive] 归档 %d 个已完成任务", n)
+	return n, Save()
+}
+
+// ArchivedTasks 返回已归档的任务，最近完成的在前
+func ArchivedTasks() []Task {
+	mu.Lock()
+	var res []Task
+	for _, t := range data.Tasks {
+		if t.Archived {
+			res = append(res, t)
+		}
+	}
+	mu.Unlock()
+	sort.SliceStable(res, func(i, j int) bool {
+		a, b := res[i].CompletedAt, res[j].CompletedAt
+		if a == nil || b == nil {
+			return a != nil
+		}
+		return a.After(*b)
+	})
+	return res
+}
+
+// UnarchiveTask 取消归档任务，使其重新显示在任务列表中。
+// 子任务与已归档的上级任务一起取消归档，否则任务在树中仍然不可见。
+func UnarchiveTask(id int64) error {
+	mu.Lock()
+	byID := taskMapLocked()
+	if _, ok := byID[id]; !ok {
+		mu.Unlock()
+		return fmt.Errorf("task id %d not found", id)
+	}
+	ids := map[int64]bool{}
+	for _, t := range TaskPath(byID, id) {
+		ids[t.ID] = true
+	}
+	for _, d := range descendantsLocked(id) {
+		ids[d] = true
+	}
+	now := nowFunc()
+	n := 0
+	for i := range data.Tasks {
+		if t := &data.Tasks[i]; ids[t.ID] && t.Archived {
+			t.Archived = false
+			unarchived := now
+			t.UnarchivedAt = &unarchived
+			n++
+		}
+	}
+	mu.Unlock()
+	log.Printf("[UnarchiveTask] id=%d 取消归档 %d 个任务", id, n)
+	return Save()
+}
+
+// CompletionDay 某一天完成的任务与专注时长
+type CompletionDay struct {
+	Date         time.Time
+	FocusSeconds int
+	Tasks        []Task // 按完成时间排序
+}
+
+// CompletionHistory 返回截至 now 所在当天、最近 days 天每天完成的任务与专注时长，最近的一天在前。
+// 已归档的任务同样计入。
+func CompletionHistory(now time.Time, days int) []CompletionDay {
+	today := StartOfDay(now.In(time.Local))
+	from := today.AddDate(0, 0, 1-days)
+	focus := DailyFocus(from, today.AddDate(0, 0, 1), "")
+
+	byDay := map[time.Time][]Task{}
+	for _, t := range AllTasks() {
+		if !t.IsDone || t.CompletedAt == nil {
+			continue
+		}
+		day := StartOfDay(t.CompletedAt.In(time.Local))
+		if !day.Before(from) && !day.After(today) {
+			byDay[day] = append(byDay[day], t)
+		}
+	}
+
+	var res []CompletionDay
+	for day := today; !day.Before(from); day = day.AddDate(0, 0, -1) {
+		tasks := byDay[day]
+		sort.Slice(tasks, func(i, j int) bool { return tasks[i].CompletedAt.Before(*tasks[j].CompletedAt) })
+		res = append(res, CompletionDay{Date: day, FocusSeconds: focus[day], Tasks: tasks})
+	}
+	return res
+}
@@ -0,0 +1,91 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSetTaskDoneAndArchive(t *testing.T) {
+	day := func(d int, h int) time.Time { return time.Date(2025, 9, d, h, 0, 0, 0, time.Local) }
+	useTestStore(t, dataFile{Tasks: []Task{
+		{ID: 1, Title: "父任务"},
+		{ID: 2, Title: "子任务", ParentID: 1},
+		{ID: 3, Title: "独立任务"},
+	}})
+
+	nowFunc = func() time.Time { return day(1, 9) }
+	for _, id := range []int64{1, 3} {
+		if err := SetTaskDone(id, true); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if data.Tasks[0].CompletedAt == nil || !data.Tasks[0].CompletedAt.Equal(day(1, 9)) {
+		t.Fatalf("CompletedAt not set: %+v", data.Tasks[0])
+	}
+
+	// 子任务未完成时父任务不归档
+	n, err := ArchiveCompleted(day(10, 9), 7)
+	if err != nil || n != 1 || !data.Tasks[2].Archived || data.Tasks[0].Archived {
+		t.Fatalf("archive: n=%d err=%v tasks=%+v", n, err, data.Tasks)
+	}
+
+	nowFunc = func() time.Time { return day(2, 20) }
+	if err := SetTaskDone(2, true); err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := ArchiveCompleted(day(10, 9), 7); n != 2 {
+		t.Fatalf("archive after child done: n=%d", n)
+	}
+
+	// 取消完成时清除完成时间并取消归档
+	if err := SetTaskDone(3, false); err != nil {
+		t.Fatal(err)
+	}
+	if tk := data.Tasks[2]; tk.IsDone || tk.CompletedAt != nil || tk.Archived {
+		t.Fatalf("undo: %+v", tk)
+	}
+
+	hist := CompletionHistory(day(3, 12), 3)
+	if len(hist) != 3 || !hist[0].Date.Equal(day(3, 0)) {
+		t.Fatalf("history days: %+v", hist)
+	}
+	if len(hist[1].Tasks) != 1 || hist[1].Tasks[0].ID != 2 || len(hist[2].Tasks) != 1 || hist[2].Tasks[0].ID != 1 {
+		t.Fatalf("history tasks: %+v", hist)
+	}
+}
+
+func TestUnarchiveTask(t *testing.T) {
+	day := func(d int) *time.Time {
+		v := time.Date(2025, 9, d, 9, 0, 0, 0, time.Local)
+		return &v
+	}
+	useTestStore(t, dataFile{Tasks: []Task{
+		{ID: 1, Title: "父任务", IsDone: true, CompletedAt: day(1), Archived: true},
+		{ID: 2, Title: "子任务", ParentID: 1, IsDone: true, CompletedAt: day(1), Archived: true},
+		{ID: 3, Title: "孙任务", ParentID: 2, IsDone: true, CompletedAt: day(1), Archived: true},
+		{ID: 4, Title: "较晚完成", IsDone: true, CompletedAt: day(3), Archived: true},
+	}})
+	if got := ArchivedTasks(); len(got) != 4 || got[0].ID != 4 {
+		t.Fatalf("archived = %+v", got)
+	}
+
+	// 取消归档子任务时，上级与下级任务一起显示
+	nowFunc = func() time.Time { return *day(20) }
+	if err := UnarchiveTask(2); err != nil {
+		t.Fatal(err)
+	}
+	if got := ArchivedTasks(); len(got) != 1 || got[0].ID != 4 {
+		t.Fatalf("archived after unarchive = %+v", got)
+	}
+
+	// 自动归档从取消归档时起重新计算
+	if n, _ := ArchiveCompleted(*day(22), 7); n != 0 {
+		t.Fatalf("re-archived %d tasks right after unarchive", n)
+	}
+	if n, _ := ArchiveCompleted(*day(28), 7); n != 3 {
+		t.Fatalf("archive a week after unarchive: n=%d", n)
+	}
+	if err := UnarchiveTask(99); err == nil {
+		t.Fatal("unarchive missing task should fail")
+	}
+}
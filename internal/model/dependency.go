@@ -0,0 +1,83 @@
+package model
+
+import "fmt"
+
+// errDependencyCycle 前置任务形成循环时返回的错误
+var errDependencyCycle = fmt.Errorf("前置任务不能形成循环依赖")
+
+// normalizeBlockedByLocked 去重并移除自身和不存在的前置任务，调用方需持有 mu
+func normalizeBlockedByLocked(t *Task) {
+	if len(t.BlockedBy) == 0 {
+		t.BlockedBy = nil
+		return
+	}
+	tasks := taskMapLocked()
+	seen := map[int64]bool{}
+	var res []int64
+	for _, id := range t.BlockedBy {
+		if id == t.ID || seen[id] {
+			continue
+		}
+		if _, ok := tasks[id]; !ok {
+			continue
+		}
+		seen[id] = true
+		res = append(res, id)
+	}
+	t.BlockedBy = res
+}
+
+// dependencyCycleLocked 判断任务 id 以 blockedBy 为前置任务时是否会形成循环，调用方需持有 mu
+func dependencyCycleLocked(id int64, blockedBy []int64) bool {
+	tasks := taskMapLocked()
+	visited := map[int64]bool{}
+	stack := append([]int64(nil), blockedBy...)
+	for len(stack) > 0 {
+		cur := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if cur == id {
+			return true
+		}
+		if visited[cur] {
+			continue
+		}
+		visited[cur] = true
+		stack = append(stack, tasks[cur].BlockedBy...)
+	}
+	return false
+}
+
+// removeBlockersLocked 从其它任务的前置任务中移除已删除的任务，调用方需持有 mu
+func removeBlockersLocked(ids map[int64]bool) {
+	for i := range data.Tasks {
+		t := &data.Tasks[i]
+		var kept []int64
+		for _, b := range t.BlockedBy {
+			if !ids[b] {
+				kept = append(kept, b)
+			}
+		}
+		t.BlockedBy = kept
+	}
+}
+
+// Blockers 返回阻塞任务 t 的未完成前置任务，按 BlockedBy 中的顺序排列
+func Blockers(t Task, byID map[int64]Task) []Task {
+	var res []Task
+	for _, id := range t.BlockedBy {
+		if b, ok := byID[id]; ok && !b.IsDone {
+			res = append(res, b)
+		}
+	}
+	return res
+}
+
+// IsBlocked 判断任务是否还有未完成的前置任务
+func IsBlocked(t Task, byID map[int64]Task) bool {
+	return len(Blockers(t, byID)) > 0
+}
+
+// IsNextAction 判断任务是否可以立即开始：未完成且没有未完成的前置任务
+func IsNextAction(t Task, byID map[int64]Task) bool {
+	return !t.IsDone && !IsBlocked(t, byID)
+}
@@ -0,0 +1,72 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTaskDependencies(t *testing.T) {
+	useTestStore(t, dataFile{NextTaskID: 1, NextSessionID: 1})
+
+	design := &Task{Title: "设计"}
+	if err := AddTask(design); err != nil {
+		t.Fatal(err)
+	}
+	code := &Task{Title: "编码", BlockedBy: []int64{design.ID, design.ID, 999}}
+	if err := AddTask(code); err != nil {
+		t.Fatal(err)
+	}
+	if len(code.BlockedBy) != 1 || code.BlockedBy[0] != design.ID {
+		t.Fatalf("BlockedBy should be normalized, got %v", code.BlockedBy)
+	}
+	test := &Task{Title: "测试", BlockedBy: []int64{code.ID}}
+	if err := AddTask(test); err != nil {
+		t.Fatal(err)
+	}
+
+	// 设计 <- 编码 <- 测试，让设计依赖测试会形成循环
+	cyclic := *design
+	cyclic.BlockedBy = []int64{test.ID}
+	if err := UpdateTask(cyclic); err == nil {
+		t.Fatal("expected dependency cycle error")
+	}
+	self := *design
+	self.BlockedBy = []int64{design.ID}
+	if err := UpdateTask(self); err != nil {
+		t.Fatal(err)
+	}
+
+	byID := map[int64]Task{}
+	for _, task := range AllTasks() {
+		byID[task.ID] = task
+	}
+	if len(byID[design.ID].BlockedBy) != 0 {
+		t.Fatalf("self dependency should be dropped, got %v", byID[design.ID].BlockedBy)
+	}
+	if b := Blockers(byID[code.ID], byID); len(b) != 1 || b[0].ID != design.ID {
+		t.Fatalf("blockers of code = %+v", b)
+	}
+	next := FilterTasks(AllTasks(), TaskFilter{NextActions: true}, time.Now())
+	if !next[design.ID] || next[code.ID] || next[test.ID] {
+		t.Fatalf("next actions = %v", next)
+	}
+
+	// 完成前置任务后解除阻塞
+	if err := SetTaskDone(design.ID, true); err != nil {
+		t.Fatal(err)
+	}
+	next = FilterTasks(AllTasks(), TaskFilter{NextActions: true}, time.Now())
+	if next[design.ID] || !next[code.ID] || next[test.ID] {
+		t.Fatalf("next actions after done = %v", next)
+	}
+
+	// 删除任务时从其它任务的前置任务中移除
+	if err := DeleteTask(code.ID); err != nil {
+		t.Fatal(err)
+	}
+	for _, task := range AllTasks() {
+		if task.ID == test.ID && len(task.BlockedBy) != 0 {
+			t.Fatalf("deleted blocker should be removed, got %v", task.BlockedBy)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package model
+
+import "sort"
+
+// PomodoroSeconds 一个标准番茄钟的时长（秒），用于将专注时长折算为番茄数
+const PomodoroSeconds = 25 * 60
+
+// TaskFocus 汇总单个任务的实际投入，Pomodoros/FocusSeconds 包含全部子任务
+type TaskFocus struct {
+	TaskID       int64
+	Pomodoros    int // 完成的番茄数（含子任务）
+	FocusSeconds int // 专注总秒数（含子任务）
+
+	OwnPomodoros    int // 直接记在该任务上的番茄数
+	OwnFocusSeconds int // 直接记在该任务上的专注秒数
+}
+
+// EstimateAccuracy 表示一组已完成任务的预估与实际对比
+type EstimateAccuracy struct {
+	Label              string
+	Tasks              int     // 参与统计的任务数
+	EstimatedPomodoros int     // 预估番茄总数
+	ActualPomodoros    int     // 实际番茄总数
+	Overran            int     // 实际超出预估的任务数（低估）
+	Underran           int     // 实际少于预估的任务数（高估）
+	Exact              int     // 实际与预估相同的任务数
+	Ratio              float64 // 实际/预估，1 表示完全准确
+}
+
+// EstimateReport 按标签汇总的预估准确度报告
+type EstimateReport struct {
+	ByLabel []EstimateAccuracy
+	Total   EstimateAccuracy
+}
+
+// sessionPomodoros 计算一条已完成计时记录折合的番茄数：
+// 正计时与倒计时都按实际专注时长折算，四舍五入到整数个 PomodoroSeconds。
+func sessionPomodoros(s TimerSession) int {
+	if s.Interrupted || s.EndedAt.IsZero() {
+		return 0
+	}
+	return (s.DurationSec + PomodoroSeconds/2) / PomodoroSeconds
+}
+
+// taskFocusLocked 在持有 mu 的情况下统计每个任务的实际投入，子任务的投入累加到各级父任务
+func taskFocusLocked() map[int64]TaskFocus {
+	res := map[int64]TaskFocus{}
+	tasks := taskMapLocked()
+	for _, s := range data.Sessions {
+		if s.TaskID == nil || s.Interrupted || s.EndedAt.IsZero() {
+			continue
+		}
+		p := sessionPomodoros(s)
+		own := res[*s.TaskID]
+		own.TaskID = *s.TaskID
+		own.OwnPomodoros += p
+		own.OwnFocusSeconds += s.DurationSec
+		res[*s.TaskID] = own
+
+		path := TaskPath(tasks, *s.TaskID)
+		if len(path) == 0 {
+			// 任务已不存在，只记在原 ID 上
+			path = []Task{{ID: *s.TaskID}}
+		}
+		for _, t := range path {
+			f := res[t.ID]
+			f.TaskID = t.ID
+			f.Pomodoros += p
+			f.FocusSeconds += s.DurationSec
+			res[t.ID] = f
+		}
+	}
+	return res
+}
+
+// TaskFocusStats 返回所有任务的实际番茄数与专注秒数（键为任务 ID）
+func TaskFocusStats() map[int64]TaskFocus {
+	mu.Lock()
+	defer mu.Unlock()
+	return taskFocusLocked()
+}
+
+// FocusForTask 返回指定任务的实际投入
+func FocusForTask(id int64) TaskFocus {
+	f := TaskFocusStats()[id]
+	f.TaskID = id
+	return f
+}
+
+// estimatedActualLocked 在持有 mu 的情况下统计每个设置了预估的任务的实际番茄数：
+// 每条记录只计入自身或最近一个设置了预估的上级任务，
+// 没有预估的子任务算在父任务的预估里，有预估的子任务单独统计，不会重复计算
+func estimatedActualLocked() map[int64]int {
+	res := map[int64]int{}
+	tasks := taskMapLocked()
+	for _, s := range data.Sessions {
+		if s.TaskID == nil {
+			continue
+		}
+		p := sessionPomodoros(s)
+		if p == 0 {
+			continue
+		}
+		path := TaskPath(tasks, *s.TaskID)
+		for i := len(path) - 1; i >= 0; i-- {
+			if path[i].EstimatePomodoros > 0 {
+				res[path[i].ID] += p
+				break
+			}
+		}
+	}
+	return res
+}
+
+// EstimateAccuracyReport 统计已完成且设置了预估的任务，按标签汇总预估准确度
+func EstimateAccuracyReport() EstimateReport {
+	mu.Lock()
+	defer mu.Unlock()
+
+	actuals := estimatedActualLocked()
+	byLabel := map[string]*EstimateAccuracy{}
+	report := EstimateReport{Total: EstimateAccuracy{Label: "全部"}}
+
+	for _, t := range data.Tasks {
+		if !t.IsDone || t.EstimatePomodoros <= 0 {
+			continue
+		}
+		label := t.Label
+		if label == "" {
+			label = DefaultLabel
+		}
+		acc, ok := byLabel[label]
+		if !ok {
+			acc = &EstimateAccuracy{Label: label}
+			byLabel[label] = acc
+		}
+		actual := actuals[t.ID]
+		for _, a := range []*EstimateAccuracy{acc, &report.Total} {
+			a.Tasks++
+			a.EstimatedPomodoros += t.EstimatePomodoros
+			a.ActualPomodoros += actual
+			switch {
+			case actual > t.EstimatePomodoros:
+				a.Overran++
+			case actual < t.EstimatePomodoros:
+				a.Underran++
+			default:
+				a.Exact++
+			}
+		}
+	}
+
+	for _, acc := range byLabel {
+		acc.Ratio = float64(acc.ActualPomodoros) / float64(acc.EstimatedPomodoros)
+		report.ByLabel = append(report.ByLabel, *acc)
+	}
+	if report.Total.EstimatedPomodoros > 0 {
+		report.Total.Ratio = float64(report.Total.ActualPomodoros) / float64(report.Total.EstimatedPomodoros)
+	}
+	sort.Slice(report.ByLabel, func(i, j int) bool { return report.ByLabel[i].Label < report.ByLabel[j].Label })
+	return report
+}
@@ -0,0 +1,103 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTaskFocusAndEstimateAccuracy(t *testing.T) {
+	now := time.Date(2025, 7, 3, 12, 0, 0, 0, time.UTC)
+	id1, id2 := int64(1), int64(2)
+
+	mu.Lock()
+	data.Tasks = []Task{
+		{ID: id1, Title: "写周报", Label: "工作", IsDone: true, EstimatePomodoros: 2},
+		{ID: id2, Title: "背单词", Label: "学习", IsDone: true, EstimatePomodoros: 3},
+		{ID: 3, Title: "未完成", Label: "学习", EstimatePomodoros: 5},
+	}
+	data.Sessions = []TimerSession{
+		// 两个完成的倒计时 + 一个 50 分钟正计时 => 4 个番茄
+		{ID: 1, TaskID: &id1, Mode: "countdown", StartedAt: now.Add(-3 * time.Hour), EndedAt: now.Add(-170 * time.Minute), DurationSec: 1500},
+		{ID: 2, TaskID: &id1, Mode: "countdown", StartedAt: now.Add(-2 * time.Hour), EndedAt: now.Add(-110 * time.Minute), DurationSec: 1500},
+		{ID: 3, TaskID: &id1, Mode: "countup", StartedAt: now.Add(-time.Hour), EndedAt: now.Add(-10 * time.Minute), DurationSec: 3000},
+		// 中断的记录不计入
+		{ID: 4, TaskID: &id2, Mode: "countdown", StartedAt: now.Add(-time.Hour), EndedAt: now.Add(-50 * time.Minute), Interrupted: true, DurationSec: 600},
+		{ID: 5, TaskID: &id2, Mode: "countdown", StartedAt: now.Add(-30 * time.Minute), EndedAt: now.Add(-5 * time.Minute), DurationSec: 1500},
+	}
+	mu.Unlock()
+
+	f := FocusForTask(id1)
+	if f.Pomodoros != 4 || f.FocusSeconds != 6000 {
+		t.Fatalf("task1 expected 4 pomodoros / 6000s, got %d / %d", f.Pomodoros, f.FocusSeconds)
+	}
+
+	report := EstimateAccuracyReport()
+	if len(report.ByLabel) != 2 {
+		t.Fatalf("expected 2 labels, got %d", len(report.ByLabel))
+	}
+	// 按标签字符串排序：学习 在 工作 之前
+	study, work := report.ByLabel[0], report.ByLabel[1]
+	if work.Label != "工作" || work.Overran != 1 || work.Ratio != 2 {
+		t.Fatalf("unexpected 工作 accuracy: %+v", work)
+	}
+	if study.Label != "学习" || study.Tasks != 1 || study.Underran != 1 {
+		t.Fatalf("unexpected 学习 accuracy: %+v", study)
+	}
+	if report.Total.Tasks != 2 || report.Total.EstimatedPomodoros != 5 || report.Total.ActualPomodoros != 5 {
+		t.Fatalf("unexpected total: %+v", report.Total)
+	}
+}
+
+func TestEstimateAccuracyWithSubtasks(t *testing.T) {
+	now := time.Date(2025, 7, 3, 12, 0, 0, 0, time.UTC)
+	parent, child, plain := int64(1), int64(2), int64(3)
+
+	mu.Lock()
+	data.Tasks = []Task{
+		{ID: parent, Title: "写论文", Label: "学习", IsDone: true, EstimatePomodoros: 2},
+		{ID: child, ParentID: parent, Title: "查文献", Label: "学习", IsDone: true, EstimatePomodoros: 3},
+		{ID: plain, ParentID: parent, Title: "排版", Label: "学习", IsDone: true},
+	}
+	session := func(id int64, taskID *int64) TimerSession {
+		start := now.Add(-time.Duration(id) * time.Hour)
+		return TimerSession{ID: id, TaskID: taskID, Mode: "countdown", StartedAt: start, EndedAt: start.Add(25 * time.Minute), DurationSec: 1500}
+	}
+	data.Sessions = []TimerSession{
+		session(1, &parent),
+		session(2, &child), session(3, &child), session(4, &child),
+		session(5, &plain), // 没有预估的子任务算在父任务上
+	}
+	mu.Unlock()
+
+	// 任务树中的投入仍然包含全部子任务
+	if f := FocusForTask(parent); f.Pomodoros != 5 || f.OwnPomodoros != 1 {
+		t.Fatalf("parent focus = %+v", f)
+	}
+
+	// 预估准确度中有预估的子任务不再重复计入父任务
+	total := EstimateAccuracyReport().Total
+	if total.Tasks != 2 || total.EstimatedPomodoros != 5 || total.ActualPomodoros != 5 || total.Exact != 2 || total.Overran != 0 {
+		t.Fatalf("total = %+v", total)
+	}
+}
+
+func TestSessionPomodoros(t *testing.T) {
+	end := time.Date(2025, 7, 3, 12, 0, 0, 0, time.UTC)
+	for _, c := range []struct {
+		mode string
+		sec  int
+		want int
+	}{
+		{"countdown", 5 * 60, 0},
+		{"countdown", 25 * 60, 1},
+		{"countdown", 90 * 60, 4},
+		{"countup", 90 * 60, 4},
+		{"countup", 12 * 60, 0},
+		{"countup", 13 * 60, 1},
+	} {
+		s := TimerSession{Mode: c.mode, EndedAt: end, DurationSec: c.sec}
+		if got := sessionPomodoros(s); got != c.want {
+			t.Errorf("%s %d min = %d pomodoros, want %d", c.mode, c.sec/60, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,401 @@
+package model
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Event 倒数日/纪念日，独立于任务保存
+type Event struct {
+	ID    int64  `json:"id"`
+	Title string `json:"title"`
+	// Date 事件的公历日期（本地零点）；农历事件为首次发生的那一天
+	Date time.Time `json:"date"`
+	// Lunar 为 true 时每年按农历日期（如八月十五）计算
+	Lunar bool `json:"lunar,omitempty"`
+	// Yearly 为 true 时每年重复，否则为一次性事件，过去后显示已经过了多少天
+	Yearly bool `json:"yearly,omitempty"`
+	// Pinned 置顶显示，同一时间只有一个事件置顶
+	Pinned    bool      `json:"pinned,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// EventStatus 事件相对于某一天的状态
+type EventStatus struct {
+	Next  time.Time // 下一次（或唯一一次）的公历日期
+	Days  int       // 距 Next 的天数；Past 为 true 时为已经过去的天数
+	Past  bool      // 一次性事件已经过去
+	Years int       // 每年重复的事件在 Next 时是第几周年
+}
+
+// daysBetween 返回两个日期之间相差的自然日数（忽略时刻与夏令时）
+func daysBetween(from, to time.Time) int {
+	a := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	b := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	return int(b.Sub(a).Hours() / 24)
+}
+
+// occurrenceIn 返回每年重复的事件在第 year 年的日期（农历事件为农历年）。
+// 公历 2 月 29 日在平年取 2 月 28 日；农历闰月在没有该闰月的年份取同名的普通月，
+// 三十在小月取廿九。
+func (e Event) occurrenceIn(year int) (time.Time, error) {
+	loc := e.Date.Location()
+	if !e.Lunar {
+		d := time.Date(year, e.Date.Month(), e.Date.Day(), 0, 0, 0, 0, loc)
+		if d.Month() != e.Date.Month() {
+			d = time.Date(year, e.Date.Month()+1, 0, 0, 0, 0, 0, loc)
+		}
+		return d, nil
+	}
+	ld, err := SolarToLunar(e.Date)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if year < lunarMinYear || year > lunarMaxYear {
+		return time.Time{}, fmt.Errorf("农历换算仅支持 %d-%d 年", lunarMinYear, lunarMaxYear)
+	}
+	ld.Year = year
+	if ld.Leap && lunarLeapMonth(year) != ld.Month {
+		ld.Leap = false
+	}
+	if n := lunarMonthDays(year, ld.Month, ld.Leap); ld.Day > n {
+		ld.Day = n
+	}
+	return LunarToSolar(ld, loc)
+}
+
+// OccurrencesBetween 返回事件在 [from, to) 之间发生的日期，按时间先后排列；
+// 一次性事件至多一个，农历事件超出换算范围的年份不返回
+func (e Event) OccurrencesBetween(from, to time.Time) []time.Time {
+	if !e.Yearly {
+		if !e.Date.Before(from) && e.Date.Before(to) {
+			return []time.Time{e.Date}
+		}
+		return nil
+	}
+	first, last := from.Year()-1, to.Year()
+	if e.Lunar {
+		// 农历年跨公历年，多取一年再按日期过滤
+		first, last = max(first, lunarMinYear), min(last, lunarMaxYear)
+	}
+	var res []time.Time
+	for y := first; y <= last; y++ {
+		d, err := e.occurrenceIn(y)
+		if err != nil || d.Before(e.Date) || d.Before(from) || !d.Before(to) {
+			continue
+		}
+		res = append(res, d)
+	}
+	return res
+}
+
+// Status 返回事件相对于 now 所在当天的状态
+func (e Event) Status(now time.Time) EventStatus {
+	today := StartOfDay(now.In(e.Date.Location()))
+	if !e.Yearly || today.Before(e.Date) {
+		days := daysBetween(today, e.Date)
+		if days < 0 {
+			return EventStatus{Next: e.Date, Days: -days, Past: true}
+		}
+		return EventStatus{Next: e.Date, Days: days}
+	}
+
+	start := e.Date.Year()
+	year := today.Year()
+	if e.Lunar {
+		ld, err := SolarToLunar(e.Date)
+		tl, err2 := SolarToLunar(today)
+		if err != nil || err2 != nil {
+			return EventStatus{Next: e.Date, Days: daysBetween(e.Date, today), Past: true}
+		}
+		start, year = ld.Year, tl.Year
+	}
+	// 本年（农历年）的日子已过时取下一年
+	for y := year - 1; y <= year+1; y++ {
+		d, err := e.occurrenceIn(y)
+		if err != nil {
+			break
+		}
+		if !d.Before(today) {
+			return EventStatus{Next: d, Days: daysBetween(today, d), Years: y - start}
+		}
+	}
+	return EventStatus{Next: e.Date, Days: daysBetween(e.Date, today), Past: true}
+}
+
+// DateText 返回事件日期的说明，如 "2025-10-01"、"农历八月十五（每年）"
+func (e Event) DateText() string {
+	text := e.Date.Format("2006-01-02")
+	if e.Lunar {
+		if ld, err := SolarToLunar(e.Date); err == nil {
+			if e.Yearly {
+				text = "农历" + ld.String()
+			} else {
+				text = fmt.Sprintf("%s（农历%s）", text, ld)
+			}
+		}
+	} else if e.Yearly {
+		text = e.Date.Format("每年01-02")
+	}
+	return text
+}
+
+func nextEventID() int64 {
+	if data.NextEventID == 0 {
+		data.NextEventID = 1
+	}
+	id := data.NextEventID
+	data.NextEventID++
+	return id
+}
+
+// normalizeEvent 校验并整理事件字段
+func normalizeEvent(e *Event) error {
+	e.Title = strings.TrimSpace(e.Title)
+	if e.Title == "" {
+		return fmt.Errorf("事件标题不能为空")
+	}
+	if e.Date.IsZero() {
+		return fmt.Errorf("事件日期不能为空")
+	}
+	e.Date = StartOfDay(e.Date)
+	if e.Lunar {
+		if _, err := SolarToLunar(e.Date); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddEvent 新建倒数日
+func AddEvent(e *Event) error {
+	if err := normalizeEvent(e); err != nil {
+		return err
+	}
+	mu.Lock()
+	now := time.Now()
+	e.ID = nextEventID()
+	e.CreatedAt = now
+	e.UpdatedAt = now
+	if e.Pinned {
+		unpinEventsLocked()
+	}
+	data.Events = append(data.Events, *e)
+	mu.Unlock()
+	if err := Save(); err != nil {
+		return err
+	}
+	log.Printf("[AddEvent] id=%d title=%s", e.ID, e.Title)
+	return nil
+}
+
+// UpdateEvent 修改倒数日
+func UpdateEvent(e Event) error {
+	if err := normalizeEvent(&e); err != nil {
+		return err
+	}
+	mu.Lock()
+	found := false
+	for i := range data.Events {
+		if data.Events[i].ID != e.ID {
+			continue
+		}
+		e.CreatedAt = data.Events[i].CreatedAt
+		e.UpdatedAt = time.Now()
+		if e.Pinned {
+			unpinEventsLocked()
+		}
+		data.Events[i] = e
+		found = true
+		break
+	}
+	mu.Unlock()
+	if !found {
+		return fmt.Errorf("event id %d not found", e.ID)
+	}
+	log.Printf("[UpdateEvent] id=%d title=%s", e.ID, e.Title)
+	return Save()
+}
+
+// DeleteEvent 删除倒数日
+func DeleteEvent(id int64) error {
+	mu.Lock()
+	var kept []Event
+	for _, e := range data.Events {
+		if e.ID != id {
+			kept = append(kept, e)
+		}
+	}
+	data.Events = kept
+	mu.Unlock()
+	log.Printf("[DeleteEvent] id=%d", id)
+	return Save()
+}
+
+// PinEvent 置顶指定事件并取消其它事件的置顶，id 为 0 时取消全部置顶
+func PinEvent(id int64) error {
+	mu.Lock()
+	unpinEventsLocked()
+	for i := range data.Events {
+		if data.Events[i].ID == id {
+			data.Events[i].Pinned = true
+		}
+	}
+	mu.Unlock()
+	return Save()
+}
+
+func unpinEventsLocked() {
+	for i := range data.Events {
+		data.Events[i].Pinned = false
+	}
+}
+
+// AllEvents 返回全部倒数日，按距 now 的下一次日期排序，已过去的一次性事件排在最后
+func AllEvents(now time.Time) []Event {
+	mu.Lock()
+	res := make([]Event, len(data.Events))
+	copy(res, data.Events)
+	mu.Unlock()
+	sort.SliceStable(res, func(i, j int) bool {
+		a, b := res[i].Status(now), res[j].Status(now)
+		if a.Past != b.Past {
+			return !a.Past
+		}
+		if a.Past {
+			return a.Days < b.Days
+		}
+		return a.Next.Before(b.Next)
+	})
+	return res
+}
+
+// TopEvent 返回置顶的事件；没有置顶时返回最近的未过去事件
+func TopEvent(now time.Time) (Event, bool) {
+	events := AllEvents(now)
+	for _, e := range events {
+		if e.Pinned {
+			return e, true
+		}
+	}
+	if len(events) > 0 && !events[0].Status(now).Past {
+		return events[0], true
+	}
+	return Event{}, false
+}
+
+// isLegacyCountdown 判断任务是否像旧版“新建倒数日”创建的：未完成、不重复、没有备注，
+// 截止日期为某天零点，既没有子任务也没有专注记录
+func isLegacyCountdown(t Task, children map[int64][]int64, focused map[int64]bool) bool {
+	if t.IsDone || t.DueDate == nil || t.Note != "" || t.ParentID != 0 {
+		return false
+	}
+	if t.RepeatRule != "" && t.RepeatRule != RepeatNone {
+		return false
+	}
+	return t.DueDate.Equal(StartOfDay(*t.DueDate)) && len(children[t.ID]) == 0 && !focused[t.ID]
+}
+
+// legacyCountdownIDs 返回 d 中像旧版倒数日的任务
+func legacyCountdownIDs(d *dataFile) []int64 {
+	focused := map[int64]bool{}
+	for _, s := range d.Sessions {
+		if s.TaskID != nil {
+			focused[*s.TaskID] = true
+		}
+	}
+	children := ChildIndex(d.Tasks)
+	var ids []int64
+	for _, t := range d.Tasks {
+		if isLegacyCountdown(t, children, focused) {
+			ids = append(ids, t.ID)
+		}
+	}
+	return ids
+}
+
+// convertTasksToEvents 把 d 中指定的任务转换为一次性倒数日并删除原任务，返回转换的数量
+func convertTasksToEvents(d *dataFile, ids []int64, now time.Time) int {
+	convert := map[int64]bool{}
+	for _, id := range ids {
+		convert[id] = true
+	}
+	if d.NextEventID == 0 {
+		d.NextEventID = 1
+	}
+	var kept []Task
+	n := 0
+	for _, t := range d.Tasks {
+		if !convert[t.ID] || t.DueDate == nil {
+			kept = append(kept, t)
+			continue
+		}
+		d.Events = append(d.Events, Event{
+			ID:        d.NextEventID,
+			Title:     t.Title,
+			Date:      StartOfDay(*t.DueDate),
+			CreatedAt: t.CreatedAt,
+			UpdatedAt: now,
+		})
+		d.NextEventID++
+		n++
+	}
+	d.Tasks = kept
+	for i := range d.Tasks {
+		var blockers []int64
+		for _, b := range d.Tasks[i].BlockedBy {
+			if !convert[b] {
+				blockers = append(blockers, b)
+			}
+		}
+		d.Tasks[i].BlockedBy = blockers
+	}
+	var reminders []ReminderLog
+	for _, r := range d.Reminders {
+		if !convert[r.TaskID] {
+			reminders = append(reminders, r)
+		}
+	}
+	d.Reminders = reminders
+	return n
+}
+
+// LegacyCountdownTasks 返回升级时记录下来、可能是旧版倒数日的任务，已经确认过时返回 nil
+func LegacyCountdownTasks() []Task {
+	mu.Lock()
+	defer mu.Unlock()
+	byID := taskMapLocked()
+	var res []Task
+	for _, id := range data.LegacyCountdowns {
+		if t, ok := byID[id]; ok && t.DueDate != nil && !t.IsDone {
+			res = append(res, t)
+		}
+	}
+	return res
+}
+
+// ResolveLegacyCountdowns 把用户选中的旧版倒数日任务转换为倒数日，其余任务保持不变，
+// 此后不再询问；返回转换的数量。
+// 询问期间有了子任务或专注记录的任务不再转换，以免删除后留下无主的子任务与记录
+func ResolveLegacyCountdowns(convert []int64) (int, error) {
+	mu.Lock()
+	eligible := map[int64]bool{}
+	for _, id := range legacyCountdownIDs(&data) {
+		eligible[id] = true
+	}
+	var ids []int64
+	for _, id := range convert {
+		if eligible[id] {
+			ids = append(ids, id)
+		}
+	}
+	n := convertTasksToEvents(&data, ids, time.Now())
+	data.LegacyCountdowns = nil
+	mu.Unlock()
+	log.Printf("[ResolveLegacyCountdowns] 转换 %d 个任务为倒数日", n)
+	return n, Save()
+}
@@ -0,0 +1,143 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEventStatus(t *testing.T) {
+	date := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.Local) }
+	now := date(2025, 9, 10).Add(15 * time.Hour)
+
+	cases := []struct {
+		name string
+		e    Event
+		want EventStatus
+	}{
+		{"一次性，未来", Event{Date: date(2025, 10, 1)}, EventStatus{Next: date(2025, 10, 1), Days: 21}},
+		{"一次性，已过去", Event{Date: date(2025, 9, 1)}, EventStatus{Next: date(2025, 9, 1), Days: 9, Past: true}},
+		{"每年，今年已过", Event{Date: date(2020, 3, 1), Yearly: true}, EventStatus{Next: date(2026, 3, 1), Days: 172, Years: 6}},
+		{"每年，今天", Event{Date: date(2015, 9, 10), Yearly: true}, EventStatus{Next: date(2025, 9, 10), Days: 0, Years: 10}},
+		{"2月29日", Event{Date: date(2024, 2, 29), Yearly: true}, EventStatus{Next: date(2026, 2, 28), Days: 171, Years: 2}},
+		// 2000-09-12 为农历八月十五，2025 年的中秋在 10 月 6 日
+		{"农历每年", Event{Date: date(2000, 9, 12), Yearly: true, Lunar: true}, EventStatus{Next: date(2025, 10, 6), Days: 26, Years: 25}},
+	}
+	for _, c := range cases {
+		got := c.e.Status(now)
+		if !got.Next.Equal(c.want.Next) || got.Days != c.want.Days || got.Past != c.want.Past || got.Years != c.want.Years {
+			t.Errorf("%s: got %+v, want %+v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestEventStoreAndPin(t *testing.T) {
+	useTestStore(t, dataFile{})
+
+	now := time.Date(2025, 9, 10, 9, 0, 0, 0, time.Local)
+	a := &Event{Title: "国庆", Date: time.Date(2025, 10, 1, 0, 0, 0, 0, time.Local)}
+	b := &Event{Title: "项目上线", Date: time.Date(2025, 9, 20, 0, 0, 0, 0, time.Local), Pinned: true}
+	for _, e := range []*Event{a, b} {
+		if err := AddEvent(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := AddEvent(&Event{Title: " "}); err == nil {
+		t.Fatal("expected error for empty title")
+	}
+
+	if top, ok := TopEvent(now); !ok || top.ID != b.ID {
+		t.Fatalf("top = %+v", top)
+	}
+	if err := PinEvent(a.ID); err != nil {
+		t.Fatal(err)
+	}
+	if top, _ := TopEvent(now); top.ID != a.ID {
+		t.Fatalf("top after pin = %+v", top)
+	}
+	// 取消置顶后取最近的事件
+	if err := PinEvent(0); err != nil {
+		t.Fatal(err)
+	}
+	if top, _ := TopEvent(now); top.ID != b.ID {
+		t.Fatalf("top without pin = %+v", top)
+	}
+
+	if err := DeleteEvent(b.ID); err != nil {
+		t.Fatal(err)
+	}
+	if events := AllEvents(now); len(events) != 1 || events[0].ID != a.ID {
+		t.Fatalf("events = %+v", events)
+	}
+}
+
+func TestMigrateLegacyCountdowns(t *testing.T) {
+	due := func(m time.Month, d, h int) *time.Time {
+		v := time.Date(2025, m, d, h, 0, 0, 0, time.Local)
+		return &v
+	}
+	tasks := func() []Task {
+		return []Task{
+			{ID: 1, Title: "国庆", Label: "重要", RepeatRule: RepeatNone, DueDate: due(10, 1, 0)},
+			{ID: 2, Title: "读书", RepeatRule: RepeatNone},
+			{ID: 3, Title: "已完成", RepeatRule: RepeatNone, DueDate: due(9, 1, 0), IsDone: true},
+			{ID: 4, Title: "有专注记录", RepeatRule: RepeatNone, DueDate: due(9, 20, 0)},
+			{ID: 5, Title: "交报告", RepeatRule: RepeatNone, DueDate: due(9, 30, 18)},
+		}
+	}
+	focused := int64(4)
+	sessions := []TimerSession{{ID: 1, TaskID: &focused}}
+
+	// v2 之前带截止日期的任务只可能是倒数日，直接转换
+	d := dataFile{Tasks: tasks(), Sessions: sessions}
+	migrateLocked(&d)
+	if len(d.Events) != 1 || d.Events[0].Title != "国庆" || !d.Events[0].Date.Equal(*due(10, 1, 0)) || d.Events[0].ID != 1 || d.NextEventID != 2 {
+		t.Fatalf("events = %+v", d.Events)
+	}
+	if len(d.Tasks) != 4 || d.LegacyCountdowns != nil {
+		t.Fatalf("tasks = %+v, pending = %v", d.Tasks, d.LegacyCountdowns)
+	}
+
+	// 之后的版本无法区分，记录下来等用户确认
+	useTestStore(t, dataFile{Version: 3, Tasks: tasks(), Sessions: sessions})
+	data.Tasks[1].BlockedBy = []int64{1}
+	migrateLocked(&data)
+	if len(data.Events) != 0 || len(data.Tasks) != 5 {
+		t.Fatalf("v3 data converted without asking: %+v", data)
+	}
+	if got := LegacyCountdownTasks(); len(got) != 1 || got[0].ID != 1 {
+		t.Fatalf("legacy countdowns = %+v", got)
+	}
+	if n, err := ResolveLegacyCountdowns([]int64{1}); err != nil || n != 1 {
+		t.Fatalf("resolve: n=%d err=%v", n, err)
+	}
+	if len(data.Events) != 1 || len(data.Tasks) != 4 || data.Tasks[0].BlockedBy != nil {
+		t.Fatalf("after resolve: %+v", data)
+	}
+	if got := LegacyCountdownTasks(); got != nil {
+		t.Fatalf("asked again: %+v", got)
+	}
+}
+
+func TestResolveLegacyCountdownsRechecks(t *testing.T) {
+	due := time.Date(2025, 10, 1, 0, 0, 0, 0, time.Local)
+	useTestStore(t, dataFile{Version: 3, Tasks: []Task{
+		{ID: 1, Title: "国庆", DueDate: &due},
+		{ID: 2, Title: "考试", DueDate: &due},
+		{ID: 3, Title: "生日", DueDate: &due},
+	}})
+	migrateLocked(&data)
+	if got := LegacyCountdownTasks(); len(got) != 3 {
+		t.Fatalf("legacy countdowns = %+v", got)
+	}
+
+	// 询问期间有了子任务或专注记录的任务不再转换
+	focused := int64(3)
+	data.Tasks = append(data.Tasks, Task{ID: 4, Title: "复习", ParentID: 2})
+	data.Sessions = []TimerSession{{ID: 1, TaskID: &focused}}
+	if n, err := ResolveLegacyCountdowns([]int64{1, 2, 3}); err != nil || n != 1 {
+		t.Fatalf("resolve: n=%d err=%v", n, err)
+	}
+	if len(data.Events) != 1 || data.Events[0].Title != "国庆" || len(data.Tasks) != 3 {
+		t.Fatalf("after resolve: events %+v, tasks %+v", data.Events, data.Tasks)
+	}
+}
@@ -0,0 +1,151 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// holidayFileName 节假日日历文件名，与数据文件同在用户目录下
+const holidayFileName = ".tomato_clock_holidays.json"
+
+// HolidayCalendar 法定节假日与调休上班日。
+// 未列出的日期按周一至周五上班、周末休息处理。
+type HolidayCalendar struct {
+	holidays map[string]bool // 放假的日期（含落在工作日的节假日）
+	workdays map[string]bool // 调休上班的周末
+}
+
+// holidayFile 是节假日日历文件的格式，日期为 YYYY-MM-DD，
+// 也可以写成 "2025-10-01..2025-10-08" 表示一段连续日期。
+type holidayFile struct {
+	Holidays []string `json:"holidays"`
+	Workdays []string `json:"workdays"`
+}
+
+var (
+	holidayMu sync.RWMutex
+	holidays  = &HolidayCalendar{}
+)
+
+// dateKey 返回日期在本地时区的 YYYY-MM-DD 表示
+func dateKey(t time.Time) string {
+	return t.In(time.Local).Format("2006-01-02")
+}
+
+// expandDates 展开单个日期或 "起..止" 日期段
+func expandDates(spec string) ([]string, error) {
+	spec = strings.TrimSpace(spec)
+	from, to, isRange := strings.Cut(spec, "..")
+	start, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(from), time.Local)
+	if err != nil {
+		return nil, fmt.Errorf("无效日期 %q", spec)
+	}
+	if !isRange {
+		return []string{dateKey(start)}, nil
+	}
+	end, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(to), time.Local)
+	if err != nil || end.Before(start) {
+		return nil, fmt.Errorf("无效日期范围 %q", spec)
+	}
+	var res []string
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		res = append(res, dateKey(d))
+	}
+	return res, nil
+}
+
+// ParseHolidayCalendar 解析节假日日历 JSON
+func ParseHolidayCalendar(b []byte) (*HolidayCalendar, error) {
+	var f holidayFile
+	if err := json.Unmarshal(b, &f); err != nil {
+		return nil, err
+	}
+	c := &HolidayCalendar{holidays: map[string]bool{}, workdays: map[string]bool{}}
+	for _, spec := range f.Holidays {
+		days, err := expandDates(spec)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range days {
+			c.holidays[d] = true
+		}
+	}
+	for _, spec := range f.Workdays {
+		days, err := expandDates(spec)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range days {
+			c.workdays[d] = true
+		}
+	}
+	return c, nil
+}
+
+// IsWorkday 判断某天是否需要上班：调休上班日优先，其次是节假日，最后按星期判断
+func (c *HolidayCalendar) IsWorkday(t time.Time) bool {
+	key := dateKey(t)
+	if c.workdays[key] {
+		return true
+	}
+	if c.holidays[key] {
+		return false
+	}
+	wd := t.In(time.Local).Weekday()
+	return wd != time.Saturday && wd != time.Sunday
+}
+
+// LoadHolidayFile 从文件加载节假日日历并替换当前日历
+func LoadHolidayFile(path string) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	c, err := ParseHolidayCalendar(b)
+	if err != nil {
+		return fmt.Errorf("解析节假日文件 %s 失败: %w", path, err)
+	}
+	SetHolidayCalendar(c)
+	log.Printf("[DEBUG] 已加载节假日日历: %s (放假%d天, 调休上班%d天)", path, len(c.holidays), len(c.workdays))
+	return nil
+}
+
+// SetHolidayCalendar 替换当前使用的节假日日历，nil 表示只按星期判断
+func SetHolidayCalendar(c *HolidayCalendar) {
+	if c == nil {
+		c = &HolidayCalendar{}
+	}
+	holidayMu.Lock()
+	holidays = c
+	holidayMu.Unlock()
+}
+
+// IsWorkday 使用当前节假日日历判断某天是否为工作日
+func IsWorkday(t time.Time) bool {
+	holidayMu.RLock()
+	defer holidayMu.RUnlock()
+	return holidays.IsWorkday(t)
+}
+
+// HolidayFilePath 返回默认的节假日日历文件路径
+func HolidayFilePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, holidayFileName), nil
+}
+
+// loadDefaultHolidays 启动时加载用户目录下的节假日文件（不存在时忽略）
+func loadDefaultHolidays(home string) {
+	path := filepath.Join(home, holidayFileName)
+	if err := LoadHolidayFile(path); err != nil && !os.IsNotExist(err) {
+		log.Printf("[ERROR] %v", err)
+	}
+}
@@ -0,0 +1,193 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// 农历换算表支持的年份范围
+const (
+	lunarMinYear = 1900
+	lunarMaxYear = 2100
+)
+
+// lunarInfo 1900-2100 年的农历数据，每年 20 位：
+// 第 0-3 位为闰月月份（0 表示无闰月），第 4-15 位从高到低依次表示正月到十二月是否为大月（30 天），
+// 第 16 位表示闰月是否为大月。
+var lunarInfo = [...]int{
+	0x04bd8, 0x04ae0, 0x0a570, 0x054d5, 0x0d260, 0x0d950, 0x16554, 0x056a0, 0x09ad0, 0x055d2, // 1900-1909
+	0x04ae0, 0x0a5b6, 0x0a4d0, 0x0d250, 0x1d255, 0x0b540, 0x0d6a0, 0x0ada2, 0x095b0, 0x14977, // 1910-1919
+	0x04970, 0x0a4b0, 0x0b4b5, 0x06a50, 0x06d40, 0x1ab54, 0x02b60, 0x09570, 0x052f2, 0x04970, // 1920-1929
+	0x06566, 0x0d4a0, 0x0ea50, 0x16a95, 0x05ad0, 0x02b60, 0x186e3, 0x092e0, 0x1c8d7, 0x0c950, // 1930-1939
+	0x0d4a0, 0x1d8a6, 0x0b550, 0x056a0, 0x1a5b4, 0x025d0, 0x092d0, 0x0d2b2, 0x0a950, 0x0b557, // 1940-1949
+	0x06ca0, 0x0b550, 0x15355, 0x04da0, 0x0a5b0, 0x14573, 0x052b0, 0x0a9a8, 0x0e950, 0x06aa0, // 1950-1959
+	0x0aea6, 0x0ab50, 0x04b60, 0x0aae4, 0x0a570, 0x05260, 0x0f263, 0x0d950, 0x05b57, 0x056a0, // 1960-1969
+	0x096d0, 0x04dd5, 0x04ad0, 0x0a4d0, 0x0d4d4, 0x0d250, 0x0d558, 0x0b540, 0x0b6a0, 0x195a6, // 1970-1979
+	0x095b0, 0x049b0, 0x0a974, 0x0a4b0, 0x0b27a, 0x06a50, 0x06d40, 0x0af46, 0x0ab60, 0x09570, // 1980-1989
+	0x04af5, 0x04970, 0x064b0, 0x074a3, 0x0ea50, 0x06b58, 0x05ac0, 0x0ab60, 0x096d5, 0x092e0, // 1990-1999
+	0x0c960, 0x0d954, 0x0d4a0, 0x0da50, 0x07552, 0x056a0, 0x0abb7, 0x025d0, 0x092d0, 0x0cab5, // 2000-2009
+	0x0a950, 0x0b4a0, 0x0baa4, 0x0ad50, 0x055d9, 0x04ba0, 0x0a5b0, 0x15176, 0x052b0, 0x0a930, // 2010-2019
+	0x07954, 0x06aa0, 0x0ad50, 0x05b52, 0x04b60, 0x0a6e6, 0x0a4e0, 0x0d260, 0x0ea65, 0x0d530, // 2020-2029
+	0x05aa0, 0x076a3, 0x096d0, 0x04afb, 0x04ad0, 0x0a4d0, 0x1d0b6, 0x0d250, 0x0d520, 0x0dd45, // 2030-2039
+	0x0b5a0, 0x056d0, 0x055b2, 0x049b0, 0x0a577, 0x0a4b0, 0x0aa50, 0x1b255, 0x06d20, 0x0ada0, // 2040-2049
+	0x14b63, 0x09370, 0x049f8, 0x04970, 0x064b0, 0x168a6, 0x0ea50, 0x06b20, 0x1a6c4, 0x0aae0, // 2050-2059
+	0x0a2e0, 0x0d2e3, 0x0c960, 0x0d557, 0x0d4a0, 0x0da50, 0x05d55, 0x056a0, 0x0a6d0, 0x055d4, // 2060-2069
+	0x052d0, 0x0a9b8, 0x0a950, 0x0b4a0, 0x0b6a6, 0x0ad50, 0x055a0, 0x0aba4, 0x0a5b0, 0x052b0, // 2070-2079
+	0x0b273, 0x06930, 0x07337, 0x06aa0, 0x0ad50, 0x14b55, 0x04b60, 0x0a570, 0x054e4, 0x0d160, // 2080-2089
+	0x0e968, 0x0d520, 0x0daa0, 0x16aa6, 0x056d0, 0x04ae0, 0x0a9d4, 0x0a2d0, 0x0d150, 0x0f252, // 2090-2099
+	0x0d520, // 2100
+}
+
+// lunarEpoch 农历 1900 年正月初一对应的公历日期
+var lunarEpoch = time.Date(1900, 1, 31, 0, 0, 0, 0, time.UTC)
+
+// LunarDate 农历日期
+type LunarDate struct {
+	Year  int
+	Month int  // 1-12
+	Day   int  // 1-30
+	Leap  bool // 是否为闰月
+}
+
+// lunarLeapMonth 返回农历 y 年的闰月月份，0 表示无闰月
+func lunarLeapMonth(y int) int {
+	return lunarInfo[y-lunarMinYear] & 0xf
+}
+
+// lunarMonthDays 返回农历 y 年 m 月（leap 为闰月）的天数
+func lunarMonthDays(y, m int, leap bool) int {
+	info := lunarInfo[y-lunarMinYear]
+	if leap {
+		if info&0x10000 != 0 {
+			return 30
+		}
+		return 29
+	}
+	if info&(0x10000>>m) != 0 {
+		return 30
+	}
+	return 29
+}
+
+// lunarYearDays 返回农历 y 年的总天数
+func lunarYearDays(y int) int {
+	days := 0
+	for m := 1; m <= 12; m++ {
+		days += lunarMonthDays(y, m, false)
+	}
+	if lunarLeapMonth(y) != 0 {
+		days += lunarMonthDays(y, lunarLeapMonth(y), true)
+	}
+	return days
+}
+
+// daysSinceEpoch 返回公历日期距 lunarEpoch 的天数
+func daysSinceEpoch(t time.Time) int {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	return int(day.Sub(lunarEpoch).Hours() / 24)
+}
+
+// SolarToLunar 将公历日期转换为农历日期，超出 1900-2100 年范围时返回错误
+func SolarToLunar(t time.Time) (LunarDate, error) {
+	offset := daysSinceEpoch(t)
+	if offset < 0 {
+		return LunarDate{}, fmt.Errorf("农历换算仅支持 %d-%d 年", lunarMinYear, lunarMaxYear)
+	}
+	y := lunarMinYear
+	for ; y <= lunarMaxYear; y++ {
+		n := lunarYearDays(y)
+		if offset < n {
+			break
+		}
+		offset -= n
+	}
+	if y > lunarMaxYear {
+		return LunarDate{}, fmt.Errorf("农历换算仅支持 %d-%d 年", lunarMinYear, lunarMaxYear)
+	}
+	leapMonth := lunarLeapMonth(y)
+	for m := 1; m <= 12; m++ {
+		n := lunarMonthDays(y, m, false)
+		if offset < n {
+			return LunarDate{Year: y, Month: m, Day: offset + 1}, nil
+		}
+		offset -= n
+		if m == leapMonth {
+			n = lunarMonthDays(y, m, true)
+			if offset < n {
+				return LunarDate{Year: y, Month: m, Day: offset + 1, Leap: true}, nil
+			}
+			offset -= n
+		}
+	}
+	return LunarDate{}, fmt.Errorf("农历换算出错: %s", t.Format("2006-01-02"))
+}
+
+// LunarToSolar 将农历日期转换为公历日期（loc 时区的零点）。
+// 该年没有对应闰月或该月没有三十时返回错误。
+func LunarToSolar(d LunarDate, loc *time.Location) (time.Time, error) {
+	if d.Year < lunarMinYear || d.Year > lunarMaxYear {
+		return time.Time{}, fmt.Errorf("农历换算仅支持 %d-%d 年", lunarMinYear, lunarMaxYear)
+	}
+	if d.Month < 1 || d.Month > 12 || d.Day < 1 || d.Day > 30 {
+		return time.Time{}, fmt.Errorf("无效的农历日期: %s", d)
+	}
+	if d.Leap && lunarLeapMonth(d.Year) != d.Month {
+		return time.Time{}, fmt.Errorf("农历%d年没有%s", d.Year, d.monthName())
+	}
+	if d.Day > lunarMonthDays(d.Year, d.Month, d.Leap) {
+		return time.Time{}, fmt.Errorf("农历%d年%s没有%s", d.Year, d.monthName(), lunarDayName(d.Day))
+	}
+	offset := 0
+	for y := lunarMinYear; y < d.Year; y++ {
+		offset += lunarYearDays(y)
+	}
+	leapMonth := lunarLeapMonth(d.Year)
+	for m := 1; m < d.Month; m++ {
+		offset += lunarMonthDays(d.Year, m, false)
+		if m == leapMonth {
+			offset += lunarMonthDays(d.Year, m, true)
+		}
+	}
+	if d.Leap {
+		offset += lunarMonthDays(d.Year, d.Month, false)
+	}
+	offset += d.Day - 1
+	solar := lunarEpoch.AddDate(0, 0, offset)
+	return time.Date(solar.Year(), solar.Month(), solar.Day(), 0, 0, 0, 0, loc), nil
+}
+
+var (
+	lunarMonthNames = []string{"正", "二", "三", "四", "五", "六", "七", "八", "九", "十", "冬", "腊"}
+	lunarDigits     = []string{"一", "二", "三", "四", "五", "六", "七", "八", "九", "十"}
+)
+
+// lunarDayName 返回农历日的中文写法，如 初一、十五、廿三、三十
+func lunarDayName(day int) string {
+	switch {
+	case day <= 10:
+		return "初" + lunarDigits[day-1]
+	case day < 20:
+		return "十" + lunarDigits[day-11]
+	case day == 20:
+		return "二十"
+	case day < 30:
+		return "廿" + lunarDigits[day-21]
+	}
+	return "三十"
+}
+
+func (d LunarDate) monthName() string {
+	name := lunarMonthNames[d.Month-1] + "月"
+	if d.Leap {
+		name = "闰" + name
+	}
+	return name
+}
+
+// String 返回如 "八月十五"、"闰六月初一" 的中文写法（不含年份）
+func (d LunarDate) String() string {
+	if d.Month < 1 || d.Month > 12 || d.Day < 1 || d.Day > 30 {
+		return fmt.Sprintf("%d-%d", d.Month, d.Day)
+	}
+	return d.monthName() + lunarDayName(d.Day)
+}
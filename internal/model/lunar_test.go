@@ -0,0 +1,52 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLunarConversion(t *testing.T) {
+	date := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.Local) }
+	cases := []struct {
+		solar time.Time
+		lunar LunarDate
+	}{
+		{date(1900, 1, 31), LunarDate{1900, 1, 1, false}},
+		{date(1990, 1, 27), LunarDate{1990, 1, 1, false}},
+		{date(2000, 2, 5), LunarDate{2000, 1, 1, false}},
+		{date(2020, 1, 25), LunarDate{2020, 1, 1, false}},
+		{date(2020, 5, 23), LunarDate{2020, 4, 1, true}}, // 闰四月初一
+		{date(2023, 1, 22), LunarDate{2023, 1, 1, false}},
+		{date(2023, 3, 22), LunarDate{2023, 2, 1, true}}, // 闰二月初一
+		{date(2024, 2, 10), LunarDate{2024, 1, 1, false}},
+		{date(2024, 9, 17), LunarDate{2024, 8, 15, false}}, // 中秋
+		{date(2025, 1, 29), LunarDate{2025, 1, 1, false}},
+		{date(2025, 7, 25), LunarDate{2025, 6, 1, true}}, // 闰六月初一
+		{date(2025, 10, 6), LunarDate{2025, 8, 15, false}},
+		{date(2026, 2, 17), LunarDate{2026, 1, 1, false}},
+		{date(2030, 2, 3), LunarDate{2030, 1, 1, false}},
+	}
+	for _, c := range cases {
+		got, err := SolarToLunar(c.solar)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != c.lunar {
+			t.Errorf("SolarToLunar(%s) = %+v, want %+v", c.solar.Format("2006-01-02"), got, c.lunar)
+		}
+		back, err := LunarToSolar(c.lunar, time.Local)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !back.Equal(c.solar) {
+			t.Errorf("LunarToSolar(%+v) = %s, want %s", c.lunar, back.Format("2006-01-02"), c.solar.Format("2006-01-02"))
+		}
+	}
+
+	if s := (LunarDate{2025, 6, 21, true}).String(); s != "闰六月廿一" {
+		t.Fatalf("String = %q", s)
+	}
+	if _, err := LunarToSolar(LunarDate{Year: 2024, Month: 6, Day: 1, Leap: true}, time.Local); err == nil {
+		t.Fatal("expected error for missing leap month")
+	}
+}
@@ -0,0 +1,192 @@
+package model
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// NoteBlock 备注按行拆分后的片段：一段连续的普通 Markdown 文本，或一条清单项
+type NoteBlock struct {
+	// Text 普通片段为原始 Markdown；清单项为去掉 "- [ ] " 前缀后的内容
+	Text string
+	// Item 为 true 时表示清单项
+	Item   bool
+	Done   bool
+	Indent int // 清单项缩进的空格数（制表符按 4 个空格计）
+	Line   int // 清单项所在的行号（从 0 开始），用于切换勾选状态
+}
+
+var noteItemRe = regexp.MustCompile(`^(\s*)[-*+]\s+\[([ xX])\](?:\s+(.*))?$`)
+
+// isFence 判断一行是否为代码块的开始或结束（``` 或 ~~~）
+func isFence(line string) bool {
+	s := strings.TrimSpace(line)
+	return strings.HasPrefix(s, "```") || strings.HasPrefix(s, "~~~")
+}
+
+// SplitNote 把备注拆分为普通文本与清单项（"- [ ] 待办"、"- [x] 已完成"），代码块中的内容不视为清单项
+func SplitNote(note string) []NoteBlock {
+	var blocks []NoteBlock
+	var text []string
+	flush := func() {
+		if len(text) > 0 {
+			blocks = append(blocks, NoteBlock{Text: strings.Join(text, "\n")})
+			text = nil
+		}
+	}
+	inCode := false
+	for i, line := range strings.Split(note, "\n") {
+		if isFence(line) {
+			inCode = !inCode
+		}
+		m := noteItemRe.FindStringSubmatch(line)
+		if inCode || m == nil {
+			text = append(text, line)
+			continue
+		}
+		flush()
+		indent := len(strings.ReplaceAll(m[1], "\t", "    "))
+		blocks = append(blocks, NoteBlock{Text: m[3], Item: true, Done: m[2] != " ", Indent: indent, Line: i})
+	}
+	flush()
+	return blocks
+}
+
+// ToggleNoteItem 切换备注第 line 行清单项的勾选状态，该行不是清单项时返回错误
+func ToggleNoteItem(note string, line int) (string, error) {
+	lines := strings.Split(note, "\n")
+	for _, b := range SplitNote(note) {
+		if !b.Item || b.Line != line {
+			continue
+		}
+		i := strings.Index(lines[line], "[") + 1
+		mark := "x"
+		if b.Done {
+			mark = " "
+		}
+		lines[line] = lines[line][:i] + mark + lines[line][i+1:]
+		return strings.Join(lines, "\n"), nil
+	}
+	return note, fmt.Errorf("备注第 %d 行不是清单项", line+1)
+}
+
+// ResetNoteItems 取消备注中全部清单项的勾选，用于重复任务的新一期
+func ResetNoteItems(note string) string {
+	lines := strings.Split(note, "\n")
+	for _, b := range SplitNote(note) {
+		if b.Item && b.Done {
+			i := strings.Index(lines[b.Line], "[") + 1
+			lines[b.Line] = lines[b.Line][:i] + " " + lines[b.Line][i+1:]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// NoteProgress 返回备注中已勾选与全部清单项的数量
+func NoteProgress(note string) (done, total int) {
+	for _, b := range SplitNote(note) {
+		if b.Item {
+			total++
+			if b.Done {
+				done++
+			}
+		}
+	}
+	return done, total
+}
+
+var (
+	noteLinkRe   = regexp.MustCompile(`\]\(\s*(<[^>\n]*>|[^)\s]+)`)
+	windowsDrive = regexp.MustCompile(`^[A-Za-z]:[\\/]`)
+)
+
+// localFileURL 把本地路径（/path、~/path、C:\path）转换为 file:// 链接，其它链接原样返回
+func localFileURL(target string) (string, bool) {
+	switch {
+	case strings.HasPrefix(target, "~/"):
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return target, false
+		}
+		target = filepath.Join(home, target[2:])
+	case strings.HasPrefix(target, "/"):
+	case windowsDrive.MatchString(target):
+		target = "/" + target
+	default:
+		return target, false
+	}
+	u := url.URL{Scheme: "file", Path: filepath.ToSlash(target)}
+	return u.String(), true
+}
+
+// LocalNoteLinks 把备注中指向本地文件的 Markdown 链接改写为 file:// 链接，便于点击后用系统程序打开。
+// 路径中有空格时可写作 [报告](<~/文档/周 报.pdf>)。
+func LocalNoteLinks(note string) string {
+	return noteLinkRe.ReplaceAllStringFunc(note, func(m string) string {
+		sub := noteLinkRe.FindStringSubmatch(m)
+		target := strings.TrimSuffix(strings.TrimPrefix(sub[1], "<"), ">")
+		u, ok := localFileURL(target)
+		if !ok {
+			return m
+		}
+		return strings.Replace(m, sub[1], u, 1)
+	})
+}
+
+// matchQuery 判断任务的标题、备注或标签是否包含关键字（不区分大小写）
+func matchQuery(t Task, query string) bool {
+	q := strings.ToLower(strings.TrimSpace(query))
+	if q == "" {
+		return true
+	}
+	fields := append([]string{t.Title, t.Note, t.Label}, t.Tags...)
+	for _, f := range fields {
+		if strings.Contains(strings.ToLower(f), q) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetTaskNote 修改任务备注
+func SetTaskNote(id int64, note string) error {
+	return updateTaskNote(id, func(string) (string, error) { return note, nil })
+}
+
+// ToggleTaskNoteItem 切换任务备注中第 line 行清单项的勾选状态
+func ToggleTaskNoteItem(id int64, line int) error {
+	return updateTaskNote(id, func(note string) (string, error) { return ToggleNoteItem(note, line) })
+}
+
+func updateTaskNote(id int64, fn func(string) (string, error)) error {
+	mu.Lock()
+	var err error
+	found := false
+	for i := range data.Tasks {
+		if data.Tasks[i].ID != id {
+			continue
+		}
+		t := &data.Tasks[i]
+		var note string
+		if note, err = fn(t.Note); err == nil && note != t.Note {
+			t.Note = note
+			t.UpdatedAt = nowFunc()
+		}
+		found = true
+		break
+	}
+	mu.Unlock()
+	if !found {
+		return fmt.Errorf("task id %d not found", id)
+	}
+	if err != nil {
+		return err
+	}
+	log.Printf("[UpdateTaskNote] id=%d", id)
+	return Save()
+}
@@ -0,0 +1,102 @@
+package model
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSplitNoteAndToggle(t *testing.T) {
+	note := strings.Join([]string{
+		"# 计划",
+		"先读完第一章",
+		"- [ ] 整理笔记",
+		"  - [x] 画思维导图",
+		"```",
+		"- [ ] 代码块里不是清单",
+		"```",
+		"* [X] 复习",
+	}, "\n")
+
+	blocks := SplitNote(note)
+	var items []NoteBlock
+	for _, b := range blocks {
+		if b.Item {
+			items = append(items, b)
+		}
+	}
+	if len(items) != 3 {
+		t.Fatalf("items = %+v", items)
+	}
+	if items[0].Text != "整理笔记" || items[0].Done || items[0].Line != 2 {
+		t.Fatalf("first item = %+v", items[0])
+	}
+	if !items[1].Done || items[1].Indent != 2 || !items[2].Done {
+		t.Fatalf("items = %+v", items)
+	}
+	if done, total := NoteProgress(note); done != 2 || total != 3 {
+		t.Fatalf("progress = %d/%d", done, total)
+	}
+
+	toggled, err := ToggleNoteItem(note, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(toggled, "- [x] 整理笔记") {
+		t.Fatalf("toggled = %q", toggled)
+	}
+	back, _ := ToggleNoteItem(toggled, 2)
+	if back != note {
+		t.Fatalf("toggle twice should restore note, got %q", back)
+	}
+	if _, err := ToggleNoteItem(note, 5); err == nil {
+		t.Fatal("expected error for line inside code block")
+	}
+}
+
+func TestLocalNoteLinks(t *testing.T) {
+	cases := map[string]string{
+		"见 [报告](/home/me/报告.pdf)":     "见 [报告](file:///home/me/%E6%8A%A5%E5%91%8A.pdf)",
+		"[周报](</tmp/周 报.md>)":         "[周报](file:///tmp/%E5%91%A8%20%E6%8A%A5.md)",
+		"[官网](https://example.com/a)": "[官网](https://example.com/a)",
+		"[表格](C:/docs/a.xlsx)":        "[表格](file:///C:/docs/a.xlsx)",
+		"普通文本 (不是链接)":                 "普通文本 (不是链接)",
+	}
+	for in, want := range cases {
+		got := LocalNoteLinks(in)
+		if got != want {
+			t.Errorf("LocalNoteLinks(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestTaskNoteSearchAndToggle(t *testing.T) {
+	useTestStore(t, dataFile{NextTaskID: 1, NextSessionID: 1})
+
+	a := &Task{Title: "读书"}
+	b := &Task{Title: "写代码", Tags: []string{"Go"}}
+	for _, task := range []*Task{a, b} {
+		if err := AddTask(task); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := SetTaskNote(a.ID, "- [ ] 第三章 Golang 并发"); err != nil {
+		t.Fatal(err)
+	}
+	if err := ToggleTaskNoteItem(a.ID, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	tasks := AllTasks()
+	if tasks[0].Note != "- [x] 第三章 Golang 并发" {
+		t.Fatalf("note = %q", tasks[0].Note)
+	}
+	got := FilterTasks(tasks, TaskFilter{Query: "golang"}, time.Now())
+	if !got[a.ID] || got[b.ID] {
+		t.Fatalf("query golang = %v", got)
+	}
+	got = FilterTasks(tasks, TaskFilter{Query: "go"}, time.Now())
+	if !got[a.ID] || !got[b.ID] {
+		t.Fatalf("query go = %v", got)
+	}
+}
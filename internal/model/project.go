@@ -0,0 +1,132 @@
+package model
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+)
+
+// NoProject 不属于任何项目的任务在统计中的分组名
+const NoProject = "无项目"
+
+// Project 项目，任务通过 Task.ProjectID 归属于项目
+type Project struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func nextProjectID() int64 {
+	if data.NextProjectID == 0 {
+		data.NextProjectID = 1
+	}
+	id := data.NextProjectID
+	data.NextProjectID++
+	return id
+}
+
+// projectByNameLocked 按名称查找项目，调用方需持有 mu
+func projectByNameLocked(name string) (Project, bool) {
+	for _, p := range data.Projects {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return Project{}, false
+}
+
+// AddProject 新建项目；同名项目已存在时直接返回已有项目
+func AddProject(name string) (Project, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return Project{}, fmt.Errorf("项目名称不能为空")
+	}
+	mu.Lock()
+	if p, ok := projectByNameLocked(name); ok {
+		mu.Unlock()
+		return p, nil
+	}
+	p := Project{ID: nextProjectID(), Name: name, CreatedAt: time.Now()}
+	data.Projects = append(data.Projects, p)
+	mu.Unlock()
+	if err := Save(); err != nil {
+		return Project{}, err
+	}
+	log.Printf("[AddProject] id=%d name=%s", p.ID, p.Name)
+	return p, nil
+}
+
+// AllProjects 返回全部项目的副本，按名称排序
+func AllProjects() []Project {
+	mu.Lock()
+	defer mu.Unlock()
+	res := make([]Project, len(data.Projects))
+	copy(res, data.Projects)
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+	return res
+}
+
+// RenameProject 修改项目名称，不能与其它项目重名
+func RenameProject(id int64, name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("项目名称不能为空")
+	}
+	mu.Lock()
+	if p, ok := projectByNameLocked(name); ok && p.ID != id {
+		mu.Unlock()
+		return fmt.Errorf("项目 %q 已存在", name)
+	}
+	var found bool
+	for i := range data.Projects {
+		if data.Projects[i].ID == id {
+			data.Projects[i].Name = name
+			found = true
+			break
+		}
+	}
+	mu.Unlock()
+	if !found {
+		return fmt.Errorf("project id %d not found", id)
+	}
+	return Save()
+}
+
+// DeleteProject 删除项目，原属于该项目的任务变为无项目
+func DeleteProject(id int64) error {
+	mu.Lock()
+	var kept []Project
+	for _, p := range data.Projects {
+		if p.ID != id {
+			kept = append(kept, p)
+		}
+	}
+	data.Projects = kept
+	for i := range data.Tasks {
+		if data.Tasks[i].ProjectID == id {
+			data.Tasks[i].ProjectID = 0
+		}
+	}
+	mu.Unlock()
+	log.Printf("[DeleteProject] id=%d", id)
+	return Save()
+}
+
+// projectNameLocked 返回任务所属项目的名称，无项目时返回 NoProject，调用方需持有 mu
+func projectNameLocked(id int64) string {
+	for _, p := range data.Projects {
+		if p.ID == id {
+			return p.Name
+		}
+	}
+	return NoProject
+}
+
+// ProjectName 返回项目名称，项目不存在或 id 为 0 时返回 NoProject
+func ProjectName(id int64) string {
+	mu.Lock()
+	defer mu.Unlock()
+	return projectNameLocked(id)
+}
@@ -0,0 +1,52 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMigrateLabelToTags(t *testing.T) {
+	d := dataFile{Tasks: []Task{{ID: 1, Title: "读书", Label: "学习"}, {ID: 2, Title: "散步"}}}
+	if !migrateLocked(&d) {
+		t.Fatalf("version 0 data should be migrated")
+	}
+	if d.Version != currentDataVersion || len(d.Tasks[0].Tags) != 1 || d.Tasks[0].Tags[0] != "学习" || d.Tasks[1].Tags != nil {
+		t.Fatalf("unexpected migration result: %+v", d)
+	}
+	if migrateLocked(&d) {
+		t.Fatalf("migration should run only once")
+	}
+}
+
+func TestFocusByGroup(t *testing.T) {
+	at := func(h int) time.Time { return time.Date(2025, 7, 1, h, 0, 0, 0, time.UTC) }
+	work, study := int64(1), int64(2)
+
+	mu.Lock()
+	data = dataFile{Version: currentDataVersion}
+	data.Projects = []Project{{ID: 1, Name: "毕业论文"}}
+	data.Tasks = []Task{
+		{ID: work, Title: "写第一章", Label: "写作", Tags: []string{"写作", "学习"}, ProjectID: 1},
+		{ID: study, Title: "背单词", Label: "学习", Tags: []string{"学习"}},
+	}
+	data.Sessions = []TimerSession{
+		{ID: 1, TaskID: &work, Mode: "countup", StartedAt: at(8), EndedAt: at(10), DurationSec: 7200},
+		{ID: 2, TaskID: &study, Mode: "countup", StartedAt: at(10), EndedAt: at(11), DurationSec: 3600},
+		{ID: 3, Mode: "countup", StartedAt: at(12), EndedAt: at(13), DurationSec: 3600},
+	}
+	mu.Unlock()
+
+	from, to := at(0), at(23)
+	byProject := FocusByGroup(from, to, GroupByProject)
+	if byProject["毕业论文"] != 7200 || byProject[NoProject] != 7200 {
+		t.Fatalf("unexpected project totals: %v", byProject)
+	}
+	byTag := FocusByGroup(from, to, GroupByTag)
+	if byTag["学习"] != 3*3600 || byTag["写作"] != 7200 || byTag[DefaultLabel] != 3600 {
+		t.Fatalf("unexpected multi-count tag totals: %v", byTag)
+	}
+	primary := FocusByGroup(from, to, GroupByLabel)
+	if primary["学习"] != 3600 || primary["写作"] != 7200 || primary[DefaultLabel] != 3600 {
+		t.Fatalf("unexpected primary tag totals: %v", primary)
+	}
+}
@@ -0,0 +1,301 @@
+package model
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxOccurrenceScan 查找上一期 / 下一期时最多向前或向后扫描的天数
+const maxOccurrenceScan = 400
+
+// Occurrence 记录重复任务某一期的完成情况
+type Occurrence struct {
+	TaskID int64     `json:"task_id"` // 所属系列 ID，见 Task.Series
+	Date   time.Time `json:"date"`    // 这一期的日期（本地零点）
+	Done   bool      `json:"done"`
+}
+
+// RepeatSpec 是解析后的重复规则
+type RepeatSpec struct {
+	Kind     string         // RepeatNone / RepeatDaily / RepeatWeekly / RepeatWorkday / RepeatEvery
+	Weekdays []time.Weekday // Kind 为 RepeatWeekly 时的指定星期，为空表示与首期同一天
+	Every    int            // Kind 为 RepeatEvery 时的间隔天数
+}
+
+// ParseRepeatRule 解析 Task.RepeatRule，空字符串视为不重复
+func ParseRepeatRule(rule string) (RepeatSpec, error) {
+	rule = strings.TrimSpace(rule)
+	kind, arg, hasArg := strings.Cut(rule, ":")
+	switch kind {
+	case "", RepeatNone:
+		return RepeatSpec{Kind: RepeatNone}, nil
+	case RepeatDaily, RepeatWorkday:
+		if hasArg {
+			break
+		}
+		return RepeatSpec{Kind: kind}, nil
+	case RepeatWeekly:
+		spec := RepeatSpec{Kind: RepeatWeekly}
+		if !hasArg {
+			return spec, nil
+		}
+		seen := map[time.Weekday]bool{}
+		for _, f := range strings.Split(arg, ",") {
+			n, err := strconv.Atoi(strings.TrimSpace(f))
+			if err != nil || n < 1 || n > 7 {
+				return RepeatSpec{}, fmt.Errorf("无效的星期 %q，应为 1-7", f)
+			}
+			wd := time.Weekday(n % 7) // 7 表示周日
+			if !seen[wd] {
+				seen[wd] = true
+				spec.Weekdays = append(spec.Weekdays, wd)
+			}
+		}
+		sort.Slice(spec.Weekdays, func(i, j int) bool {
+			return (spec.Weekdays[i]+6)%7 < (spec.Weekdays[j]+6)%7
+		})
+		return spec, nil
+	case RepeatEvery:
+		n, err := strconv.Atoi(strings.TrimSpace(arg))
+		if err != nil || n < 1 {
+			return RepeatSpec{}, fmt.Errorf("无效的间隔天数 %q", arg)
+		}
+		return RepeatSpec{Kind: RepeatEvery, Every: n}, nil
+	}
+	return RepeatSpec{}, fmt.Errorf("无法识别的重复规则 %q", rule)
+}
+
+// String 返回规则的规范写法，可再次被 ParseRepeatRule 解析
+func (s RepeatSpec) String() string {
+	switch s.Kind {
+	case RepeatWeekly:
+		if len(s.Weekdays) == 0 {
+			return RepeatWeekly
+		}
+		var days []string
+		for _, wd := range s.Weekdays {
+			n := int(wd)
+			if n == 0 {
+				n = 7
+			}
+			days = append(days, strconv.Itoa(n))
+		}
+		return RepeatWeekly + ":" + strings.Join(days, ",")
+	case RepeatEvery:
+		return fmt.Sprintf("%s:%d", RepeatEvery, s.Every)
+	case "":
+		return RepeatNone
+	}
+	return s.Kind
+}
+
+// Describe 返回规则的中文描述，用于界面显示
+func (s RepeatSpec) Describe() string {
+	names := []string{"日", "一", "二", "三", "四", "五", "六"}
+	switch s.Kind {
+	case RepeatDaily:
+		return "每天"
+	case RepeatWorkday:
+		return "工作日"
+	case RepeatWeekly:
+		if len(s.Weekdays) == 0 {
+			return "每周"
+		}
+		var days []string
+		for _, wd := range s.Weekdays {
+			days = append(days, names[wd])
+		}
+		return "每周" + strings.Join(days, "、")
+	case RepeatEvery:
+		return fmt.Sprintf("每%d天", s.Every)
+	}
+	return "不重复"
+}
+
+// IsRepeating 判断规则是否会重复
+func (s RepeatSpec) IsRepeating() bool {
+	return s.Kind != "" && s.Kind != RepeatNone
+}
+
+// Matches 判断 day 是否为一期的开始。anchor 为系列的首期日期，
+// 用于不指定星期的每周重复与每隔 N 天重复。
+func (s RepeatSpec) Matches(day, anchor time.Time) bool {
+	day = StartOfDay(day.In(time.Local))
+	anchor = StartOfDay(anchor.In(time.Local))
+	switch s.Kind {
+	case RepeatDaily:
+		return true
+	case RepeatWorkday:
+		return IsWorkday(day)
+	case RepeatWeekly:
+		if len(s.Weekdays) == 0 {
+			return day.Weekday() == anchor.Weekday()
+		}
+		for _, wd := range s.Weekdays {
+			if day.Weekday() == wd {
+				return true
+			}
+		}
+	case RepeatEvery:
+		// 按日历日计算差值，避免夏令时导致的 23/25 小时
+		y1, m1, d1 := anchor.Date()
+		y2, m2, d2 := day.Date()
+		diff := int(time.Date(y2, m2, d2, 0, 0, 0, 0, time.UTC).Sub(time.Date(y1, m1, d1, 0, 0, 0, 0, time.UTC)).Hours() / 24)
+		return ((diff%s.Every)+s.Every)%s.Every == 0
+	}
+	return false
+}
+
+// Current 返回不晚于 now 所在日期的最近一期，找不到时返回零值
+func (s RepeatSpec) Current(now, anchor time.Time) time.Time {
+	day := StartOfDay(now.In(time.Local))
+	for i := 0; i < maxOccurrenceScan; i++ {
+		if s.Matches(day, anchor) {
+			return day
+		}
+		day = day.AddDate(0, 0, -1)
+	}
+	return time.Time{}
+}
+
+// Next 返回 after 之后（不含当天）的下一期，找不到时返回零值
+func (s RepeatSpec) Next(after, anchor time.Time) time.Time {
+	day := StartOfDay(after.In(time.Local))
+	for i := 0; i < maxOccurrenceScan; i++ {
+		day = day.AddDate(0, 0, 1)
+		if s.Matches(day, anchor) {
+			return day
+		}
+	}
+	return time.Time{}
+}
+
+// recurrenceAnchor 返回任务的首期参考日期
+func recurrenceAnchor(t Task) time.Time {
+	if t.OccurrenceDate != nil {
+		return *t.OccurrenceDate
+	}
+	return t.CreatedAt
+}
+
+// RollOverRecurring 检查所有重复任务是否进入了新的一期：
+// 记录上一期（以及期间错过的各期）的完成情况，然后重置原任务的完成状态，
+// 或在 RepeatSpawn 时生成下一期的新任务。返回发生变化的任务数。
+func RollOverRecurring(now time.Time) (int, error) {
+	mu.Lock()
+	var changed int
+	var spawned []Task
+	for i := range data.Tasks {
+		t := &data.Tasks[i]
+		spec, err := ParseRepeatRule(t.RepeatRule)
+		if err != nil {
+			log.Printf("[ERROR] 任务 %d 的重复规则无效: %v", t.ID, err)
+			continue
+		}
+		if !spec.IsRepeating() {
+			continue
+		}
+		anchor := recurrenceAnchor(*t)
+		cur := spec.Current(now, anchor)
+		if cur.IsZero() {
+			continue
+		}
+		if t.OccurrenceDate == nil {
+			// 首次遇到该重复任务：只记录当前这一期
+			t.OccurrenceDate = &cur
+			changed++
+			continue
+		}
+		prev := *t.OccurrenceDate
+		if !cur.After(prev) {
+			continue
+		}
+
+		series := t.Series()
+		data.Occurrences = append(data.Occurrences, Occurrence{TaskID: series, Date: prev, Done: t.IsDone})
+		for d := spec.Next(prev, anchor); !d.IsZero() && d.Before(cur); d = spec.Next(d, anchor) {
+			data.Occurrences = append(data.Occurrences, Occurrence{TaskID: series, Date: d})
+		}
+
+		// 截止日期随期数顺延
+		var due *time.Time
+		if t.DueDate != nil {
+			shifted := t.DueDate.AddDate(0, 0, int(cur.Sub(prev).Hours()/24+0.5))
+			due = &shifted
+		}
+
+		if t.RepeatSpawn {
+			// 只沿用任务本身的设置，排序位置、前置任务与清单的勾选属于旧的一期
+			next := *t
+			next.ID = nextTaskID()
+			next.SeriesID = series
+			next.IsDone = false
+			next.CompletedAt = nil
+			next.Archived = false
+			next.UnarchivedAt = nil
+			next.SortOrder = nextSortOrderLocked() + int64(len(spawned))
+			next.BlockedBy = nil
+			next.Note = ResetNoteItems(t.Note)
+			next.Tags = append([]string(nil), t.Tags...)
+			next.RemindBefore = append([]int(nil), t.RemindBefore...)
+			next.OccurrenceDate = &cur
+			next.DueDate = due
+			next.CreatedAt = now
+			next.UpdatedAt = now
+			spawned = append(spawned, next)
+
+			// 旧任务保留为历史，不再参与重复
+			t.SeriesID = series
+			t.RepeatRule = RepeatNone
+			log.Printf("[Recurring] 任务 %d 进入新一期 %s，生成任务 %d", t.ID, dateKey(cur), next.ID)
+		} else {
+			setCompletedLocked(t, false, now)
+			t.OccurrenceDate = &cur
+			t.DueDate = due
+			log.Printf("[Recurring] 任务 %d 进入新一期 %s，已重置完成状态", t.ID, dateKey(cur))
+		}
+		t.UpdatedAt = now
+		changed++
+	}
+	data.Tasks = append(data.Tasks, spawned...)
+	mu.Unlock()
+
+	if changed == 0 {
+		return 0, nil
+	}
+	return changed, Save()
+}
+
+// OccurrenceHistory 返回某个重复系列已结束各期的完成记录，按日期排序
+func OccurrenceHistory(seriesID int64) []Occurrence {
+	mu.Lock()
+	defer mu.Unlock()
+	var res []Occurrence
+	for _, o := range data.Occurrences {
+		if o.TaskID == seriesID {
+			res = append(res, o)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Date.Before(res[j].Date) })
+	return res
+}
+
+// removeOccurrencesLocked 在系列中已没有任何任务时删除其完成记录，调用方需持有 mu
+func removeOccurrencesLocked(seriesID int64) {
+	for _, t := range data.Tasks {
+		if t.Series() == seriesID {
+			return
+		}
+	}
+	var kept []Occurrence
+	for _, o := range data.Occurrences {
+		if o.TaskID != seriesID {
+			kept = append(kept, o)
+		}
+	}
+	data.Occurrences = kept
+}
@@ -0,0 +1,135 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRepeatRule(t *testing.T) {
+	for rule, want := range map[string]string{
+		"":               RepeatNone,
+		"daily":          RepeatDaily,
+		"weekly:5,1,3,1": "weekly:1,3,5",
+		"weekly:7":       "weekly:7",
+		"every:3":        "every:3",
+	} {
+		spec, err := ParseRepeatRule(rule)
+		if err != nil {
+			t.Fatalf("parse %q: %v", rule, err)
+		}
+		if spec.String() != want {
+			t.Fatalf("parse %q = %q, want %q", rule, spec.String(), want)
+		}
+	}
+	for _, bad := range []string{"weekly:8", "every:0", "monthly", "daily:2"} {
+		if _, err := ParseRepeatRule(bad); err == nil {
+			t.Fatalf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestHolidayCalendar(t *testing.T) {
+	c, err := ParseHolidayCalendar([]byte(`{"holidays":["2025-10-01..2025-10-08"],"workdays":["2025-09-28","2025-10-11"]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	day := func(m time.Month, d int) time.Time { return time.Date(2025, m, d, 0, 0, 0, 0, time.Local) }
+	cases := map[time.Time]bool{
+		day(9, 26):  true,  // 周五
+		day(9, 27):  false, // 周六
+		day(9, 28):  true,  // 周日调休上班
+		day(10, 1):  false, // 国庆
+		day(10, 8):  false, // 周三，仍在假期
+		day(10, 9):  true,
+		day(10, 11): true, // 周六调休上班
+	}
+	for d, want := range cases {
+		if got := c.IsWorkday(d); got != want {
+			t.Fatalf("IsWorkday(%s) = %v, want %v", d.Format("2006-01-02"), got, want)
+		}
+	}
+}
+
+func TestRollOverRecurring(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 9, d, 0, 0, 0, 0, time.Local) }
+	created := day(1).Add(9 * time.Hour) // 周一
+
+	useTestStore(t, dataFile{NextTaskID: 3, NextSessionID: 1, Tasks: []Task{
+		{ID: 1, Title: "背单词", RepeatRule: "weekly:1,3,5", CreatedAt: created},
+		{ID: 2, Title: "周报", RepeatRule: "every:7", RepeatSpawn: true, CreatedAt: created},
+	}})
+
+	// 首次运行只确定当前这一期
+	if n, err := RollOverRecurring(day(1).Add(10 * time.Hour)); err != nil || n != 2 {
+		t.Fatalf("initial rollover: n=%d err=%v", n, err)
+	}
+
+	mu.Lock()
+	data.Tasks[0].IsDone = true
+	data.Tasks[1].IsDone = true
+	mu.Unlock()
+
+	// 同一期内不变
+	if n, _ := RollOverRecurring(day(2)); n != 0 {
+		t.Fatalf("expected no rollover on 9/2, got %d", n)
+	}
+
+	// 9/8 周一：背单词经过了 9/3、9/5 两期，周报生成新任务
+	if n, err := RollOverRecurring(day(8).Add(8 * time.Hour)); err != nil || n != 2 {
+		t.Fatalf("rollover on 9/8: n=%d err=%v", n, err)
+	}
+
+	tasks := AllTasks()
+	if len(tasks) != 3 {
+		t.Fatalf("expected a spawned task, got %d tasks", len(tasks))
+	}
+	if tasks[0].IsDone || !tasks[0].OccurrenceDate.Equal(day(8)) {
+		t.Fatalf("reset task not rolled over: %+v", tasks[0])
+	}
+	if !tasks[1].IsDone || tasks[1].RepeatRule != RepeatNone {
+		t.Fatalf("old spawned task should be kept as done history: %+v", tasks[1])
+	}
+	if next := tasks[2]; next.IsDone || next.Series() != 2 || next.RepeatRule != "every:7" || !next.OccurrenceDate.Equal(day(8)) {
+		t.Fatalf("unexpected spawned task: %+v", next)
+	}
+
+	hist := OccurrenceHistory(1)
+	if len(hist) != 3 || !hist[0].Done || hist[1].Done || !hist[2].Date.Equal(day(5)) {
+		t.Fatalf("unexpected history: %+v", hist)
+	}
+	if hist := OccurrenceHistory(2); len(hist) != 1 || !hist[0].Done {
+		t.Fatalf("unexpected spawn history: %+v", hist)
+	}
+}
+
+func TestSpawnedOccurrenceStartsFresh(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 9, d, 0, 0, 0, 0, time.Local) }
+	useTestStore(t, dataFile{NextTaskID: 3, Tasks: []Task{
+		{ID: 1, Title: "准备材料", SortOrder: 1},
+		{ID: 2, Title: "周报", RepeatRule: "every:7", RepeatSpawn: true, CreatedAt: day(1), SortOrder: 2,
+			Note: "- [x] 汇总数据\n- [ ] 写结论", Tags: []string{"工作"}, RemindBefore: []int{60}, BlockedBy: []int64{1}},
+	}})
+	if _, err := RollOverRecurring(day(1)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := RollOverRecurring(day(8)); err != nil {
+		t.Fatal(err)
+	}
+
+	old, next := data.Tasks[1], data.Tasks[2]
+	if next.SortOrder != 3 {
+		t.Fatalf("spawned sort order = %d, want a fresh position", next.SortOrder)
+	}
+	if next.Note != "- [ ] 汇总数据\n- [ ] 写结论" || old.Note != "- [x] 汇总数据\n- [ ] 写结论" {
+		t.Fatalf("checklist: old %q, new %q", old.Note, next.Note)
+	}
+	if next.BlockedBy != nil {
+		t.Fatalf("spawned task kept blockers: %v", next.BlockedBy)
+	}
+	// 新任务的切片不与旧任务共用
+	next.Tags[0] = "改"
+	next.RemindBefore[0] = 5
+	if old.Tags[0] != "工作" || old.RemindBefore[0] != 60 {
+		t.Fatalf("spawned task shares slices with the old one: %+v", old)
+	}
+}
@@ -0,0 +1,242 @@
+package model
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ReminderOverdue 在 ReminderLog.Offset 中表示“已逾期”提醒
+const ReminderOverdue = -1
+
+// ReminderLog 已发出的提醒，用于保证同一提醒只发一次。
+// Due 记录发出时的截止时间，截止日期修改后旧记录不再生效，提醒会重新安排。
+type ReminderLog struct {
+	TaskID  int64     `json:"task_id"`
+	Offset  int       `json:"offset"` // 提前的分钟数，ReminderOverdue 表示逾期提醒
+	Due     time.Time `json:"due"`
+	FiredAt time.Time `json:"fired_at"`
+}
+
+// Reminder 一条需要发出的提醒
+type Reminder struct {
+	Task   Task
+	Offset int // 提前的分钟数，ReminderOverdue 表示已逾期
+	Due    time.Time
+}
+
+// Digest 每日汇总：已逾期、今天到期与本周内到期的未完成任务
+type Digest struct {
+	Date     time.Time
+	Overdue  []Task
+	Today    []Task
+	ThisWeek []Task
+}
+
+// Empty 汇总中是否没有任何任务
+func (d Digest) Empty() bool {
+	return len(d.Overdue)+len(d.Today)+len(d.ThisWeek) == 0
+}
+
+// DueAt 返回任务的截止时刻。只有日期（零点）的截止日期视为当天结束时（本地时间）截止；
+// 没有截止日期时返回零值。
+func DueAt(t Task) time.Time {
+	if t.DueDate == nil {
+		return time.Time{}
+	}
+	due := *t.DueDate
+	if due.Equal(StartOfDay(due)) {
+		y, m, d := due.Date()
+		return time.Date(y, m, d+1, 0, 0, 0, 0, time.Local)
+	}
+	return due
+}
+
+// DueDay 返回截止时刻所在的日期（本地零点）
+func DueDay(t Task) time.Time {
+	return StartOfDay(DueAt(t).Add(-time.Nanosecond).In(time.Local))
+}
+
+// ParseRemindOffsets 解析提前提醒的设置，如 "1d, 2h, 30m" 或 "1天,2小时"，
+// 返回从早到晚排列（提前量从大到小）的分钟数
+func ParseRemindOffsets(s string) ([]int, error) {
+	s = strings.NewReplacer("，", ",", "、", ",", " ", ",").Replace(s)
+	seen := map[int]bool{}
+	var res []int
+	for _, part := range strings.Split(s, ",") {
+		part = strings.ToLower(strings.TrimSpace(part))
+		if part == "" {
+			continue
+		}
+		unit := 1
+		for _, u := range []struct {
+			suffix  string
+			minutes int
+		}{
+			{"分钟", 1}, {"小时", 60}, {"天", 24 * 60},
+			{"min", 1}, {"m", 1}, {"h", 60}, {"d", 24 * 60},
+		} {
+			if strings.HasSuffix(part, u.suffix) {
+				part = strings.TrimSuffix(part, u.suffix)
+				unit = u.minutes
+				break
+			}
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("无法识别的提醒时间: %q（示例：1d, 2h, 30m）", part)
+		}
+		if !seen[n*unit] {
+			seen[n*unit] = true
+			res = append(res, n*unit)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(res)))
+	return res, nil
+}
+
+// FormatRemindOffset 把提前的分钟数格式化为 "1天"、"2小时"、"30分钟"
+func FormatRemindOffset(minutes int) string {
+	switch {
+	case minutes == 0:
+		return "到期时"
+	case minutes%(24*60) == 0:
+		return fmt.Sprintf("%d天", minutes/(24*60))
+	case minutes%60 == 0:
+		return fmt.Sprintf("%d小时", minutes/60)
+	}
+	return fmt.Sprintf("%d分钟", minutes)
+}
+
+// FormatRemindOffsets 把提醒设置格式化为可再次解析的文本，如 "1d, 2h"
+func FormatRemindOffsets(offsets []int) string {
+	var parts []string
+	for _, m := range offsets {
+		switch {
+		case m != 0 && m%(24*60) == 0:
+			parts = append(parts, fmt.Sprintf("%dd", m/(24*60)))
+		case m != 0 && m%60 == 0:
+			parts = append(parts, fmt.Sprintf("%dh", m/60))
+		default:
+			parts = append(parts, fmt.Sprintf("%dm", m))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// reminderFiredLocked 判断提醒是否已经发过，调用方需持有 mu
+func reminderFiredLocked(taskID int64, offset int, due time.Time) bool {
+	for _, r := range data.Reminders {
+		if r.TaskID == taskID && r.Offset == offset && r.Due.Equal(due) {
+			return true
+		}
+	}
+	return false
+}
+
+// TakeDueReminders 返回到 now 为止应发出且尚未发过的提醒，并立即记为已发出后保存，
+// 因此重启或重复调用都不会重复提醒。
+// 错过的多个提前提醒只发最接近截止时间的一条；已逾期的任务只发一条逾期提醒。
+func TakeDueReminders(now time.Time) ([]Reminder, error) {
+	mu.Lock()
+	var res []Reminder
+	changed := false
+	fire := func(t Task, offset int, due time.Time) {
+		data.Reminders = append(data.Reminders, ReminderLog{TaskID: t.ID, Offset: offset, Due: due, FiredAt: now})
+		changed = true
+	}
+	for _, t := range data.Tasks {
+		if t.IsDone || t.DueDate == nil {
+			continue
+		}
+		due := DueAt(t)
+		if !now.Before(due) {
+			if !reminderFiredLocked(t.ID, ReminderOverdue, due) {
+				fire(t, ReminderOverdue, due)
+				res = append(res, Reminder{Task: t, Offset: ReminderOverdue, Due: due})
+			}
+			continue
+		}
+		// 提前量从大到小，最后一个到点的即最接近截止时间的提醒
+		var latest *Reminder
+		for _, offset := range t.RemindBefore {
+			if now.Before(due.Add(-time.Duration(offset)*time.Minute)) || reminderFiredLocked(t.ID, offset, due) {
+				continue
+			}
+			fire(t, offset, due)
+			latest = &Reminder{Task: t, Offset: offset, Due: due}
+		}
+		if latest != nil {
+			res = append(res, *latest)
+		}
+	}
+	if changed {
+		pruneRemindersLocked()
+	}
+	mu.Unlock()
+
+	if !changed {
+		return nil, nil
+	}
+	log.Printf("[Reminder] 发出 %d 条提醒", len(res))
+	return res, Save()
+}
+
+// pruneRemindersLocked 删除已失效的提醒记录（任务已删除或截止日期已修改），调用方需持有 mu
+func pruneRemindersLocked() {
+	current := map[int64]time.Time{}
+	for _, t := range data.Tasks {
+		if t.DueDate != nil {
+			current[t.ID] = DueAt(t)
+		}
+	}
+	var kept []ReminderLog
+	for _, r := range data.Reminders {
+		if due, ok := current[r.TaskID]; ok && due.Equal(r.Due) {
+			kept = append(kept, r)
+		}
+	}
+	data.Reminders = kept
+}
+
+// TakeDailyDigest 每天第一次调用时返回当天的到期汇总并记录日期，当天再次调用返回 false。
+// “本周”指明天到本周日。
+func TakeDailyDigest(now time.Time) (Digest, bool, error) {
+	mu.Lock()
+	today := dateKey(now)
+	if data.LastDigest == today {
+		mu.Unlock()
+		return Digest{}, false, nil
+	}
+	data.LastDigest = today
+
+	start := StartOfDay(now.In(time.Local))
+	weekday := int(start.Weekday())
+	if weekday == 0 {
+		weekday = 7
+	}
+	weekEnd := start.AddDate(0, 0, 8-weekday)
+	d := Digest{Date: start}
+	for _, t := range data.Tasks {
+		if t.IsDone || t.DueDate == nil {
+			continue
+		}
+		day := DueDay(t)
+		switch {
+		case IsOverdue(t, now):
+			d.Overdue = append(d.Overdue, t)
+		case day.Equal(start):
+			d.Today = append(d.Today, t)
+		case day.After(start) && day.Before(weekEnd):
+			d.ThisWeek = append(d.ThisWeek, t)
+		}
+	}
+	for _, list := range [][]Task{d.Overdue, d.Today, d.ThisWeek} {
+		sort.SliceStable(list, func(i, j int) bool { return DueAt(list[i]).Before(DueAt(list[j])) })
+	}
+	mu.Unlock()
+	return d, !d.Empty(), Save()
+}
@@ -0,0 +1,96 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseRemindOffsets(t *testing.T) {
+	got, err := ParseRemindOffsets("30m, 1d，2小时 1天")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{24 * 60, 120, 30}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("offsets = %v, want %v", got, want)
+	}
+	if s := FormatRemindOffsets(got); s != "1d, 2h, 30m" {
+		t.Fatalf("format = %q", s)
+	}
+	if _, err := ParseRemindOffsets("明天"); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestTakeDueReminders(t *testing.T) {
+	due := time.Date(2025, 9, 10, 0, 0, 0, 0, time.Local) // 只有日期，9 月 10 日结束时截止
+	useTestStore(t, dataFile{Tasks: []Task{
+		{ID: 1, Title: "周报", DueDate: &due, RemindBefore: []int{24 * 60, 120}},
+		{ID: 2, Title: "已完成", DueDate: &due, IsDone: true, RemindBefore: []int{120}},
+	}})
+
+	take := func(now time.Time) []Reminder {
+		t.Helper()
+		rs, err := TakeDueReminders(now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return rs
+	}
+
+	if rs := take(time.Date(2025, 9, 9, 12, 0, 0, 0, time.Local)); len(rs) != 0 {
+		t.Fatalf("too early: %+v", rs)
+	}
+	rs := take(time.Date(2025, 9, 10, 8, 0, 0, 0, time.Local))
+	if len(rs) != 1 || rs[0].Task.ID != 1 || rs[0].Offset != 24*60 {
+		t.Fatalf("1 day before: %+v", rs)
+	}
+	if rs := take(time.Date(2025, 9, 10, 9, 0, 0, 0, time.Local)); len(rs) != 0 {
+		t.Fatalf("fired twice: %+v", rs)
+	}
+	rs = take(time.Date(2025, 9, 11, 1, 0, 0, 0, time.Local))
+	if len(rs) != 1 || rs[0].Offset != ReminderOverdue {
+		t.Fatalf("overdue: %+v", rs)
+	}
+	if rs := take(time.Date(2025, 9, 12, 1, 0, 0, 0, time.Local)); len(rs) != 0 {
+		t.Fatalf("overdue fired twice: %+v", rs)
+	}
+
+	// 修改截止日期后重新安排，错过的多个提醒只发最近的一条
+	newDue := time.Date(2025, 9, 20, 18, 0, 0, 0, time.Local)
+	data.Tasks[0].DueDate = &newDue
+	rs = take(time.Date(2025, 9, 20, 17, 0, 0, 0, time.Local))
+	if len(rs) != 1 || rs[0].Offset != 120 {
+		t.Fatalf("rescheduled: %+v", rs)
+	}
+	if len(data.Reminders) != 2 {
+		t.Fatalf("stale logs not pruned: %+v", data.Reminders)
+	}
+}
+
+func TestTakeDailyDigest(t *testing.T) {
+	day := func(d int) *time.Time {
+		v := time.Date(2025, 9, d, 0, 0, 0, 0, time.Local)
+		return &v
+	}
+	useTestStore(t, dataFile{Tasks: []Task{
+		{ID: 1, Title: "逾期", DueDate: day(8)},
+		{ID: 2, Title: "今天", DueDate: day(10)},
+		{ID: 3, Title: "周五", DueDate: day(12)},
+		{ID: 4, Title: "下周", DueDate: day(15)},
+	}})
+	now := time.Date(2025, 9, 10, 8, 0, 0, 0, time.Local) // 周三
+	d, ok, err := TakeDailyDigest(now)
+	if err != nil || !ok {
+		t.Fatalf("digest: ok=%v err=%v", ok, err)
+	}
+	if len(d.Overdue) != 1 || len(d.Today) != 1 || len(d.ThisWeek) != 1 || d.ThisWeek[0].ID != 3 {
+		t.Fatalf("digest = %+v", d)
+	}
+	if _, ok, _ := TakeDailyDigest(now.Add(time.Hour)); ok {
+		t.Fatal("digest twice on the same day")
+	}
+	if _, ok, _ := TakeDailyDigest(now.AddDate(0, 0, 1)); !ok {
+		t.Fatal("no digest on the next day")
+	}
+}
@@ -0,0 +1,216 @@
+package model
+
+import (
+	"log"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 任务优先级
+const (
+	PriorityNone   = 0
+	PriorityLow    = 1
+	PriorityMedium = 2
+	PriorityHigh   = 3
+)
+
+// PriorityNames 优先级的中文名称，下标与优先级对应
+var PriorityNames = []string{"无", "低", "中", "高"}
+
+// 任务列表的排序方式
+const (
+	SortManual      = "manual"   // 手动拖动的顺序
+	SortPriority    = "priority" // 优先级从高到低
+	SortDueDate     = "due"      // 截止日期从近到远，无截止日期的排在最后
+	SortRecentFocus = "recent"   // 最近专注过的排在前面
+	SortTitle       = "title"    // 按标题
+)
+
+// NoProjectID 在 TaskFilter.ProjectID 中表示“只看不属于任何项目的任务”
+const NoProjectID = -1
+
+// TaskFilter 任务列表的筛选条件，零值表示不筛选
+type TaskFilter struct {
+	HideDone    bool
+	Tag         string // 任务的任一标签等于 Tag
+	ProjectID   int64  // 0 表示全部，NoProjectID 表示无项目
+	OverdueOnly bool
+	// NextActions 只显示可以立即开始的任务：未完成且没有未完成的前置任务
+	NextActions bool
+	// Query 关键字，匹配标题、备注或标签（不区分大小写）
+	Query string
+}
+
+// nextSortOrderLocked 返回排在所有任务之后的 SortOrder，调用方需持有 mu
+func nextSortOrderLocked() int64 {
+	var last int64
+	for _, t := range data.Tasks {
+		if t.SortOrder > last {
+			last = t.SortOrder
+		}
+	}
+	return last + 1
+}
+
+// IsOverdue 判断任务是否已过截止时刻（只有日期时截止当天不算逾期，已完成的任务不算逾期）
+func IsOverdue(t Task, now time.Time) bool {
+	if t.DueDate == nil || t.IsDone {
+		return false
+	}
+	return !now.Before(DueAt(t))
+}
+
+// Match 判断单个任务是否满足筛选条件
+func (f TaskFilter) Match(t Task, now time.Time) bool {
+	if f.HideDone && t.IsDone {
+		return false
+	}
+	if f.OverdueOnly && !IsOverdue(t, now) {
+		return false
+	}
+	if !matchQuery(t, f.Query) {
+		return false
+	}
+	switch {
+	case f.ProjectID == NoProjectID && t.ProjectID != 0:
+		return false
+	case f.ProjectID > 0 && t.ProjectID != f.ProjectID:
+		return false
+	}
+	if f.Tag != "" {
+		found := t.Label == f.Tag
+		for _, tag := range t.Tags {
+			found = found || tag == f.Tag
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// FilterTasks 返回需要显示的任务 ID：满足条件的任务及其各级父任务（保持树形结构）
+func FilterTasks(tasks []Task, f TaskFilter, now time.Time) map[int64]bool {
+	byID := make(map[int64]Task, len(tasks))
+	for _, t := range tasks {
+		byID[t.ID] = t
+	}
+	visible := map[int64]bool{}
+	for _, t := range tasks {
+		if !f.Match(t, now) || (f.NextActions && !IsNextAction(t, byID)) {
+			continue
+		}
+		for _, p := range TaskPath(byID, t.ID) {
+			visible[p.ID] = true
+		}
+	}
+	return visible
+}
+
+// LastFocusTimes 返回每个任务最近一次完成专注的结束时间，子任务的时间会计入父任务
+func LastFocusTimes() map[int64]time.Time {
+	mu.Lock()
+	defer mu.Unlock()
+
+	tasks := taskMapLocked()
+	res := map[int64]time.Time{}
+	for _, s := range data.Sessions {
+		if s.TaskID == nil || s.Interrupted || s.EndedAt.IsZero() {
+			continue
+		}
+		for _, t := range TaskPath(tasks, *s.TaskID) {
+			if s.EndedAt.After(res[t.ID]) {
+				res[t.ID] = s.EndedAt
+			}
+		}
+	}
+	return res
+}
+
+// SortTasks 按排序方式原地排序任务；lastFocus 仅在 SortRecentFocus 时使用。
+// 相同时按手动顺序排列，保证结果稳定。
+func SortTasks(tasks []Task, mode string, lastFocus map[int64]time.Time) {
+	manual := func(a, b Task) bool {
+		if a.SortOrder != b.SortOrder {
+			return a.SortOrder < b.SortOrder
+		}
+		return a.ID < b.ID
+	}
+	var less func(a, b Task) bool
+	switch mode {
+	case SortPriority:
+		less = func(a, b Task) bool {
+			if a.Priority != b.Priority {
+				return a.Priority > b.Priority
+			}
+			return manual(a, b)
+		}
+	case SortDueDate:
+		less = func(a, b Task) bool {
+			switch {
+			case a.DueDate == nil && b.DueDate == nil:
+				return manual(a, b)
+			case a.DueDate == nil:
+				return false
+			case b.DueDate == nil:
+				return true
+			case !a.DueDate.Equal(*b.DueDate):
+				return a.DueDate.Before(*b.DueDate)
+			}
+			return manual(a, b)
+		}
+	case SortRecentFocus:
+		less = func(a, b Task) bool {
+			fa, fb := lastFocus[a.ID], lastFocus[b.ID]
+			if !fa.Equal(fb) {
+				return fa.After(fb)
+			}
+			return manual(a, b)
+		}
+	case SortTitle:
+		less = func(a, b Task) bool {
+			ta, tb := strings.ToLower(a.Title), strings.ToLower(b.Title)
+			if ta != tb {
+				return ta < tb
+			}
+			return manual(a, b)
+		}
+	default:
+		less = manual
+	}
+	sort.SliceStable(tasks, func(i, j int) bool { return less(tasks[i], tasks[j]) })
+}
+
+// SetTaskOrder 按 ids 的顺序重新排列这些任务：沿用它们原有的 SortOrder 取值，
+// 只交换位置，因此不会影响未列出的任务（如被筛选隐藏的同级任务）。
+func SetTaskOrder(ids []int64) error {
+	mu.Lock()
+	index := make(map[int64]int, len(data.Tasks))
+	for i, t := range data.Tasks {
+		index[t.ID] = i
+	}
+	var orders []int64
+	for _, id := range ids {
+		if i, ok := index[id]; ok {
+			orders = append(orders, data.Tasks[i].SortOrder)
+		}
+	}
+	sort.Slice(orders, func(i, j int) bool { return orders[i] < orders[j] })
+	// 有重复取值时顺延，保证顺序严格递增
+	for i := 1; i < len(orders); i++ {
+		if orders[i] <= orders[i-1] {
+			orders[i] = orders[i-1] + 1
+		}
+	}
+	n := 0
+	for _, id := range ids {
+		if i, ok := index[id]; ok {
+			data.Tasks[i].SortOrder = orders[n]
+			n++
+		}
+	}
+	mu.Unlock()
+	log.Printf("[SetTaskOrder] %v", ids)
+	return Save()
+}
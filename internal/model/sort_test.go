@@ -0,0 +1,85 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSortAndFilterTasks(t *testing.T) {
+	now := time.Date(2025, 7, 10, 9, 0, 0, 0, time.Local)
+	yesterday := now.AddDate(0, 0, -1)
+	tomorrow := now.AddDate(0, 0, 1)
+	tasks := []Task{
+		{ID: 1, Title: "b", SortOrder: 3, Priority: PriorityLow, DueDate: &tomorrow},
+		{ID: 2, Title: "A", SortOrder: 1, Priority: PriorityHigh},
+		{ID: 3, Title: "c", SortOrder: 2, DueDate: &yesterday, Tags: []string{"工作"}, ProjectID: 1},
+		{ID: 4, Title: "d", SortOrder: 4, ParentID: 3, IsDone: true, Tags: []string{"学习"}},
+	}
+	order := func(mode string, last map[int64]time.Time) []int64 {
+		sorted := append([]Task(nil), tasks...)
+		SortTasks(sorted, mode, last)
+		var ids []int64
+		for _, t := range sorted {
+			ids = append(ids, t.ID)
+		}
+		return ids
+	}
+	for mode, want := range map[string][]int64{
+		SortManual:   {2, 3, 1, 4},
+		SortPriority: {2, 1, 3, 4},
+		SortDueDate:  {3, 1, 2, 4},
+		SortTitle:    {2, 1, 3, 4},
+	} {
+		if got := order(mode, nil); !equalIDs(got, want) {
+			t.Fatalf("%s order = %v, want %v", mode, got, want)
+		}
+	}
+	if got := order(SortRecentFocus, map[int64]time.Time{4: now, 1: yesterday}); !equalIDs(got, []int64{4, 1, 2, 3}) {
+		t.Fatalf("recent order = %v", got)
+	}
+
+	if visible := FilterTasks(tasks, TaskFilter{OverdueOnly: true}, now); len(visible) != 1 || !visible[3] {
+		t.Fatalf("only task 3 is overdue: %v", visible)
+	}
+	// 子任务匹配时父任务也要显示
+	if visible := FilterTasks(tasks, TaskFilter{Tag: "学习"}, now); len(visible) != 2 || !visible[3] || !visible[4] {
+		t.Fatalf("tag filter should keep ancestors: %v", visible)
+	}
+	if visible := FilterTasks(tasks, TaskFilter{HideDone: true, ProjectID: NoProjectID}, now); len(visible) != 2 || visible[3] || visible[4] {
+		t.Fatalf("unexpected no-project filter: %v", visible)
+	}
+}
+
+func TestSetTaskOrder(t *testing.T) {
+	useTestStore(t, dataFile{Version: currentDataVersion, NextTaskID: 1})
+	for _, title := range []string{"a", "b", "c", "d"} {
+		if err := AddTask(&Task{Title: title}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 只重排 1、3、4，任务 2 的位置不变
+	if err := SetTaskOrder([]int64{4, 1, 3}); err != nil {
+		t.Fatal(err)
+	}
+	sorted := AllTasks()
+	SortTasks(sorted, SortManual, nil)
+	var ids []int64
+	for _, t := range sorted {
+		ids = append(ids, t.ID)
+	}
+	if !equalIDs(ids, []int64{4, 2, 1, 3}) {
+		t.Fatalf("unexpected manual order %v", ids)
+	}
+}
+
+func equalIDs(a, b []int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
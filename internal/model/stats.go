@@ -0,0 +1,87 @@
+package model
+
+import (
+	"sort"
+	"time"
+)
+
+// SessionOverlap 返回计时记录与区间 [from,to] 重叠的秒数，未结束的记录返回 0。
+func SessionOverlap(s TimerSession, from, to time.Time) int {
+	return sessionOverlapSeconds(s, from, to)
+}
+
+// StartOfDay 返回 t 所在日期（按 t 的时区）的零点
+func StartOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// sessionLabelLocked 返回计时记录所属任务的标签，自由计时或无标签时为 DefaultLabel。
+// 调用方需持有 mu。
+func sessionLabelLocked(s TimerSession, tasks map[int64]Task) string {
+	if s.TaskID != nil {
+		if t, ok := tasks[*s.TaskID]; ok && t.Label != "" {
+			return t.Label
+		}
+	}
+	return DefaultLabel
+}
+
+// taskMapLocked 构建任务 ID 到任务的映射，调用方需持有 mu
+func taskMapLocked() map[int64]Task {
+	m := make(map[int64]Task, len(data.Tasks))
+	for _, t := range data.Tasks {
+		m[t.ID] = t
+	}
+	return m
+}
+
+// DailyFocus 返回 [from,to) 内每天的专注秒数，键为当天零点（按 from 的时区）。
+// label 非空时只统计该标签；跨天的记录按重叠部分拆分到各天。
+func DailyFocus(from, to time.Time, label string) map[time.Time]int {
+	mu.Lock()
+	defer mu.Unlock()
+
+	tasks := taskMapLocked()
+	res := map[time.Time]int{}
+	loc := from.Location()
+	for _, s := range data.Sessions {
+		if s.Interrupted || s.EndedAt.IsZero() {
+			continue
+		}
+		if s.EndedAt.Before(from) || !s.StartedAt.Before(to) {
+			continue
+		}
+		if label != "" && sessionLabelLocked(s, tasks) != label {
+			continue
+		}
+		for day := StartOfDay(s.StartedAt.In(loc)); day.Before(s.EndedAt) && day.Before(to); day = day.AddDate(0, 0, 1) {
+			if day.Before(StartOfDay(from)) {
+				continue
+			}
+			if sec := sessionOverlapSeconds(s, day, day.AddDate(0, 0, 1)); sec > 0 {
+				res[day] += sec
+			}
+		}
+	}
+	return res
+}
+
+// Labels 返回所有任务使用过的标签（含 DefaultLabel），按字典序排列
+func Labels() []string {
+	mu.Lock()
+	defer mu.Unlock()
+
+	seen := map[string]bool{DefaultLabel: true}
+	for _, t := range data.Tasks {
+		if t.Label != "" {
+			seen[t.Label] = true
+		}
+	}
+	labels := make([]string, 0, len(seen))
+	for l := range seen {
+		labels = append(labels, l)
+	}
+	sort.Strings(labels)
+	return labels
+}
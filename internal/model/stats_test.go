@@ -0,0 +1,33 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDailyFocus(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 7, d, 0, 0, 0, 0, time.UTC) }
+	id := int64(1)
+
+	mu.Lock()
+	data.Tasks = []Task{{ID: id, Title: "读书", Label: "学习"}}
+	data.Sessions = []TimerSession{
+		// 跨越 7/1 午夜：7/1 一小时，7/2 一小时
+		{ID: 1, TaskID: &id, Mode: "countup", StartedAt: day(1).Add(23 * time.Hour), EndedAt: day(2).Add(time.Hour), DurationSec: 7200},
+		{ID: 2, Mode: "countup", StartedAt: day(2).Add(9 * time.Hour), EndedAt: day(2).Add(10 * time.Hour), DurationSec: 3600},
+		{ID: 3, TaskID: &id, Mode: "countdown", StartedAt: day(3), EndedAt: day(3).Add(time.Minute), Interrupted: true},
+	}
+	mu.Unlock()
+
+	all := DailyFocus(day(1), day(4), "")
+	if all[day(1)] != 3600 || all[day(2)] != 7200 || len(all) != 2 {
+		t.Fatalf("unexpected daily focus: %v", all)
+	}
+	study := DailyFocus(day(2), day(4), "学习")
+	if study[day(2)] != 3600 || len(study) != 1 {
+		t.Fatalf("unexpected 学习 daily focus: %v", study)
+	}
+	if labels := Labels(); len(labels) != 2 {
+		t.Fatalf("expected 学习 and %s, got %v", DefaultLabel, labels)
+	}
+}
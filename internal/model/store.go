@@ -21,18 +21,58 @@ const DefaultLabel = "未分类"
 // nowFunc 用于获取当前时间，测试时可覆盖
 var nowFunc = time.Now
 
+// currentDataVersion 数据文件的结构版本，升级时在 migrateLocked 中迁移旧数据
+const currentDataVersion = 4
+
+// dataFile 是数据文件的完整结构
+type dataFile struct {
+	Version       int            `json:"version,omitempty"`
+	NextTaskID    int64          `json:"next_task_id"`
+	NextSessionID int64          `json:"next_session_id"`
+	NextProjectID int64          `json:"next_project_id,omitempty"`
+	Tasks         []Task         `json:"tasks"`
+	Sessions      []TimerSession `json:"sessions"`
+	Projects      []Project      `json:"projects,omitempty"`
+	// Occurrences 重复任务每一期的完成情况
+	Occurrences []Occurrence `json:"occurrences,omitempty"`
+	// Reminders 已发出的截止提醒，LastDigest 最近一次每日汇总的日期
+	Reminders  []ReminderLog `json:"reminders,omitempty"`
+	LastDigest string        `json:"last_digest,omitempty"`
+	// Events 倒数日/纪念日
+	NextEventID int64   `json:"next_event_id,omitempty"`
+	Events      []Event `json:"events,omitempty"`
+	// LegacyCountdowns 升级时无法确定是否为旧版倒数日的任务，等待用户确认是否转换
+	LegacyCountdowns []int64 `json:"legacy_countdowns,omitempty"`
+}
+
 // in-memory 数据结构
 var (
-	mu   sync.Mutex
-	data struct {
-		NextTaskID    int64          `json:"next_task_id"`
-		NextSessionID int64          `json:"next_session_id"`
-		Tasks         []Task         `json:"tasks"`
-		Sessions      []TimerSession `json:"sessions"`
-	}
+	mu       sync.Mutex
+	data     dataFile
 	filePath string
+
+	listenersMu sync.Mutex
+	listeners   []func()
 )
 
+// OnChange 注册数据变更回调，在每次成功保存或重新加载后调用。
+// 回调在调用 Save/Reload 的协程中执行，此时未持有数据锁，可以读取数据。
+func OnChange(fn func()) {
+	listenersMu.Lock()
+	listeners = append(listeners, fn)
+	listenersMu.Unlock()
+}
+
+// notifyChange 依次调用已注册的变更回调
+func notifyChange() {
+	listenersMu.Lock()
+	fns := append([]func(){}, listeners...)
+	listenersMu.Unlock()
+	for _, fn := range fns {
+		fn()
+	}
+}
+
 // Init 在程序启动时调用，负责加载数据文件（若存在）。
 func Init() error {
 	home, err := os.UserHomeDir()
@@ -40,13 +80,16 @@ func Init() error {
 		return err
 	}
 	filePath = filepath.Join(home, dataFileName)
+	loadDefaultHolidays(home)
 
 	b, err := os.ReadFile(filePath)
 	if err != nil {
 		if os.IsNotExist(err) {
 			// 第一次运行：初始化默认值即可
+			data.Version = currentDataVersion
 			data.NextTaskID = 1
 			data.NextSessionID = 1
+			data.NextProjectID = 1
 			return Save()
 		}
 		return err
@@ -68,9 +111,60 @@ func Init() error {
 			log.Printf("[DEBUG] 修正无秒时间戳并成功加载数据文件")
 		}
 	}
+	mu.Lock()
+	migrated := migrateLocked(&data)
+	mu.Unlock()
+	if migrated {
+		return Save()
+	}
 	return nil
 }
 
+// migrateLocked 将旧版本的数据迁移到 currentDataVersion，返回是否有改动
+func migrateLocked(d *dataFile) bool {
+	if d.Version >= currentDataVersion {
+		return false
+	}
+	if d.Version < 1 {
+		// v1: 单一 Label 迁移为 Tags，Label 保留为主标签
+		for i := range d.Tasks {
+			normalizeTaskTags(&d.Tasks[i])
+		}
+		if d.NextProjectID == 0 {
+			d.NextProjectID = 1
+		}
+	}
+	if d.Version < 2 {
+		// v2: 按原有的插入顺序初始化手动排序
+		for i := range d.Tasks {
+			d.Tasks[i].SortOrder = int64(i + 1)
+		}
+	}
+	if d.Version < 3 {
+		// v3: 已完成的旧任务以最后修改时间作为完成时间
+		for i := range d.Tasks {
+			if t := &d.Tasks[i]; t.IsDone && t.CompletedAt == nil {
+				completed := t.UpdatedAt
+				t.CompletedAt = &completed
+			}
+		}
+	}
+	if d.Version < 4 {
+		// v4: 旧版“新建倒数日”创建的是带截止日期的任务，迁移为倒数日。
+		// v2 之前任务不能设置截止日期，带截止日期的只可能是倒数日，直接转换；
+		// 之后的版本无法区分，记录下来由用户确认
+		ids := legacyCountdownIDs(d)
+		if d.Version < 2 {
+			convertTasksToEvents(d, ids, time.Now())
+		} else {
+			d.LegacyCountdowns = ids
+		}
+	}
+	log.Printf("[DEBUG] 数据文件从版本 %d 迁移到 %d", d.Version, currentDataVersion)
+	d.Version = currentDataVersion
+	return true
+}
+
 // Reload 重新从文件加载数据到内存，保持 filePath 不变。
 // 若数据文件不存在，保持现有内存数据不变并返回 os.ErrNotExist。
 func Reload() error {
@@ -81,12 +175,7 @@ func Reload() error {
 	if err != nil {
 		return err
 	}
-	var newData struct {
-		NextTaskID    int64          `json:"next_task_id"`
-		NextSessionID int64          `json:"next_session_id"`
-		Tasks         []Task         `json:"tasks"`
-		Sessions      []TimerSession `json:"sessions"`
-	}
+	var newData dataFile
 	if err := json.Unmarshal(b, &newData); err != nil {
 		patched := fixTimestampWithoutSeconds(b)
 		if patched == nil {
@@ -97,13 +186,24 @@ func Reload() error {
 		}
 	}
 	mu.Lock()
+	migrateLocked(&newData)
 	data = newData
 	mu.Unlock()
+	notifyChange()
 	return nil
 }
 
-// Save 将内存数据写回文件。
+// Save 将内存数据写回文件，成功后通知变更回调。
 func Save() error {
+	if err := save(); err != nil {
+		return err
+	}
+	notifyChange()
+	return nil
+}
+
+// save 在持有数据锁的情况下写文件
+func save() error {
 	mu.Lock()
 	defer mu.Unlock()
 
@@ -144,13 +244,32 @@ func nextSessionID() int64 {
 func AddTask(t *Task) error {
 	mu.Lock()
 	now := time.Now()
+	if t.ParentID != 0 {
+		parent, ok := taskMapLocked()[t.ParentID]
+		if !ok {
+			mu.Unlock()
+			return fmt.Errorf("parent task id %d not found", t.ParentID)
+		}
+		// 子任务未设置标签、项目时沿用父任务的设置
+		if t.Label == "" && len(t.Tags) == 0 {
+			t.Label = parent.Label
+			t.Tags = append([]string(nil), parent.Tags...)
+		}
+		if t.ProjectID == 0 {
+			t.ProjectID = parent.ProjectID
+		}
+	}
 	// 如果没有设置标签，默认赋值为"学习"
-	if t.Label == "" {
+	if t.Label == "" && len(NormalizeTags(t.Tags)) == 0 {
 		t.Label = "学习"
 	}
+	normalizeTaskTags(t)
 	t.ID = nextTaskID()
+	normalizeBlockedByLocked(t)
+	t.SortOrder = nextSortOrderLocked()
 	t.CreatedAt = now
 	t.UpdatedAt = now
+	setCompletedLocked(t, t.IsDone, now)
 
 	data.Tasks = append(data.Tasks, *t)
 	mu.Unlock()
@@ -161,6 +280,35 @@ func AddTask(t *Task) error {
 	return nil
 }
 
+// AddTasks 批量插入任务（用于导入），重新分配 ID 并只保存一次。
+// 返回与入参一一对应的新 ID；CreatedAt/UpdatedAt 非零时保留原值。
+func AddTasks(tasks []Task) ([]int64, error) {
+	mu.Lock()
+	now := time.Now()
+	ids := make([]int64, len(tasks))
+	for i, t := range tasks {
+		t.ID = nextTaskID()
+		t.SortOrder = nextSortOrderLocked()
+		normalizeTaskTags(&t)
+		normalizeBlockedByLocked(&t)
+		if t.CreatedAt.IsZero() {
+			t.CreatedAt = now
+		}
+		if t.UpdatedAt.IsZero() {
+			t.UpdatedAt = now
+		}
+		setCompletedLocked(&t, t.IsDone, t.UpdatedAt)
+		ids[i] = t.ID
+		data.Tasks = append(data.Tasks, t)
+	}
+	mu.Unlock()
+	if err := Save(); err != nil {
+		return nil, err
+	}
+	log.Printf("[AddTasks] count=%d", len(tasks))
+	return ids, nil
+}
+
 // AllTasks 获取全部任务切片（拷贝）
 func AllTasks() []Task {
 	mu.Lock()
@@ -173,13 +321,28 @@ func AllTasks() []Task {
 // UpdateTask 更新已有任务信息（根据ID）
 func UpdateTask(t Task) error {
 	mu.Lock()
+	if t.ParentID != 0 && wouldCycleLocked(t.ID, t.ParentID) {
+		mu.Unlock()
+		return fmt.Errorf("不能将任务移动到它自己或它的子任务下")
+	}
+	normalizeBlockedByLocked(&t)
+	if dependencyCycleLocked(t.ID, t.BlockedBy) {
+		mu.Unlock()
+		return errDependencyCycle
+	}
 	var found bool
 	for i, task := range data.Tasks {
 		if task.ID == t.ID {
 			// 保留创建时间
 			t.CreatedAt = task.CreatedAt
+			normalizeTaskTags(&t)
+			// 重复规则变化后由 RollOverRecurring 重新确定当前这一期
+			if t.RepeatRule != task.RepeatRule {
+				t.OccurrenceDate = nil
+			}
 			// 更新时间戳
 			t.UpdatedAt = time.Now()
+			setCompletedLocked(&t, t.IsDone, t.UpdatedAt)
 			data.Tasks[i] = t
 			found = true
 			break
@@ -216,6 +379,21 @@ func StartTimerSession(taskID *int64, mode string, targetSeconds int) (int64, er
 	return s.ID, nil
 }
 
+// AddSessions 批量插入已结束的计时记录（用于导入），重新分配 ID 并只保存一次。
+func AddSessions(sessions []TimerSession) error {
+	mu.Lock()
+	for _, s := range sessions {
+		s.ID = nextSessionID()
+		data.Sessions = append(data.Sessions, s)
+	}
+	mu.Unlock()
+	if err := Save(); err != nil {
+		return err
+	}
+	log.Printf("[AddSessions] count=%d", len(sessions))
+	return nil
+}
+
 func EndTimerSession(id int64, interrupted bool) error {
 	mu.Lock()
 	var modified bool
@@ -241,6 +419,28 @@ func EndTimerSession(id int64, interrupted bool) error {
 	return nil
 }
 
+// addSessionBell 在计时记录中追加一次正念铃声
+func addSessionBell(id int64, elapsedSec int) error {
+	mu.Lock()
+	var found bool
+	for i, s := range data.Sessions {
+		if s.ID == id {
+			data.Sessions[i].Bells = append(data.Sessions[i].Bells, elapsedSec)
+			found = true
+			break
+		}
+	}
+	mu.Unlock()
+	if !found {
+		return nil // 未找到记录
+	}
+	if err := Save(); err != nil {
+		return err
+	}
+	log.Printf("[RecordBell] id=%d elapsed=%d", id, elapsedSec)
+	return nil
+}
+
 // UpdateSessionTask 修改计时记录的 TaskID (nil 表示自由计时)
 func updateSessionTask(id int64, taskID *int64) error {
 	mu.Lock()
@@ -357,6 +557,16 @@ func CompletedSessions() []TimerSession {
 	return list
 }
 
+// AllSessions 返回全部计时记录的副本（含中断与未结束的记录），按开始时间排序。
+func AllSessions() []TimerSession {
+	mu.Lock()
+	defer mu.Unlock()
+	res := make([]TimerSession, len(data.Sessions))
+	copy(res, data.Sessions)
+	sort.Slice(res, func(i, j int) bool { return res[i].StartedAt.Before(res[j].StartedAt) })
+	return res
+}
+
 // PrintSessionsSummary 将完成的计时记录打印到 stdout。
 func PrintSessionsSummary() {
 	sessions := CompletedSessions()
@@ -381,27 +591,12 @@ func ClearSessions() error {
 	return Save()
 }
 
-// DeleteTask 根据 ID 删除任务及其关联计时记录
+// DeleteTask 根据 ID 删除任务及其关联计时记录，子任务移到被删除任务的父任务下
 func DeleteTask(id int64) error {
 	mu.Lock()
-	// 删除任务
-	var newTasks []Task
-	for _, t := range data.Tasks {
-		if t.ID != id {
-			newTasks = append(newTasks, t)
-		}
-	}
-	data.Tasks = newTasks
-	// 删除引用该任务的 session
-	var newSess []TimerSession
-	for _, s := range data.Sessions {
-		if s.TaskID == nil || *s.TaskID != id {
-			newSess = append(newSess, s)
-		}
-	}
-	data.Sessions = newSess
+	parent := taskMapLocked()[id].ParentID
 	mu.Unlock()
-	return Save()
+	return DeleteTaskRehome(id, parent)
 }
 
 // sessionOverlapSeconds 计算计时记录与指定区间 [from,to] 的重叠秒数。
@@ -503,6 +698,7 @@ func Last24HoursFocusTimeByLabel() map[string]int {
 	startTime := now.Add(-24 * time.Hour)
 
 	result := map[string]int{}
+	tasks := taskMapLocked()
 
 	for _, s := range data.Sessions {
 		if s.Interrupted || s.EndedAt.IsZero() {
@@ -514,19 +710,7 @@ func Last24HoursFocusTimeByLabel() map[string]int {
 			continue
 		}
 
-		label := DefaultLabel
-		if s.TaskID != nil {
-			for _, t := range data.Tasks {
-				if t.ID == *s.TaskID {
-					if t.Label != "" {
-						label = t.Label
-					}
-					break
-				}
-			}
-		}
-
-		result[label] += overlap
+		result[sessionLabelLocked(s, tasks)] += overlap
 	}
 
 	return result
@@ -1,10 +1,26 @@
 package model
 
 import (
+	"path/filepath"
 	"testing"
 	"time"
 )
 
+// useTestStore 让测试使用临时数据文件与给定的内存数据，结束时恢复原来的数据、文件路径与时钟
+func useTestStore(t *testing.T, d dataFile) {
+	t.Helper()
+	mu.Lock()
+	oldData, oldPath, oldNow := data, filePath, nowFunc
+	data = d
+	filePath = filepath.Join(t.TempDir(), dataFileName)
+	mu.Unlock()
+	t.Cleanup(func() {
+		mu.Lock()
+		data, filePath, nowFunc = oldData, oldPath, oldNow
+		mu.Unlock()
+	})
+}
+
 func TestLast24HoursFocusTimeAndByLabel(t *testing.T) {
 	// 重置数据
 	mu.Lock()
@@ -70,3 +86,24 @@ func TestLast24HoursFocusTimeAndByLabel(t *testing.T) {
 		t.Fatalf("label 未分类 expected 1800, got %d", byLabel[DefaultLabel])
 	}
 }
+
+func TestRecordBell(t *testing.T) {
+	useTestStore(t, dataFile{NextSessionID: 1})
+
+	id, err := StartSession(nil, "countdown", 1500)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, sec := range []int{300, 780} {
+		if err := RecordBell(id, sec); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := Reload(); err != nil {
+		t.Fatal(err)
+	}
+	sessions := AllSessions()
+	if len(sessions) != 1 || len(sessions[0].Bells) != 2 || sessions[0].Bells[1] != 780 {
+		t.Fatalf("sessions = %+v", sessions)
+	}
+}
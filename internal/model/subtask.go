@@ -0,0 +1,134 @@
+package model
+
+import (
+	"fmt"
+	"log"
+	"strings"
+)
+
+// ChildIndex 按父任务分组任务 ID，键 0 为顶层任务；父任务不存在的任务视为顶层任务。
+// 同一父任务下的子任务保持 tasks 中的顺序。
+func ChildIndex(tasks []Task) map[int64][]int64 {
+	exists := make(map[int64]bool, len(tasks))
+	for _, t := range tasks {
+		exists[t.ID] = true
+	}
+	idx := map[int64][]int64{}
+	for _, t := range tasks {
+		parent := t.ParentID
+		if !exists[parent] || parent == t.ID {
+			parent = 0
+		}
+		idx[parent] = append(idx[parent], t.ID)
+	}
+	return idx
+}
+
+// TaskPath 返回从顶层任务到 id 的任务链（含 id 本身），任务不存在时返回 nil
+func TaskPath(byID map[int64]Task, id int64) []Task {
+	var path []Task
+	seen := map[int64]bool{}
+	for id != 0 && !seen[id] {
+		t, ok := byID[id]
+		if !ok {
+			break
+		}
+		seen[id] = true
+		path = append([]Task{t}, path...)
+		id = t.ParentID
+	}
+	return path
+}
+
+// TaskPathTitle 返回 "父任务 / 子任务" 形式的完整标题
+func TaskPathTitle(byID map[int64]Task, id int64) string {
+	var titles []string
+	for _, t := range TaskPath(byID, id) {
+		titles = append(titles, t.Title)
+	}
+	return strings.Join(titles, " / ")
+}
+
+// wouldCycleLocked 判断把 id 移到 parentID 下是否会形成环，调用方需持有 mu
+func wouldCycleLocked(id, parentID int64) bool {
+	for _, t := range TaskPath(taskMapLocked(), parentID) {
+		if t.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// descendantsLocked 返回 id 的全部子孙任务 ID（不含 id 本身），调用方需持有 mu
+func descendantsLocked(id int64) []int64 {
+	idx := ChildIndex(data.Tasks)
+	var res []int64
+	queue := idx[id]
+	for len(queue) > 0 {
+		c := queue[0]
+		queue = queue[1:]
+		res = append(res, c)
+		queue = append(queue, idx[c]...)
+	}
+	return res
+}
+
+// deleteTasksLocked 删除一组任务及引用它们的计时记录，调用方需持有 mu
+func deleteTasksLocked(ids map[int64]bool) {
+	var series []int64
+	var newTasks []Task
+	for _, t := range data.Tasks {
+		if ids[t.ID] {
+			series = append(series, t.Series())
+		} else {
+			newTasks = append(newTasks, t)
+		}
+	}
+	data.Tasks = newTasks
+	removeBlockersLocked(ids)
+
+	var newSess []TimerSession
+	for _, s := range data.Sessions {
+		if s.TaskID == nil || !ids[*s.TaskID] {
+			newSess = append(newSess, s)
+		}
+	}
+	data.Sessions = newSess
+
+	for _, sid := range series {
+		removeOccurrencesLocked(sid)
+	}
+}
+
+// DeleteTaskRehome 删除任务及其计时记录，并把它的直接子任务移到 newParent 下（0 表示顶层）
+func DeleteTaskRehome(id, newParent int64) error {
+	mu.Lock()
+	if newParent == id || (newParent != 0 && wouldCycleLocked(id, newParent)) {
+		mu.Unlock()
+		return fmt.Errorf("不能将子任务移到被删除的任务或其子任务下")
+	}
+	var moved int
+	for i := range data.Tasks {
+		if data.Tasks[i].ParentID == id && data.Tasks[i].ID != id {
+			data.Tasks[i].ParentID = newParent
+			moved++
+		}
+	}
+	deleteTasksLocked(map[int64]bool{id: true})
+	mu.Unlock()
+	log.Printf("[DeleteTask] id=%d 子任务%d个移到 parent=%d", id, moved, newParent)
+	return Save()
+}
+
+// DeleteTaskTree 删除任务及其全部子孙任务与它们的计时记录
+func DeleteTaskTree(id int64) error {
+	mu.Lock()
+	ids := map[int64]bool{id: true}
+	for _, d := range descendantsLocked(id) {
+		ids[d] = true
+	}
+	deleteTasksLocked(ids)
+	mu.Unlock()
+	log.Printf("[DeleteTaskTree] id=%d 共删除%d个任务", id, len(ids))
+	return Save()
+}
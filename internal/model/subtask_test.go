@@ -0,0 +1,65 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSubtasks(t *testing.T) {
+	useTestStore(t, dataFile{NextTaskID: 1, NextSessionID: 1})
+
+	root := &Task{Title: "论文", Label: "学习"}
+	if err := AddTask(root); err != nil {
+		t.Fatal(err)
+	}
+	chapter := &Task{Title: "第一章", ParentID: root.ID}
+	if err := AddTask(chapter); err != nil {
+		t.Fatal(err)
+	}
+	section := &Task{Title: "1.1", ParentID: chapter.ID}
+	if err := AddTask(section); err != nil {
+		t.Fatal(err)
+	}
+	if section.Label != "学习" {
+		t.Fatalf("subtask should inherit parent label, got %q", section.Label)
+	}
+
+	// 不能把父任务移到自己的子孙任务下
+	moved := *root
+	moved.ParentID = section.ID
+	if err := UpdateTask(moved); err == nil {
+		t.Fatalf("expected cycle error")
+	}
+
+	start := time.Now().Add(-time.Hour)
+	mu.Lock()
+	data.Sessions = []TimerSession{
+		{ID: 1, TaskID: &section.ID, Mode: "countdown", StartedAt: start, EndedAt: start.Add(25 * time.Minute), DurationSec: 1500},
+		{ID: 2, TaskID: &root.ID, Mode: "countup", StartedAt: start, EndedAt: start.Add(50 * time.Minute), DurationSec: 3000},
+	}
+	mu.Unlock()
+
+	focus := TaskFocusStats()
+	if f := focus[root.ID]; f.FocusSeconds != 4500 || f.OwnFocusSeconds != 3000 || f.Pomodoros != 3 {
+		t.Fatalf("unexpected root focus: %+v", f)
+	}
+	if f := focus[chapter.ID]; f.FocusSeconds != 1500 || f.OwnFocusSeconds != 0 {
+		t.Fatalf("unexpected chapter focus: %+v", f)
+	}
+
+	// 删除中间层时子任务移到上一级
+	if err := DeleteTask(chapter.ID); err != nil {
+		t.Fatal(err)
+	}
+	idx := ChildIndex(AllTasks())
+	if len(idx[root.ID]) != 1 || idx[root.ID][0] != section.ID {
+		t.Fatalf("section should be re-homed under root: %v", idx)
+	}
+
+	if err := DeleteTaskTree(root.ID); err != nil {
+		t.Fatal(err)
+	}
+	if len(AllTasks()) != 0 || len(AllSessions()) != 0 {
+		t.Fatalf("deleting a tree should remove all tasks and sessions")
+	}
+}
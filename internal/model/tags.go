@@ -0,0 +1,133 @@
+package model
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// 统计的分组方式
+const (
+	GroupByLabel   = "label"   // 按主标签（即旧版的单一标签字段 Label），每条记录只计一次
+	GroupByProject = "project" // 按所属项目
+	GroupByTag     = "tag"     // 按标签，多标签任务的时长计入每个标签
+)
+
+// NormalizeTags 去掉空白与重复的标签，保持原有顺序
+func NormalizeTags(tags []string) []string {
+	var res []string
+	seen := map[string]bool{}
+	for _, tag := range tags {
+		tag = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		res = append(res, tag)
+	}
+	return res
+}
+
+// normalizeTaskTags 保持 Tags 与旧版 Label 一致：
+// 有标签时 Label 为第一个标签；只有 Label 时将其迁移为唯一的标签。
+func normalizeTaskTags(t *Task) {
+	t.Tags = NormalizeTags(t.Tags)
+	if len(t.Tags) > 0 {
+		t.Label = t.Tags[0]
+		return
+	}
+	t.Label = strings.TrimSpace(t.Label)
+	if t.Label != "" {
+		t.Tags = []string{t.Label}
+	}
+}
+
+// PrimaryTag 返回任务的主标签，没有标签时返回 DefaultLabel
+func (t Task) PrimaryTag() string {
+	if len(t.Tags) > 0 {
+		return t.Tags[0]
+	}
+	if t.Label != "" {
+		return t.Label
+	}
+	return DefaultLabel
+}
+
+// Tags 返回所有任务使用过的标签，按字典序排列
+func Tags() []string {
+	mu.Lock()
+	defer mu.Unlock()
+	seen := map[string]bool{}
+	for _, t := range data.Tasks {
+		for _, tag := range t.Tags {
+			seen[tag] = true
+		}
+	}
+	tags := make([]string, 0, len(seen))
+	for tag := range seen {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// sessionGroupsLocked 返回计时记录在指定分组方式下所属的分组（GroupByTag 时可能有多个），
+// 调用方需持有 mu。
+func sessionGroupsLocked(s TimerSession, tasks map[int64]Task, mode string) []string {
+	var t *Task
+	if s.TaskID != nil {
+		if task, ok := tasks[*s.TaskID]; ok {
+			t = &task
+		}
+	}
+	switch mode {
+	case GroupByProject:
+		if t == nil {
+			return []string{NoProject}
+		}
+		return []string{projectNameLocked(t.ProjectID)}
+	case GroupByTag:
+		if t == nil || len(t.Tags) == 0 {
+			return []string{sessionLabelLocked(s, tasks)}
+		}
+		return t.Tags
+	}
+	return []string{sessionLabelLocked(s, tasks)}
+}
+
+// SessionGroups 返回计时记录在指定分组方式下所属的分组，规则与 FocusByGroup 相同。
+// tasks 由调用方给定（如报表的输入数据），项目名称取自当前数据。
+func SessionGroups(s TimerSession, tasks map[int64]Task, mode string) []string {
+	mu.Lock()
+	defer mu.Unlock()
+	return sessionGroupsLocked(s, tasks, mode)
+}
+
+// FocusByGroup 返回 [from,to] 内按分组方式汇总的专注秒数。
+// GroupByTag 下多标签任务的时长计入每个标签，因此各分组之和可能大于总时长。
+func FocusByGroup(from, to time.Time, mode string) map[string]int {
+	mu.Lock()
+	defer mu.Unlock()
+
+	tasks := taskMapLocked()
+	res := map[string]int{}
+	for _, s := range data.Sessions {
+		if s.Interrupted || s.EndedAt.IsZero() {
+			continue
+		}
+		sec := sessionOverlapSeconds(s, from, to)
+		if sec == 0 {
+			continue
+		}
+		for _, g := range sessionGroupsLocked(s, tasks, mode) {
+			res[g] += sec
+		}
+	}
+	return res
+}
+
+// Last24HoursFocusTimeByGroup 返回过去 24 小时按分组方式汇总的专注时长(秒)
+func Last24HoursFocusTimeByGroup(mode string) map[string]int {
+	now := nowFunc()
+	return FocusByGroup(now.Add(-24*time.Hour), now, mode)
+}
@@ -2,11 +2,14 @@ package model
 
 import "time"
 
+// RepeatRule 的取值，另外支持 "weekly:1,3,5"（每周指定几天，1 为周一、7 为周日）
+// 与 "every:3"（每隔 N 天），解析见 ParseRepeatRule。
 const (
 	RepeatNone    = "none"
 	RepeatDaily   = "daily"
 	RepeatWeekly  = "weekly"
 	RepeatWorkday = "workday"
+	RepeatEvery   = "every"
 )
 
 type Task struct {
@@ -17,8 +20,44 @@ type Task struct {
 	RepeatRule string     `json:"repeat_rule"`
 	CreatedAt  time.Time  `json:"created_at"`
 	UpdatedAt  time.Time  `json:"updated_at"`
-	Label      string     `json:"label"`
+	Label      string     `json:"label"` // 旧版的单一标签，现为主标签，始终等于 Tags[0]
 	DueDate    *time.Time `json:"due_date,omitempty"`
+	// Tags 任务的全部标签，第一个为主标签
+	Tags []string `json:"tags,omitempty"`
+	// ProjectID 所属项目，0 表示不属于任何项目
+	ProjectID int64 `json:"project_id,omitempty"`
+	// EstimatePomodoros 预估需要的番茄数，0 表示未预估
+	EstimatePomodoros int `json:"estimate_pomodoros,omitempty"`
+	// OccurrenceDate 重复任务当前这一期的日期（本地零点），由 RollOverRecurring 维护
+	OccurrenceDate *time.Time `json:"occurrence_date,omitempty"`
+	// RepeatSpawn 为 true 时每到新的一期生成一个新任务并保留旧任务，否则重置原任务的完成状态
+	RepeatSpawn bool `json:"repeat_spawn,omitempty"`
+	// SeriesID 重复任务所属系列（第一期任务的 ID），0 表示任务自身即系列
+	SeriesID int64 `json:"series_id,omitempty"`
+	// ParentID 父任务 ID，0 表示顶层任务
+	ParentID int64 `json:"parent_id,omitempty"`
+	// Priority 优先级，见 Priority* 常量
+	Priority int `json:"priority,omitempty"`
+	// SortOrder 手动排序的位置，越小越靠前
+	SortOrder int64 `json:"sort_order,omitempty"`
+	// RemindBefore 截止前多少分钟发出提醒，可设置多个，按提前量从大到小排列
+	RemindBefore []int `json:"remind_before,omitempty"`
+	// CompletedAt 完成时间，未完成时为 nil
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	// Archived 已完成较久的任务被自动归档，不再显示在任务列表中
+	Archived bool `json:"archived,omitempty"`
+	// UnarchivedAt 手动取消归档的时间，自动归档从此时起重新计算
+	UnarchivedAt *time.Time `json:"unarchived_at,omitempty"`
+	// BlockedBy 前置任务 ID，这些任务全部完成前本任务处于"被阻塞"状态
+	BlockedBy []int64 `json:"blocked_by,omitempty"`
+}
+
+// Series 返回任务所属重复系列的 ID
+func (t Task) Series() int64 {
+	if t.SeriesID != 0 {
+		return t.SeriesID
+	}
+	return t.ID
 }
 
 func CreateTask(t *Task) error {
@@ -11,7 +11,8 @@ type TimerSession struct {
 	StartedAt     time.Time `json:"started_at"`
 	EndedAt       time.Time `json:"ended_at"` // 零值表示未结束
 	Interrupted   bool      `json:"interrupted"`
-	DurationSec   int       `json:"duration_sec"` // 方便统计直接累加
+	DurationSec   int       `json:"duration_sec"`    // 方便统计直接累加
+	Bells         []int     `json:"bells,omitempty"` // 每次正念铃声响起时已专注的秒数
 }
 
 // StartSession 新建计时记录并返回 ID
@@ -24,6 +25,11 @@ func EndSession(id int64, interrupted bool) error {
 	return EndTimerSession(id, interrupted)
 }
 
+// RecordBell 记录一次正念铃声，elapsedSec 为响铃时已专注的秒数
+func RecordBell(id int64, elapsedSec int) error {
+	return addSessionBell(id, elapsedSec)
+}
+
 // UpdateSessionTask 更新计时记录关联的任务（taskID 为 nil 表示自由计时）
 func UpdateSessionTask(id int64, taskID *int64) error {
 	return updateSessionTask(id, taskID)
@@ -0,0 +1,394 @@
+// Package quickadd 解析快速添加输入框中的一行文本，
+// 如 "写周报 #工作 @周五 !高 3🍅" 或 "复习英语 #学习 明天 25m"。
+package quickadd
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"tomato_clock/internal/model"
+)
+
+// PomodoroMinutes 把 "25m"、"1h" 这类时长换算为番茄数时每个番茄的分钟数
+const PomodoroMinutes = 25
+
+// Result 解析结果，未出现的字段保持零值
+type Result struct {
+	Title    string
+	Tags     []string
+	Due      *time.Time // 只有日期时为本地零点
+	HasTime  bool       // Due 是否带具体时间
+	Priority int        // 见 model.Priority* 常量
+	Estimate int        // 预估番茄数
+}
+
+// Task 把解析结果转换为待创建的任务
+func (r Result) Task() model.Task {
+	t := model.Task{
+		Title:             r.Title,
+		RepeatRule:        model.RepeatNone,
+		Tags:              r.Tags,
+		DueDate:           r.Due,
+		Priority:          r.Priority,
+		EstimatePomodoros: r.Estimate,
+	}
+	if len(r.Tags) > 0 {
+		t.Label = r.Tags[0]
+	}
+	return t
+}
+
+// Summary 返回用于预览的各字段说明
+func (r Result) Summary() string {
+	var lines []string
+	title := r.Title
+	if title == "" {
+		title = "（空）"
+	}
+	lines = append(lines, "标题: "+title)
+	if len(r.Tags) > 0 {
+		lines = append(lines, "标签: #"+strings.Join(r.Tags, " #"))
+	}
+	if r.Due != nil {
+		layout := "2006-01-02 (Mon)"
+		if r.HasTime {
+			layout = "2006-01-02 15:04 (Mon)"
+		}
+		lines = append(lines, "截止: "+localWeekday(r.Due.Format(layout)))
+	}
+	if r.Priority > model.PriorityNone {
+		lines = append(lines, "优先级: "+model.PriorityNames[r.Priority])
+	}
+	if r.Estimate > 0 {
+		lines = append(lines, fmt.Sprintf("预估: %d 🍅", r.Estimate))
+	}
+	return strings.Join(lines, "\n")
+}
+
+var weekdayNames = strings.NewReplacer(
+	"(Mon)", "周一", "(Tue)", "周二", "(Wed)", "周三", "(Thu)", "周四",
+	"(Fri)", "周五", "(Sat)", "周六", "(Sun)", "周日",
+)
+
+func localWeekday(s string) string {
+	return weekdayNames.Replace(s)
+}
+
+// Parse 解析一行快速添加文本，now 用于计算相对日期。
+// 以空白分隔的词中能识别为标签、日期、时间、优先级或预估的部分被提取出来，其余按原顺序组成标题。
+//
+//   - 标签：#工作
+//   - 日期（可加 @ 前缀）：今天、明天、后天、大后天、周五、星期五、下周一、3天后、9月30日、2025-09-30、
+//     today、tomorrow、next monday、in 3 days
+//   - 容易与标题混淆的日期必须加 @ 前缀：@9/30、@9.30、@fri（否则 "复习 3.5 节"、"Sun 公司" 会被误认为日期）
+//   - 时间：18:00、3点、下午3点、3pm（只有时间时为今天，已过则为明天）
+//   - 优先级：!高 !中 !低、!!!、!3、!high
+//   - 预估：3🍅、3个番茄、25m、90分钟、1.5h（按每个番茄 25 分钟换算）
+func Parse(text string, now time.Time) Result {
+	return parse(text, now, false)
+}
+
+// parse 解析一行文本；dateOnly 为 true 时整段文本都是日期，所有日期写法都不需要 @ 前缀
+func parse(text string, now time.Time, dateOnly bool) Result {
+	var r Result
+	var title []string
+	var date *time.Time
+	hour, minute := -1, 0
+
+	words := strings.Fields(text)
+	for i := 0; i < len(words); i++ {
+		w := words[i]
+		lower := strings.ToLower(w)
+
+		if strings.HasPrefix(w, "#") && len(w) > 1 {
+			r.Tags = model.NormalizeTags(append(r.Tags, w))
+			continue
+		}
+		if p, ok := parsePriority(lower); ok {
+			r.Priority = p
+			continue
+		}
+		if n, ok := parseEstimate(lower); ok {
+			r.Estimate = n
+			continue
+		}
+		token := strings.TrimPrefix(lower, "@")
+		marked := dateOnly || token != lower
+		// 英文的多词日期：next monday、in 3 days
+		if i+1 < len(words) {
+			if d, ok := parseDate(token+" "+strings.ToLower(words[i+1]), now, marked); ok {
+				date = &d
+				i++
+				continue
+			}
+		}
+		if i+2 < len(words) {
+			if d, ok := parseDate(token+" "+strings.ToLower(strings.Join(words[i+1:i+3], " ")), now, marked); ok {
+				date = &d
+				i += 2
+				continue
+			}
+		}
+		if d, ok := parseDate(token, now, marked); ok {
+			date = &d
+			continue
+		}
+		if h, m, ok := parseClock(token); ok {
+			hour, minute = h, m
+			continue
+		}
+		// "明天18:00"、"周五下午3点" 这类日期与时间连写
+		if d, h, m, ok := splitDateClock(token, now, marked); ok {
+			date = &d
+			hour, minute = h, m
+			continue
+		}
+		title = append(title, w)
+	}
+	r.Title = strings.Join(title, " ")
+
+	if hour >= 0 {
+		day := model.StartOfDay(now)
+		if date != nil {
+			day = *date
+		}
+		due := time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, now.Location())
+		if date == nil && !due.After(now) {
+			due = due.AddDate(0, 0, 1)
+		}
+		r.Due = &due
+		r.HasTime = true
+	} else if date != nil {
+		r.Due = date
+	}
+	return r
+}
+
+// parsePriority 识别 !高、!!!、!3、!high 等优先级写法
+func parsePriority(w string) (int, bool) {
+	if !strings.HasPrefix(w, "!") && !strings.HasPrefix(w, "！") {
+		return 0, false
+	}
+	rest := strings.TrimLeft(w, "!！")
+	switch rest {
+	case "":
+		n := strings.Count(w, "!") + strings.Count(w, "！")
+		if n > model.PriorityHigh {
+			n = model.PriorityHigh
+		}
+		return n, true
+	case "高", "high", "h", "3":
+		return model.PriorityHigh, true
+	case "中", "medium", "mid", "m", "2":
+		return model.PriorityMedium, true
+	case "低", "low", "l", "1":
+		return model.PriorityLow, true
+	case "无", "none", "0":
+		return model.PriorityNone, true
+	}
+	return 0, false
+}
+
+var (
+	pomodoroRe = regexp.MustCompile(`^(\d+)(🍅|个番茄|番茄|pomos?|p)$`)
+	durationRe = regexp.MustCompile(`^(\d+(?:\.\d+)?)(m|min|mins|分钟|h|hr|hrs|小时)$`)
+)
+
+// parseEstimate 识别 3🍅、3个番茄、25m、1.5h 等预估写法，返回番茄数
+func parseEstimate(w string) (int, bool) {
+	if m := pomodoroRe.FindStringSubmatch(w); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		return n, n > 0
+	}
+	m := durationRe.FindStringSubmatch(w)
+	if m == nil {
+		return 0, false
+	}
+	v, _ := strconv.ParseFloat(m[1], 64)
+	switch m[2] {
+	case "h", "hr", "hrs", "小时":
+		v *= 60
+	}
+	n := int(math.Ceil(v / PomodoroMinutes))
+	return n, n > 0
+}
+
+var (
+	cnWeekdays = map[string]time.Weekday{
+		"一": time.Monday, "二": time.Tuesday, "三": time.Wednesday, "四": time.Thursday,
+		"五": time.Friday, "六": time.Saturday, "日": time.Sunday, "天": time.Sunday,
+	}
+	enWeekdays = map[string]time.Weekday{
+		"mon": time.Monday, "monday": time.Monday, "tue": time.Tuesday, "tues": time.Tuesday, "tuesday": time.Tuesday,
+		"wed": time.Wednesday, "wednesday": time.Wednesday, "thu": time.Thursday, "thur": time.Thursday,
+		"thurs": time.Thursday, "thursday": time.Thursday, "fri": time.Friday, "friday": time.Friday,
+		"sat": time.Saturday, "saturday": time.Saturday, "sun": time.Sunday, "sunday": time.Sunday,
+	}
+	relativeDays = map[string]int{
+		"今天": 0, "今日": 0, "明天": 1, "明日": 1, "后天": 2, "大后天": 3,
+		"today": 0, "tonight": 0, "tomorrow": 1, "tmr": 1,
+	}
+
+	cnWeekdayRe  = regexp.MustCompile(`^(本|这|下下|下)?(?:周|星期|礼拜)([一二三四五六日天])$`)
+	enWeekdayRe  = regexp.MustCompile(`^(this |next )?([a-z]+)$`)
+	daysLaterRe  = regexp.MustCompile(`^(\d+)(?:天|日)(?:后|以后)$`)
+	inDaysRe     = regexp.MustCompile(`^in (\d+) (?:days?|d)$`)
+	cnMonthDayRe = regexp.MustCompile(`^(?:(\d{4})年)?(\d{1,2})月(\d{1,2})(?:日|号)?$`)
+	isoDateRe    = regexp.MustCompile(`^(\d{4})-(\d{1,2})-(\d{1,2})$`)
+	numDateRe    = regexp.MustCompile(`^(?:(\d{4})[-/.])?(\d{1,2})[-/.](\d{1,2})$`)
+)
+
+// parseDate 识别一个日期，返回本地零点。marked 表示带有 @ 前缀：
+// 没有前缀时只识别不会与标题混淆的写法，9/30、3.5、fri 这类可能是小数、分数或单词的写法需要 @
+func parseDate(w string, now time.Time, marked bool) (time.Time, bool) {
+	today := model.StartOfDay(now)
+	if n, ok := relativeDays[w]; ok {
+		return today.AddDate(0, 0, n), true
+	}
+	if m := daysLaterRe.FindStringSubmatch(w); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		return today.AddDate(0, 0, n), true
+	}
+	if m := inDaysRe.FindStringSubmatch(w); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		return today.AddDate(0, 0, n), true
+	}
+	if m := cnWeekdayRe.FindStringSubmatch(w); m != nil {
+		weeks := map[string]int{"": -1, "本": 0, "这": 0, "下": 1, "下下": 2}[m[1]]
+		return weekdayDate(today, cnWeekdays[m[2]], weeks), true
+	}
+	if m := enWeekdayRe.FindStringSubmatch(w); m != nil && (marked || m[1] != "") {
+		if wd, ok := enWeekdays[m[2]]; ok {
+			weeks := map[string]int{"": -1, "this ": 0, "next ": 1}[m[1]]
+			return weekdayDate(today, wd, weeks), true
+		}
+	}
+	if m := cnMonthDayRe.FindStringSubmatch(w); m != nil {
+		return monthDay(today, m[1], m[2], m[3])
+	}
+	if m := isoDateRe.FindStringSubmatch(w); m != nil {
+		return monthDay(today, m[1], m[2], m[3])
+	}
+	if m := numDateRe.FindStringSubmatch(w); m != nil && marked {
+		return monthDay(today, m[1], m[2], m[3])
+	}
+	return time.Time{}, false
+}
+
+// weekdayDate 计算星期几对应的日期。weeks 为 -1 时取今天起最近的一天（含今天），
+// 否则取本周（周一开始）之后第 weeks 周中的那一天。
+func weekdayDate(today time.Time, wd time.Weekday, weeks int) time.Time {
+	if weeks < 0 {
+		diff := (int(wd) - int(today.Weekday()) + 7) % 7
+		return today.AddDate(0, 0, diff)
+	}
+	isoDay := func(d time.Weekday) int {
+		if d == time.Sunday {
+			return 7
+		}
+		return int(d)
+	}
+	monday := today.AddDate(0, 0, 1-isoDay(today.Weekday()))
+	return monday.AddDate(0, 0, weeks*7+isoDay(wd)-1)
+}
+
+// monthDay 由年月日字符串构造日期；未写年份且日期已过时取明年
+func monthDay(today time.Time, year, month, day string) (time.Time, bool) {
+	m, _ := strconv.Atoi(month)
+	d, _ := strconv.Atoi(day)
+	y := today.Year()
+	if year != "" {
+		y, _ = strconv.Atoi(year)
+	}
+	if m < 1 || m > 12 || d < 1 || d > 31 {
+		return time.Time{}, false
+	}
+	t := time.Date(y, time.Month(m), d, 0, 0, 0, 0, today.Location())
+	if t.Day() != d {
+		return time.Time{}, false // 如 2 月 30 日
+	}
+	if year == "" && t.Before(today) {
+		t = t.AddDate(1, 0, 0)
+	}
+	return t, true
+}
+
+var (
+	clockRe   = regexp.MustCompile(`^(\d{1,2}):(\d{2})$`)
+	cnClockRe = regexp.MustCompile(`^(早上|上午|中午|下午|傍晚|晚上)?(\d{1,2})(?:点|时)(半|(\d{1,2})分?)?$`)
+	enClockRe = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?(am|pm)$`)
+)
+
+// parseClock 识别 18:00、下午3点、3点半、3pm 等时间写法
+func parseClock(w string) (hour, minute int, ok bool) {
+	if m := clockRe.FindStringSubmatch(w); m != nil {
+		hour, _ = strconv.Atoi(m[1])
+		minute, _ = strconv.Atoi(m[2])
+	} else if m := cnClockRe.FindStringSubmatch(w); m != nil {
+		hour, _ = strconv.Atoi(m[2])
+		switch {
+		case m[3] == "半":
+			minute = 30
+		case m[4] != "":
+			minute, _ = strconv.Atoi(m[4])
+		}
+		switch m[1] {
+		case "下午", "傍晚", "晚上":
+			if hour < 12 {
+				hour += 12
+			}
+		case "中午":
+			if hour < 6 {
+				hour += 12
+			}
+		}
+	} else if m := enClockRe.FindStringSubmatch(w); m != nil {
+		hour, _ = strconv.Atoi(m[1])
+		if m[2] != "" {
+			minute, _ = strconv.Atoi(m[2])
+		}
+		if hour == 12 {
+			hour = 0
+		}
+		if m[3] == "pm" {
+			hour += 12
+		}
+	} else {
+		return 0, 0, false
+	}
+	if hour > 23 || minute > 59 {
+		return 0, 0, false
+	}
+	return hour, minute, true
+}
+
+// splitDateClock 识别日期后直接跟时间的写法，如 "明天18:00"、"周五下午3点"
+func splitDateClock(w string, now time.Time, marked bool) (time.Time, int, int, bool) {
+	for i := range w {
+		if i == 0 {
+			continue
+		}
+		d, ok := parseDate(w[:i], now, marked)
+		if !ok {
+			continue
+		}
+		if h, m, ok := parseClock(w[i:]); ok {
+			return d, h, m, true
+		}
+	}
+	return time.Time{}, 0, 0, false
+}
+
+// ParseDate 把整段文本解析为一个日期（可带时间），如 "下周五"、"9月30日 18:00"、"2025-09-30"；
+// 文本中含有日期以外的内容时返回 false。整段都是日期，9/30、fri 这类写法不需要 @ 前缀
+func ParseDate(text string, now time.Time) (time.Time, bool) {
+	r := parse(text, now, true)
+	if r.Due == nil || r.Title != "" || len(r.Tags) > 0 || r.Priority != 0 || r.Estimate != 0 {
+		return time.Time{}, false
+	}
+	return *r.Due, true
+}
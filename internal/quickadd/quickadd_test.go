@@ -0,0 +1,120 @@
+package quickadd
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"tomato_clock/internal/model"
+)
+
+// 2025-09-10 周三 10:00
+var now = time.Date(2025, 9, 10, 10, 0, 0, 0, time.Local)
+
+func day(m time.Month, d int) time.Time {
+	return time.Date(2025, m, d, 0, 0, 0, 0, time.Local)
+}
+
+func TestParseExamples(t *testing.T) {
+	r := Parse("写周报 #工作 @周五 !高 3🍅", now)
+	if r.Title != "写周报" || !reflect.DeepEqual(r.Tags, []string{"工作"}) ||
+		r.Priority != model.PriorityHigh || r.Estimate != 3 || r.Due == nil || !r.Due.Equal(day(9, 12)) || r.HasTime {
+		t.Fatalf("unexpected result: %+v", r)
+	}
+
+	r = Parse("复习英语 #学习 明天 25m", now)
+	if r.Title != "复习英语" || !reflect.DeepEqual(r.Tags, []string{"学习"}) ||
+		r.Estimate != 1 || r.Due == nil || !r.Due.Equal(day(9, 11)) {
+		t.Fatalf("unexpected result: %+v", r)
+	}
+
+	task := r.Task()
+	if task.Label != "学习" || task.EstimatePomodoros != 1 || task.DueDate == nil {
+		t.Fatalf("unexpected task: %+v", task)
+	}
+}
+
+func TestParseDates(t *testing.T) {
+	cases := map[string]time.Time{
+		"今天":          day(9, 10),
+		"后天":          day(9, 12),
+		"3天后":         day(9, 13),
+		"周三":          day(9, 10), // 今天就是周三
+		"周一":          day(9, 15),
+		"本周一":         day(9, 8),
+		"下周五":         day(9, 19),
+		"星期日":         day(9, 14),
+		"9月30日":       day(9, 30),
+		"10月1号":       day(10, 1),
+		"@9/1":        time.Date(2026, 9, 1, 0, 0, 0, 0, time.Local), // 已过，取明年
+		"@9.30":       day(9, 30),
+		"2025-12-31":  day(12, 31),
+		"tomorrow":    day(9, 11),
+		"@fri":        day(9, 12),
+		"next monday": day(9, 15),
+		"in 3 days":   day(9, 13),
+	}
+	for text, want := range cases {
+		r := Parse("任务 "+text, now)
+		if r.Due == nil || !r.Due.Equal(want) || r.Title != "任务" {
+			t.Errorf("%q: due=%v title=%q, want %s", text, r.Due, r.Title, want.Format("2006-01-02"))
+		}
+	}
+}
+
+func TestParseKeepsAmbiguousWords(t *testing.T) {
+	// 小数、分数、版本号与像星期的单词没有 @ 前缀时留在标题中
+	for _, text := range []string{"复习 3.5 节", "升级到 1.22", "2/3 进度", "Sun 公司", "Wed 设计评审", "读 2025.9.30 版说明"} {
+		if r := Parse(text, now); r.Due != nil || r.Title != text {
+			t.Errorf("%q: due=%v title=%q", text, r.Due, r.Title)
+		}
+	}
+	// 加上 @ 后按日期识别
+	if r := Parse("发布 @sun", now); r.Due == nil || !r.Due.Equal(day(9, 14)) || r.Title != "发布" {
+		t.Fatalf("@sun: %+v", r)
+	}
+}
+
+func TestParseTimesAndEstimates(t *testing.T) {
+	r := Parse("开会 明天下午3点", now)
+	if want := day(9, 11).Add(15 * time.Hour); r.Due == nil || !r.Due.Equal(want) || !r.HasTime {
+		t.Fatalf("date+time: %+v", r)
+	}
+	// 只有时间且已经过去时为明天
+	r = Parse("跑步 8:30", now)
+	if want := day(9, 11).Add(8*time.Hour + 30*time.Minute); r.Due == nil || !r.Due.Equal(want) {
+		t.Fatalf("past time: %+v", r)
+	}
+	r = Parse("写代码 周五 6pm", now)
+	if want := day(9, 12).Add(18 * time.Hour); r.Due == nil || !r.Due.Equal(want) {
+		t.Fatalf("weekday + pm: %+v", r)
+	}
+
+	for text, want := range map[string]int{"2个番茄": 2, "90分钟": 4, "1.5h": 4, "50min": 2} {
+		if r := Parse("x "+text, now); r.Estimate != want {
+			t.Errorf("%q: estimate=%d, want %d", text, r.Estimate, want)
+		}
+	}
+	for text, want := range map[string]int{"!!": model.PriorityMedium, "!low": model.PriorityLow, "！中": model.PriorityMedium} {
+		if r := Parse("x "+text, now); r.Priority != want {
+			t.Errorf("%q: priority=%d, want %d", text, r.Priority, want)
+		}
+	}
+	// 无法识别的词保留在标题中
+	if r := Parse("读 第3章 #书 #书", now); r.Title != "读 第3章" || len(r.Tags) != 1 {
+		t.Fatalf("title: %+v", r)
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	if d, ok := ParseDate("下周五 18:00", now); !ok || !d.Equal(day(9, 19).Add(18*time.Hour)) {
+		t.Fatalf("ParseDate = %v, %v", d, ok)
+	}
+	// 整段都是日期时不需要 @ 前缀
+	if d, ok := ParseDate("9/30", now); !ok || !d.Equal(day(9, 30)) {
+		t.Fatalf("ParseDate(9/30) = %v, %v", d, ok)
+	}
+	if _, ok := ParseDate("明天 开会", now); ok {
+		t.Fatal("expected failure for text with title")
+	}
+}
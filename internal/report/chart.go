@@ -0,0 +1,142 @@
+package report
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image/color"
+	"image/png"
+
+	"github.com/fogleman/gg"
+)
+
+// 图表尺寸（像素）
+const (
+	chartWidth  = 720
+	chartHeight = 260
+	chartMargin = 36
+)
+
+// chartColors 与界面饼图一致的莫兰迪色系
+var chartColors = []color.Color{
+	color.NRGBA{R: 218, G: 195, B: 194, A: 255},
+	color.NRGBA{R: 176, G: 190, B: 181, A: 255},
+	color.NRGBA{R: 163, G: 182, B: 191, A: 255},
+	color.NRGBA{R: 202, G: 189, B: 162, A: 255},
+	color.NRGBA{R: 195, G: 177, B: 171, A: 255},
+	color.NRGBA{R: 147, G: 161, B: 152, A: 255},
+	color.NRGBA{R: 132, G: 153, B: 164, A: 255},
+	color.NRGBA{R: 180, G: 166, B: 143, A: 255},
+}
+
+// chartColor 根据索引循环取色
+func chartColor(i int) color.Color {
+	return chartColors[i%len(chartColors)]
+}
+
+// colorHex 返回颜色的 #RRGGBB 表示，供 HTML 图例使用
+func colorHex(c color.Color) string {
+	r, g, b, _ := c.RGBA()
+	return fmt.Sprintf("#%02X%02X%02X", r>>8, g>>8, b>>8)
+}
+
+// dataURI 将 gg 绘制结果编码为 PNG data URI，便于嵌入独立 HTML
+func dataURI(dc *gg.Context) (string, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, dc.Image()); err != nil {
+		return "", err
+	}
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// drawDailyChart 绘制每日专注时长柱状图，目标线以虚线表示。
+// 坐标轴文字仅使用 ASCII，避免 gg 默认字体缺少中文字形。
+func drawDailyChart(r *Report) *gg.Context {
+	dc := gg.NewContext(chartWidth, chartHeight)
+	dc.SetRGB(1, 1, 1)
+	dc.Clear()
+
+	maxSec := r.Goal.DailySeconds
+	for _, d := range r.Days {
+		if d.Seconds > maxSec {
+			maxSec = d.Seconds
+		}
+	}
+	if maxSec == 0 || len(r.Days) == 0 {
+		return dc
+	}
+
+	plotW := float64(chartWidth - 2*chartMargin)
+	plotH := float64(chartHeight - 2*chartMargin)
+	slot := plotW / float64(len(r.Days))
+	barW := slot * 0.7
+
+	// 坐标轴
+	dc.SetRGB(0.6, 0.6, 0.6)
+	dc.SetLineWidth(1)
+	dc.DrawLine(chartMargin, chartMargin+plotH, chartMargin+plotW, chartMargin+plotH)
+	dc.Stroke()
+
+	// 每 labelStep 个柱子标注一次日期，避免月报标签重叠
+	labelStep := 1
+	if len(r.Days) > 10 {
+		labelStep = len(r.Days) / 7
+	}
+
+	for i, d := range r.Days {
+		x := chartMargin + slot*float64(i) + (slot-barW)/2
+		h := plotH * float64(d.Seconds) / float64(maxSec)
+		if d.GoalMet {
+			dc.SetColor(chartColor(1))
+		} else {
+			dc.SetColor(chartColor(0))
+		}
+		dc.DrawRectangle(x, chartMargin+plotH-h, barW, h)
+		dc.Fill()
+
+		if i%labelStep == 0 {
+			dc.SetRGB(0.3, 0.3, 0.3)
+			dc.DrawStringAnchored(d.Date.Format("01-02"), x+barW/2, chartMargin+plotH+14, 0.5, 0.5)
+		}
+	}
+
+	if r.Goal.DailySeconds > 0 {
+		y := chartMargin + plotH - plotH*float64(r.Goal.DailySeconds)/float64(maxSec)
+		dc.SetRGB(0.8, 0.4, 0.4)
+		dc.SetDash(6, 4)
+		dc.DrawLine(chartMargin, y, chartMargin+plotW, y)
+		dc.Stroke()
+		dc.SetDash()
+		dc.DrawStringAnchored(fmt.Sprintf("%.1fh", float64(r.Goal.DailySeconds)/3600), chartMargin+plotW, y-8, 1, 0.5)
+	}
+
+	dc.SetRGB(0.3, 0.3, 0.3)
+	dc.DrawStringAnchored(fmt.Sprintf("%.1fh", float64(maxSec)/3600), chartMargin-4, chartMargin, 1, 0.5)
+	return dc
+}
+
+// drawLabelChart 绘制各标签占比的横向堆叠条，颜色与 HTML 图例一一对应
+func drawLabelChart(r *Report) *gg.Context {
+	const h = 48
+	dc := gg.NewContext(chartWidth, h)
+	dc.SetRGB(1, 1, 1)
+	dc.Clear()
+
+	if r.TotalSeconds == 0 {
+		dc.SetRGB(0.9, 0.9, 0.9)
+		dc.DrawRectangle(chartMargin, 12, chartWidth-2*chartMargin, h-24)
+		dc.Fill()
+		return dc
+	}
+
+	x := float64(chartMargin)
+	plotW := float64(chartWidth - 2*chartMargin)
+	for i, row := range r.ByLabel {
+		w := plotW * float64(row.Seconds) / float64(r.TotalSeconds)
+		dc.SetColor(chartColor(i))
+		dc.DrawRectangle(x, 12, w, h-24)
+		dc.Fill()
+		x += w
+	}
+	return dc
+}
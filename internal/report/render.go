@@ -0,0 +1,74 @@
+package report
+
+import (
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"strings"
+	"text/template"
+	"time"
+
+	"tomato_clock/internal/model"
+)
+
+// 支持的输出格式
+const (
+	FormatMarkdown = "md"
+	FormatHTML     = "html"
+)
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+// funcs 两种模板共用的格式化函数
+var funcs = map[string]any{
+	"dur":  model.FormatDuration,
+	"pct":  func(f float64) string { return fmt.Sprintf("%.0f%%", f*100) },
+	"date": func(t time.Time, layout string) string { return t.Format(layout) },
+	"inc":  func(i int) int { return i + 1 },
+	// md 转义表格单元格中的竖线与换行
+	"md": func(s string) string {
+		return strings.NewReplacer("|", `\|`, "\n", " ").Replace(s)
+	},
+	"swatch": func(i int) htmltemplate.CSS { return htmltemplate.CSS(colorHex(chartColor(i))) },
+}
+
+var (
+	markdownTmpl = template.Must(template.New("report.md.tmpl").Funcs(funcs).ParseFS(templateFS, "templates/report.md.tmpl"))
+	htmlTmpl     = htmltemplate.Must(htmltemplate.New("report.html.tmpl").Funcs(funcs).ParseFS(templateFS, "templates/report.html.tmpl"))
+)
+
+// Render 按指定格式将报表写入 w
+func Render(w io.Writer, r *Report, format string) error {
+	switch format {
+	case FormatMarkdown:
+		return RenderMarkdown(w, r)
+	case FormatHTML:
+		return RenderHTML(w, r)
+	default:
+		return fmt.Errorf("unknown report format %q", format)
+	}
+}
+
+// RenderMarkdown 输出 Markdown 报表
+func RenderMarkdown(w io.Writer, r *Report) error {
+	return markdownTmpl.Execute(w, r)
+}
+
+// RenderHTML 输出独立的 HTML 报表，图表以 PNG data URI 内嵌
+func RenderHTML(w io.Writer, r *Report) error {
+	daily, err := dataURI(drawDailyChart(r))
+	if err != nil {
+		return err
+	}
+	labels, err := dataURI(drawLabelChart(r))
+	if err != nil {
+		return err
+	}
+	return htmlTmpl.Execute(w, struct {
+		Report     *Report
+		DailyChart htmltemplate.URL
+		LabelChart htmltemplate.URL
+	}{r, htmltemplate.URL(daily), htmltemplate.URL(labels)})
+}
@@ -0,0 +1,280 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"tomato_clock/internal/model"
+)
+
+// 报表周期名称，用于菜单与命令行参数
+const (
+	PeriodWeek      = "week"
+	PeriodLastWeek  = "lastweek"
+	PeriodMonth     = "month"
+	PeriodLastMonth = "lastmonth"
+)
+
+// topTaskCount 报表中“重点任务”展示的数量
+const topTaskCount = 5
+
+// freeTimerTitle 未关联任务的计时记录在报表中的名称
+const freeTimerTitle = "自由计时"
+
+// Period 表示报表统计的时间区间 [From, To)
+type Period struct {
+	Name  string
+	Title string
+	From  time.Time
+	To    time.Time
+}
+
+// Goal 表示每日专注目标（按标签统计）
+type Goal struct {
+	Label        string
+	DailySeconds int
+}
+
+// DefaultGoal 与主界面一致的默认学习目标：每天 8 小时“学习”
+var DefaultGoal = Goal{Label: "学习", DailySeconds: 8 * 3600}
+
+// Row 表示分组汇总的一行
+type Row struct {
+	Name     string
+	Seconds  int
+	Sessions int
+	Percent  float64 // 占总专注时长的百分比
+	Depth    int     // 任务行的层级，0 为顶层任务；子任务行的 Name 为 "父任务 / 子任务"
+}
+
+// DayRow 表示某一天的汇总
+type DayRow struct {
+	Date        time.Time
+	Seconds     int
+	Sessions    int
+	Interrupted int
+	GoalSeconds int  // 目标标签的专注秒数
+	GoalMet     bool // 是否达成每日目标
+}
+
+// Report 表示一个周期内的专注统计
+type Report struct {
+	Period      Period
+	GeneratedAt time.Time
+	Goal        Goal
+
+	TotalSeconds       int
+	Sessions           int
+	Pomodoros          int
+	Interrupted        int // 被中断的次数
+	InterruptedSeconds int // 被中断记录累计的秒数
+
+	ByLabel   []Row // 按主标签，各行之和等于总时长
+	ByProject []Row
+	ByTag     []Row // 多标签任务的时长计入每个标签，各行之和可能大于总时长
+	ByTask   []Row // 按任务树排列，父任务的时长包含子任务
+	TopTasks []Row // 顶层任务中时长最多的几项
+	Days     []DayRow
+
+	GoalDaysMet    int
+	GoalSeconds    int     // 周期内目标标签的专注总秒数
+	GoalAttainment float64 // 目标完成率（0~1），按周期天数 × 每日目标计算
+}
+
+// NewPeriod 根据名称返回包含 now 的周期（周从周一开始）
+func NewPeriod(name string, now time.Time) (Period, error) {
+	today := model.StartOfDay(now)
+	weekStart := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+	monthStart := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, today.Location())
+
+	var p Period
+	switch name {
+	case PeriodWeek:
+		p = Period{From: weekStart, To: weekStart.AddDate(0, 0, 7)}
+	case PeriodLastWeek:
+		p = Period{From: weekStart.AddDate(0, 0, -7), To: weekStart}
+	case PeriodMonth:
+		p = Period{From: monthStart, To: monthStart.AddDate(0, 1, 0)}
+	case PeriodLastMonth:
+		p = Period{From: monthStart.AddDate(0, -1, 0), To: monthStart}
+	default:
+		return Period{}, fmt.Errorf("unknown report period %q", name)
+	}
+	p.Name = name
+
+	kind := "周报"
+	if name == PeriodMonth || name == PeriodLastMonth {
+		kind = "月报"
+	}
+	p.Title = fmt.Sprintf("%s ~ %s %s", p.From.Format("2006-01-02"), p.To.AddDate(0, 0, -1).Format("2006-01-02"), kind)
+	return p, nil
+}
+
+// Generate 使用当前已加载的数据生成报表
+func Generate(p Period, goal Goal) *Report {
+	return Build(p, model.AllTasks(), model.AllSessions(), goal, time.Now())
+}
+
+// Build 根据给定的任务与计时记录生成报表。
+// 专注时长按记录与周期（及每天）的重叠部分计算，中断的记录只计入中断统计。
+func Build(p Period, tasks []model.Task, sessions []model.TimerSession, goal Goal, now time.Time) *Report {
+	r := &Report{Period: p, GeneratedAt: now, Goal: goal}
+
+	taskByID := make(map[int64]model.Task, len(tasks))
+	for _, t := range tasks {
+		taskByID[t.ID] = t
+	}
+
+	for d := p.From; d.Before(p.To); d = d.AddDate(0, 0, 1) {
+		r.Days = append(r.Days, DayRow{Date: d})
+	}
+
+	labels := map[string]*Row{}
+	projects := map[string]*Row{}
+	tags := map[string]*Row{}
+	byTask := map[string]*Row{}
+	add := func(m map[string]*Row, name string, sec int) {
+		row, ok := m[name]
+		if !ok {
+			row = &Row{Name: name}
+			m[name] = row
+		}
+		row.Seconds += sec
+		row.Sessions++
+	}
+
+	for _, s := range sessions {
+		if s.EndedAt.IsZero() {
+			continue
+		}
+		if s.Interrupted {
+			if !s.StartedAt.Before(p.From) && s.StartedAt.Before(p.To) {
+				r.Interrupted++
+				r.InterruptedSeconds += s.DurationSec
+				if i := dayIndex(r.Days, s.StartedAt); i >= 0 {
+					r.Days[i].Interrupted++
+				}
+			}
+			continue
+		}
+
+		sec := model.SessionOverlap(s, p.From, p.To)
+		if sec == 0 {
+			continue
+		}
+
+		// 任务行从顶层任务到当前任务逐级累加，使父任务包含子任务的时长
+		titles := []string{freeTimerTitle}
+		if s.TaskID != nil {
+			if path := model.TaskPath(taskByID, *s.TaskID); len(path) > 0 {
+				titles = titles[:0]
+				for i := range path {
+					titles = append(titles, model.TaskPathTitle(taskByID, path[i].ID))
+				}
+			}
+		}
+		// 标签与项目的分组与主界面的占比图（model.FocusByGroup）一致
+		label := model.SessionGroups(s, taskByID, model.GroupByLabel)[0]
+
+		r.TotalSeconds += sec
+		r.Sessions++
+		if s.Mode == "countdown" {
+			r.Pomodoros++
+		} else {
+			r.Pomodoros += sec / model.PomodoroSeconds
+		}
+		add(labels, label, sec)
+		for _, name := range model.SessionGroups(s, taskByID, model.GroupByProject) {
+			add(projects, name, sec)
+		}
+		for _, name := range model.SessionGroups(s, taskByID, model.GroupByTag) {
+			add(tags, name, sec)
+		}
+		for depth, title := range titles {
+			add(byTask, title, sec)
+			byTask[title].Depth = depth
+		}
+
+		for i := range r.Days {
+			day := &r.Days[i]
+			daySec := model.SessionOverlap(s, day.Date, day.Date.AddDate(0, 0, 1))
+			if daySec == 0 {
+				continue
+			}
+			day.Seconds += daySec
+			day.Sessions++
+			if label == goal.Label {
+				day.GoalSeconds += daySec
+			}
+		}
+	}
+
+	r.ByLabel = sortedRows(labels, r.TotalSeconds)
+	r.ByProject = sortedRows(projects, r.TotalSeconds)
+	r.ByTag = sortedRows(tags, r.TotalSeconds)
+	r.ByTask = treeOrder(sortedRows(byTask, r.TotalSeconds))
+	for _, row := range r.ByTask {
+		if row.Depth == 0 && len(r.TopTasks) < topTaskCount {
+			r.TopTasks = append(r.TopTasks, row)
+		}
+	}
+
+	for i := range r.Days {
+		day := &r.Days[i]
+		r.GoalSeconds += day.GoalSeconds
+		if goal.DailySeconds > 0 && day.GoalSeconds >= goal.DailySeconds {
+			day.GoalMet = true
+			r.GoalDaysMet++
+		}
+	}
+	if target := goal.DailySeconds * len(r.Days); target > 0 {
+		r.GoalAttainment = float64(r.GoalSeconds) / float64(target)
+	}
+	return r
+}
+
+// dayIndex 返回 t 所在日期在 days 中的下标，不存在时返回 -1
+func dayIndex(days []DayRow, t time.Time) int {
+	for i, d := range days {
+		if !t.Before(d.Date) && t.Before(d.Date.AddDate(0, 0, 1)) {
+			return i
+		}
+	}
+	return -1
+}
+
+// treeOrder 将已按时长排序的任务行重排为树形：每个任务后紧跟它的子任务
+func treeOrder(rows []Row) []Row {
+	res := make([]Row, 0, len(rows))
+	var walk func(prefix string, depth int)
+	walk = func(prefix string, depth int) {
+		for _, row := range rows {
+			if row.Depth == depth && strings.HasPrefix(row.Name, prefix) {
+				res = append(res, row)
+				walk(row.Name+" / ", depth+1)
+			}
+		}
+	}
+	walk("", 0)
+	return res
+}
+
+// sortedRows 将汇总结果按时长降序排列并计算占比
+func sortedRows(m map[string]*Row, total int) []Row {
+	rows := make([]Row, 0, len(m))
+	for _, row := range m {
+		if total > 0 {
+			row.Percent = float64(row.Seconds) / float64(total) * 100
+		}
+		rows = append(rows, *row)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Seconds != rows[j].Seconds {
+			return rows[i].Seconds > rows[j].Seconds
+		}
+		return rows[i].Name < rows[j].Name
+	})
+	return rows
+}
@@ -0,0 +1,130 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"tomato_clock/internal/model"
+)
+
+func TestBuildAndRender(t *testing.T) {
+	now := time.Date(2025, 7, 9, 20, 0, 0, 0, time.UTC) // 周三
+	p, err := NewPeriod(PeriodWeek, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !p.From.Equal(time.Date(2025, 7, 7, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("week should start on Monday, got %v", p.From)
+	}
+
+	id := int64(1)
+	tasks := []model.Task{{ID: id, Title: "读书|笔记", Label: "学习"}}
+	at := func(day, hour int) time.Time { return time.Date(2025, 7, day, hour, 0, 0, 0, time.UTC) }
+	sessions := []model.TimerSession{
+		{ID: 1, TaskID: &id, Mode: "countup", StartedAt: at(7, 8), EndedAt: at(7, 11), DurationSec: 3 * 3600},
+		// 跨越周一午夜的记录拆分到两天
+		{ID: 2, TaskID: &id, Mode: "countdown", StartedAt: at(7, 23), EndedAt: at(8, 1), DurationSec: 2 * 3600},
+		{ID: 3, Mode: "countup", StartedAt: at(8, 9), EndedAt: at(8, 10), DurationSec: 3600},
+		{ID: 4, TaskID: &id, Mode: "countdown", StartedAt: at(9, 9), EndedAt: at(9, 10), Interrupted: true, DurationSec: 600},
+		// 周期外的记录不计入
+		{ID: 5, TaskID: &id, Mode: "countup", StartedAt: at(1, 9), EndedAt: at(1, 10), DurationSec: 3600},
+	}
+
+	r := Build(p, tasks, sessions, Goal{Label: "学习", DailySeconds: 4 * 3600}, now)
+	if r.TotalSeconds != 6*3600 || r.Sessions != 3 || r.Interrupted != 1 {
+		t.Fatalf("unexpected totals: %d s, %d sessions, %d interrupted", r.TotalSeconds, r.Sessions, r.Interrupted)
+	}
+	if r.Days[0].Seconds != 4*3600 || !r.Days[0].GoalMet || r.Days[1].GoalSeconds != 3600 {
+		t.Fatalf("unexpected day rows: %+v %+v", r.Days[0], r.Days[1])
+	}
+	if r.GoalDaysMet != 1 || len(r.ByLabel) != 2 || r.TopTasks[0].Name != "读书|笔记" {
+		t.Fatalf("unexpected breakdown: %+v", r)
+	}
+
+	var md bytes.Buffer
+	if err := RenderMarkdown(&md, r); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(md.String(), `读书\|笔记`) || !strings.Contains(md.String(), "6小时0分钟") {
+		t.Fatalf("markdown missing content:\n%s", md.String())
+	}
+
+	var html bytes.Buffer
+	if err := RenderHTML(&html, r); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(html.String(), `src="data:image/png;base64,`) {
+		t.Fatalf("html should embed chart images")
+	}
+}
+
+func TestTaskRollUp(t *testing.T) {
+	now := time.Date(2025, 7, 9, 20, 0, 0, 0, time.UTC)
+	p, _ := NewPeriod(PeriodWeek, now)
+	parent, child := int64(1), int64(2)
+	tasks := []model.Task{
+		{ID: parent, Title: "论文", Label: "学习"},
+		{ID: child, Title: "第一章", Label: "学习", ParentID: parent},
+		{ID: 3, Title: "跑步", Label: "运动"},
+	}
+	at := func(hour int) time.Time { return time.Date(2025, 7, 8, hour, 0, 0, 0, time.UTC) }
+	other := int64(3)
+	sessions := []model.TimerSession{
+		{ID: 1, TaskID: &parent, Mode: "countup", StartedAt: at(8), EndedAt: at(9), DurationSec: 3600},
+		{ID: 2, TaskID: &child, Mode: "countup", StartedAt: at(9), EndedAt: at(11), DurationSec: 7200},
+		{ID: 3, TaskID: &other, Mode: "countup", StartedAt: at(12), EndedAt: at(14), DurationSec: 7200},
+	}
+
+	r := Build(p, tasks, sessions, DefaultGoal, now)
+	var names []string
+	for _, row := range r.ByTask {
+		names = append(names, row.Name)
+	}
+	if strings.Join(names, ",") != "论文,论文 / 第一章,跑步" {
+		t.Fatalf("task rows should be in tree order, got %v", names)
+	}
+	if r.ByTask[0].Seconds != 3*3600 || r.ByTask[1].Depth != 1 || len(r.TopTasks) != 2 {
+		t.Fatalf("parent should include subtask time: %+v", r.ByTask)
+	}
+}
+
+func TestGroupByProjectAndTag(t *testing.T) {
+	now := time.Date(2025, 7, 9, 20, 0, 0, 0, time.UTC)
+	p, _ := NewPeriod(PeriodWeek, now)
+	both, one := int64(1), int64(2)
+	tasks := []model.Task{
+		{ID: both, Title: "论文", Label: "学习", Tags: []string{"学习", "写作"}},
+		{ID: one, Title: "跑步", Label: "运动", Tags: []string{"运动"}},
+	}
+	at := func(hour int) time.Time { return time.Date(2025, 7, 8, hour, 0, 0, 0, time.UTC) }
+	sessions := []model.TimerSession{
+		{ID: 1, TaskID: &both, Mode: "countup", StartedAt: at(8), EndedAt: at(10), DurationSec: 7200},
+		{ID: 2, TaskID: &one, Mode: "countup", StartedAt: at(12), EndedAt: at(13), DurationSec: 3600},
+	}
+
+	r := Build(p, tasks, sessions, DefaultGoal, now)
+	if len(r.ByLabel) != 2 || r.ByLabel[0].Name != "学习" {
+		t.Fatalf("primary tag rows: %+v", r.ByLabel)
+	}
+	byTag := map[string]int{}
+	for _, row := range r.ByTag {
+		byTag[row.Name] = row.Seconds
+	}
+	// 多标签任务的时长计入每个标签
+	if len(byTag) != 3 || byTag["学习"] != 7200 || byTag["写作"] != 7200 || byTag["运动"] != 3600 {
+		t.Fatalf("tag rows: %+v", r.ByTag)
+	}
+	if len(r.ByProject) != 1 || r.ByProject[0].Name != model.NoProject || r.ByProject[0].Seconds != 3*3600 {
+		t.Fatalf("project rows: %+v", r.ByProject)
+	}
+
+	var md bytes.Buffer
+	if err := RenderMarkdown(&md, r); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(md.String(), "## 按项目") || !strings.Contains(md.String(), "| 写作 |") {
+		t.Fatalf("markdown missing project/tag tables:\n%s", md.String())
+	}
+}
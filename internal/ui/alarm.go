@@ -0,0 +1,208 @@
+package ui
+
+import (
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"tomato_clock/internal/audio"
+	"tomato_clock/internal/config"
+	"tomato_clock/internal/logic"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// alarm 倒计时结束时正在响的闹铃，主窗口与悬浮窗共用
+var alarm *audio.Alarm
+
+// alarmProfile 读取配置中的闹铃方式
+func alarmProfile() audio.AlarmProfile {
+	var c config.Alarm
+	if cfg, err := config.Load(); err == nil {
+		c = cfg.Alarm
+	}
+	return audio.NewAlarmProfile(c)
+}
+
+// ringAlarm 按闹铃方式播放倒计时结束的提示音，静音时不播放；返回本次的闹铃方式（用于贪睡）
+func ringAlarm() audio.AlarmProfile {
+	p := alarmProfile()
+	if muteAlerts {
+		log.Printf("[DEBUG] 倒计时结束，但静音已启用，不播放提示音")
+		return p
+	}
+	if sounds == nil {
+		return p
+	}
+	stopAlarm()
+	a, err := sounds.Alarm(audio.EventCountdownEnd, p)
+	if err != nil {
+		log.Printf("[ERROR] 播放%s提示音失败: %v", audio.EventCountdownEnd.Title(), err)
+		return p
+	}
+	if a == nil {
+		return p
+	}
+	alarm = a
+	go func() {
+		<-a.Done()
+		log.Printf("[AUDIO] 倒计时结束的闹铃已停止")
+	}()
+	return p
+}
+
+// stopAlarm 确认并停止闹铃
+func stopAlarm() {
+	if alarm != nil {
+		alarm.Stop()
+		alarm = nil
+	}
+}
+
+// snooze 倒计时结束后的贪睡，到时再次响铃
+var snooze logic.Snooze
+
+// snoozeAlarm 在 d 之后于主线程调用 ring 再次提醒
+func snoozeAlarm(d time.Duration, ring func()) {
+	snooze.Start(d, func() { runOnMain(ring) })
+}
+
+// cancelSnooze 开始新的计时或休息时取消未到时的贪睡
+func cancelSnooze() {
+	if snooze.Cancel() {
+		log.Printf("[DEBUG] 已取消贪睡")
+	}
+}
+
+// alarmForm 提示音设置中闹铃方式的编辑控件
+type alarmForm struct {
+	preset         *widget.Select
+	fadeIn         *widget.Entry
+	escalateAfter  *widget.Entry
+	escalateVolume *widget.Entry
+	escalateFile   *widget.SelectEntry
+	maxSeconds     *widget.Entry
+	snoozeMinutes  *widget.Entry
+	filling        bool // 正在填入预设，忽略输入框的修改事件
+}
+
+const customAlarmOption = "自定义"
+
+// newAlarmForm 创建闹铃方式的编辑控件，fileOptions 为升级后可选的声音
+func newAlarmForm(c config.Alarm, fileOptions []string) *alarmForm {
+	f := &alarmForm{
+		fadeIn:         widget.NewEntry(),
+		escalateAfter:  widget.NewEntry(),
+		escalateVolume: widget.NewEntry(),
+		escalateFile:   widget.NewSelectEntry(fileOptions),
+		maxSeconds:     widget.NewEntry(),
+		snoozeMinutes:  widget.NewEntry(),
+	}
+	titles := []string{customAlarmOption}
+	for _, p := range audio.AlarmPresets {
+		titles = append(titles, p.Title)
+	}
+	f.preset = widget.NewSelect(titles, func(title string) {
+		if f.filling {
+			return
+		}
+		for _, p := range audio.AlarmPresets {
+			if p.Title == title {
+				f.set(p.Alarm)
+			}
+		}
+	})
+	// 手动修改后显示对应的预设，不是预设时显示为自定义
+	changed := func(string) {
+		if f.filling {
+			return
+		}
+		f.filling = true
+		f.preset.SetSelected(f.presetTitle())
+		f.filling = false
+	}
+	for _, e := range []*widget.Entry{f.fadeIn, f.escalateAfter, f.escalateVolume, f.maxSeconds, f.snoozeMinutes} {
+		e.OnChanged = changed
+	}
+	f.escalateFile.OnChanged = changed
+	f.set(c)
+	return f
+}
+
+// set 把设置填入输入框，0 显示为空
+func (f *alarmForm) set(c config.Alarm) {
+	num := func(n int) string {
+		if n <= 0 {
+			return ""
+		}
+		return strconv.Itoa(n)
+	}
+	f.filling = true
+	defer func() { f.filling = false }()
+	f.fadeIn.SetText(num(c.FadeIn))
+	f.escalateAfter.SetText(num(c.EscalateAfter))
+	f.escalateVolume.SetText(num(c.EscalateVolume))
+	f.escalateFile.SetText("")
+	if c.EscalateFile != "" {
+		f.escalateFile.SetText(soundFileText(c.EscalateFile))
+	}
+	f.maxSeconds.SetText(num(c.MaxSeconds))
+	f.snoozeMinutes.SetText(num(c.SnoozeMinutes))
+	f.preset.SetSelected(f.presetTitle())
+}
+
+// alarm 返回当前编辑的设置，无法解析的数字按 0 处理
+func (f *alarmForm) alarm() config.Alarm {
+	num := func(e *widget.Entry) int {
+		n, _ := strconv.Atoi(strings.TrimSpace(e.Text))
+		return max(n, 0)
+	}
+	return config.Alarm{
+		FadeIn:         num(f.fadeIn),
+		EscalateAfter:  num(f.escalateAfter),
+		EscalateVolume: min(num(f.escalateVolume), 100),
+		EscalateFile:   parseSoundFile(f.escalateFile.Text),
+		MaxSeconds:     num(f.maxSeconds),
+		SnoozeMinutes:  num(f.snoozeMinutes),
+	}
+}
+
+// presetTitle 返回与当前设置相同的预设名称，没有时为“自定义”
+func (f *alarmForm) presetTitle() string {
+	c := f.alarm()
+	for _, p := range audio.AlarmPresets {
+		if p.Alarm == c {
+			return p.Title
+		}
+	}
+	return customAlarmOption
+}
+
+// content 返回设置对话框中的闹铃方式区域
+func (f *alarmForm) content() fyne.CanvasObject {
+	title := widget.NewLabel("倒计时结束的闹铃方式")
+	title.TextStyle = fyne.TextStyle{Bold: true}
+	for _, e := range []*widget.Entry{f.fadeIn, f.escalateAfter, f.escalateVolume, f.maxSeconds, f.snoozeMinutes} {
+		e.SetPlaceHolder("0")
+	}
+	f.escalateFile.SetPlaceHolder("不换声音")
+	hint := widget.NewLabel("留空或 0 表示不启用该项。无人理会时到时升级：加大音量或换成另一个声音；最长响铃时间到后自动停止；设置贪睡后完成提示框中可以稍后再提醒。")
+	hint.Wrapping = fyne.TextWrapWord
+	hint.Importance = widget.LowImportance
+	return container.NewVBox(
+		title,
+		container.NewHBox(widget.NewLabel("预设"), f.preset),
+		container.NewGridWithColumns(4,
+			widget.NewLabel("淡入(秒)"), f.fadeIn,
+			widget.NewLabel("最长响铃(秒)"), f.maxSeconds,
+			widget.NewLabel("升级等待(秒)"), f.escalateAfter,
+			widget.NewLabel("升级音量(%)"), f.escalateVolume,
+			widget.NewLabel("贪睡(分钟)"), f.snoozeMinutes,
+		),
+		container.NewBorder(nil, nil, widget.NewLabel("升级后的声音"), nil, f.escalateFile),
+		hint,
+	)
+}
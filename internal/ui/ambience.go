@@ -0,0 +1,180 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"time"
+
+	"tomato_clock/internal/audio"
+	"tomato_clock/internal/config"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+)
+
+// 背景音在专注阶段自动播放：开始专注时淡入，暂停时暂停，结束时淡出
+var (
+	ambience        *audio.Ambience // 当前设置的背景音，关闭时为 nil
+	ambienceSetting config.Ambience
+	ambienceFocus   bool // 是否处于专注阶段
+)
+
+const (
+	ambienceOff    = "关闭"
+	ambiencePickUp = "选择文件…"
+)
+
+// loadAmbience 读取背景音设置
+func loadAmbience() {
+	ambienceSetting = config.Ambience{Volume: 50}
+	if cfg, err := config.Load(); err == nil && (cfg.Ambience.Source != "" || cfg.Ambience.Volume > 0) {
+		ambienceSetting = cfg.Ambience
+	}
+	if ambienceSetting.Source != "" {
+		ambience = audio.NewAmbience(ambienceSetting.Source, ambienceSetting.Volume)
+	}
+}
+
+// setAmbience 修改并保存背景音设置，专注中立即切换
+func setAmbience(s config.Ambience) error {
+	changed := s.Source != ambienceSetting.Source
+	ambienceSetting = s
+	if err := config.Update(func(cfg *config.Config) { cfg.Ambience = s }); err != nil {
+		log.Printf("[ERROR] 保存背景音设置失败: %v", err)
+	}
+	if !changed {
+		if ambience != nil {
+			ambience.SetVolume(s.Volume)
+		}
+		return nil
+	}
+	if ambience != nil {
+		ambience.FadeOut(300 * time.Millisecond)
+		ambience = nil
+	}
+	if s.Source == "" {
+		return nil
+	}
+	ambience = audio.NewAmbience(s.Source, s.Volume)
+	if ambienceFocus {
+		return ambience.Start()
+	}
+	return nil
+}
+
+// startAmbience 开始专注时淡入背景音
+func startAmbience() {
+	ambienceFocus = true
+	if ambience == nil {
+		return
+	}
+	if err := ambience.Start(); err != nil {
+		log.Printf("[ERROR] 播放背景音失败: %v", err)
+	}
+}
+
+// pauseAmbience 随计时器暂停或继续背景音
+func pauseAmbience(paused bool) {
+	if ambience != nil {
+		ambience.SetPaused(paused)
+	}
+}
+
+// stopAmbience 专注结束时淡出背景音
+func stopAmbience() {
+	ambienceFocus = false
+	if ambience != nil {
+		ambience.FadeOut(audio.AmbienceFadeOut)
+	}
+}
+
+// ambienceTitle 背景音设置在下拉框中的显示名称
+func ambienceTitle(source string) string {
+	switch {
+	case source == "":
+		return ambienceOff
+	case audio.IsNoise(source):
+		return audio.NoiseTitle(source[len(audio.NoisePrefix):])
+	}
+	return filepath.Base(source)
+}
+
+// newAmbienceControl 主窗口中的背景音选择与音量
+func newAmbienceControl(w fyne.Window) fyne.CanvasObject {
+	loadAmbience()
+
+	var sel *widget.Select
+	var sources []string
+	// 选项为关闭、三种噪音、当前使用的文件和「选择文件…」
+	setOptions := func() {
+		sources = []string{""}
+		for _, n := range audio.Noises {
+			sources = append(sources, audio.NoisePrefix+n)
+		}
+		if cur := ambienceSetting.Source; cur != "" && !audio.IsNoise(cur) {
+			sources = append(sources, cur)
+		}
+		var titles []string
+		for _, s := range sources {
+			titles = append(titles, ambienceTitle(s))
+		}
+		sel.Options = append(titles, ambiencePickUp)
+		selected := 0
+		for i, s := range sources {
+			if s == ambienceSetting.Source {
+				selected = i
+			}
+		}
+		sel.SetSelectedIndex(selected)
+	}
+	apply := func(source string) {
+		s := ambienceSetting
+		s.Source = source
+		if err := setAmbience(s); err != nil {
+			dialog.ShowError(fmt.Errorf("播放背景音失败: %w", err), w)
+		}
+		setOptions()
+	}
+
+	sel = widget.NewSelect(nil, nil)
+	setOptions()
+	sel.OnChanged = func(title string) {
+		i := sel.SelectedIndex()
+		switch {
+		case title == ambiencePickUp:
+			fd := dialog.NewFileOpen(func(rc fyne.URIReadCloser, err error) {
+				if err != nil || rc == nil {
+					setOptions() // 取消时恢复原来的选择
+					return
+				}
+				rc.Close()
+				apply(rc.URI().Path())
+			}, w)
+			fd.SetFilter(storage.NewExtensionFileFilter(audio.SupportedExtensions))
+			fd.Show()
+		case i >= 0 && i < len(sources) && sources[i] != ambienceSetting.Source:
+			apply(sources[i])
+		}
+	}
+
+	volume := widget.NewSlider(0, 100)
+	volume.Step = 5
+	volume.SetValue(float64(ambienceSetting.Volume))
+	volume.OnChanged = func(v float64) {
+		if ambience != nil {
+			ambience.SetVolume(int(v))
+		}
+	}
+	volume.OnChangeEnded = func(v float64) {
+		s := ambienceSetting
+		s.Volume = int(v)
+		_ = setAmbience(s)
+	}
+
+	return container.NewHBox(widget.NewLabel("背景音:"), sel,
+		container.NewGridWrap(fyne.NewSize(120, volume.MinSize().Height), volume))
+}
@@ -0,0 +1,175 @@
+package ui
+
+import (
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"tomato_clock/internal/audio"
+	"tomato_clock/internal/config"
+	"tomato_clock/internal/logic"
+	"tomato_clock/internal/model"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// bellSetting 正念铃声设置，开关与调度方式都保存在配置文件中
+var bellSetting config.Bell
+
+// loadBell 读取正念铃声设置
+func loadBell() {
+	if cfg, err := config.Load(); err == nil {
+		bellSetting = cfg.Bell
+	}
+}
+
+// setBell 修改并保存正念铃声设置，下一次开始专注时生效
+func setBell(b config.Bell) {
+	bellSetting = b
+	if err := config.Update(func(cfg *config.Config) { cfg.Bell = b }); err != nil {
+		log.Printf("[ERROR] 保存正念铃声设置失败: %v", err)
+	}
+}
+
+// startBells 为本次专注启动正念铃声调度，每次响铃记录到专注记录中；未启用时返回 nil。
+// 关闭返回的通道即停止调度
+func startBells(t *logic.Timer, sessionID int64) chan struct{} {
+	if !bellSetting.Enabled {
+		return nil
+	}
+	stop := make(chan struct{})
+	p := logic.NewBellPlanner(logic.NewBellSchedule(bellSetting), time.Now().UnixNano())
+	go logic.RunBells(t, p, stop, func(n, elapsedSec int) {
+		if err := model.RecordBell(sessionID, elapsedSec); err != nil {
+			log.Printf("[ERROR] 记录正念铃声失败: %v", err)
+		}
+		runOnMain(func() { playSound(audio.EventRandomHint) })
+	})
+	return stop
+}
+
+// bellsText 把专注记录中的铃声时刻显示为“第 5、13 分钟”
+func bellsText(bells []int) string {
+	parts := make([]string, len(bells))
+	for i, sec := range bells {
+		parts[i] = strconv.Itoa(sec / 60)
+	}
+	return "第 " + strings.Join(parts, "、") + " 分钟"
+}
+
+// 间隔分布选项（显示名 -> 分布）
+var bellDistributionOptions = []struct {
+	title string
+	name  string
+}{
+	{"固定间隔", logic.BellFixed},
+	{"均匀随机", logic.BellUniform},
+	{"泊松随机", logic.BellPoisson},
+}
+
+// bellForm 提示音设置中正念铃声调度的编辑控件
+type bellForm struct {
+	distribution *widget.Select
+	interval     *widget.Entry
+	min, max     *widget.Entry
+	quietStart   *widget.Entry
+	quietEnd     *widget.Entry
+	maxCount     *widget.Entry
+}
+
+// newBellForm 创建正念铃声调度的编辑控件，未设置过时填入默认值
+func newBellForm(c config.Bell) *bellForm {
+	s := logic.NewBellSchedule(c)
+	f := &bellForm{
+		interval:   widget.NewEntry(),
+		min:        widget.NewEntry(),
+		max:        widget.NewEntry(),
+		quietStart: widget.NewEntry(),
+		quietEnd:   widget.NewEntry(),
+		maxCount:   widget.NewEntry(),
+	}
+	var titles []string
+	for _, o := range bellDistributionOptions {
+		titles = append(titles, o.title)
+	}
+	f.distribution = widget.NewSelect(titles, func(title string) {
+		if f.name() == logic.BellUniform {
+			f.interval.Disable()
+			f.min.Enable()
+			f.max.Enable()
+		} else {
+			f.interval.Enable()
+			f.min.Disable()
+			f.max.Disable()
+		}
+	})
+	minutes := func(d time.Duration) string { return strconv.Itoa(int(d / time.Minute)) }
+	f.interval.SetText(minutes(s.Interval))
+	f.min.SetText(minutes(s.Min))
+	f.max.SetText(minutes(s.Max))
+	f.quietStart.SetText(minutes(s.QuietStart))
+	f.quietEnd.SetText(minutes(s.QuietEnd))
+	f.maxCount.SetText(strconv.Itoa(s.MaxCount))
+	for _, o := range bellDistributionOptions {
+		if o.name == s.Distribution {
+			f.distribution.SetSelected(o.title)
+		}
+	}
+	if f.distribution.Selected == "" {
+		f.distribution.SetSelected(bellDistributionOptions[1].title)
+	}
+	return f
+}
+
+// name 返回所选的分布
+func (f *bellForm) name() string {
+	for _, o := range bellDistributionOptions {
+		if o.title == f.distribution.Selected {
+			return o.name
+		}
+	}
+	return logic.BellUniform
+}
+
+// bell 返回当前编辑的设置，enabled 为正念铃声开关的当前状态
+func (f *bellForm) bell(enabled bool) config.Bell {
+	num := func(e *widget.Entry) int {
+		n, _ := strconv.Atoi(strings.TrimSpace(e.Text))
+		return max(n, 0)
+	}
+	return config.Bell{
+		Enabled:      enabled,
+		Distribution: f.name(),
+		Interval:     num(f.interval),
+		Min:          num(f.min),
+		Max:          num(f.max),
+		QuietStart:   num(f.quietStart),
+		QuietEnd:     num(f.quietEnd),
+		MaxCount:     num(f.maxCount),
+	}
+}
+
+// content 返回设置对话框中的正念铃声区域
+func (f *bellForm) content() fyne.CanvasObject {
+	title := widget.NewLabel("正念铃声")
+	title.TextStyle = fyne.TextStyle{Bold: true}
+	hint := widget.NewLabel("计时栏的「正念铃声」按钮开启后，专注中按下面的间隔播放「随机提示音」，时间按专注时长计算，暂停时不响。泊松随机的平均间隔为「间隔」，两次至少相隔 1 分钟。次数为 0 表示不限。每次响铃都记录在专注记录中。")
+	hint.Wrapping = fyne.TextWrapWord
+	hint.Importance = widget.LowImportance
+	return container.NewVBox(
+		title,
+		container.NewHBox(widget.NewLabel("间隔分布"), f.distribution),
+		container.NewGridWithColumns(4,
+			widget.NewLabel("间隔(分钟)"), f.interval,
+			widget.NewLabel("最多次数"), f.maxCount,
+			widget.NewLabel("最短(分钟)"), f.min,
+			widget.NewLabel("最长(分钟)"), f.max,
+			widget.NewLabel("开头安静(分钟)"), f.quietStart,
+			widget.NewLabel("结尾安静(分钟)"), f.quietEnd,
+		),
+		hint,
+	)
+}
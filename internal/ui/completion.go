@@ -0,0 +1,156 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"tomato_clock/internal/model"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// completionHistoryDays 每日完成记录显示的天数
+const completionHistoryDays = 30
+
+// archiveOptions 自动归档选项（显示名 -> 天数，-1 表示不归档）
+var archiveOptions = []struct {
+	title string
+	days  int
+}{
+	{"完成1天后归档", 1},
+	{"完成3天后归档", 3},
+	{"完成7天后归档", 7},
+	{"完成30天后归档", 30},
+	{"不自动归档", -1},
+}
+
+// archiveAfterDays 返回配置中的归档天数，0 表示使用默认值
+func archiveAfterDays(days int) int {
+	if days == 0 {
+		return model.DefaultArchiveAfterDays
+	}
+	return days
+}
+
+// newArchiveSelect 创建自动归档天数选择框
+func newArchiveSelect(days int, onChanged func(days int)) *widget.Select {
+	var titles []string
+	for _, o := range archiveOptions {
+		titles = append(titles, o.title)
+	}
+	sel := widget.NewSelect(titles, nil)
+	for _, o := range archiveOptions {
+		if o.days == archiveAfterDays(days) {
+			sel.SetSelected(o.title)
+		}
+	}
+	sel.OnChanged = func(s string) {
+		for _, o := range archiveOptions {
+			if o.title == s {
+				onChanged(o.days)
+			}
+		}
+	}
+	return sel
+}
+
+// archiveCompleted 按设置归档完成较久的任务，返回是否有任务被归档
+func archiveCompleted(days int) bool {
+	n, err := model.ArchiveCompleted(time.Now(), archiveAfterDays(days))
+	if err != nil {
+		log.Printf("[ERROR] 归档已完成任务失败: %v", err)
+	}
+	return n > 0
+}
+
+// completedOnText 返回某一天完成任务的简短说明，用于历史记录筛选栏
+func completedOnText(day time.Time) string {
+	hist := model.CompletionHistory(day, 1)
+	if len(hist) == 0 || len(hist[0].Tasks) == 0 {
+		return ""
+	}
+	var titles []string
+	for _, t := range hist[0].Tasks {
+		titles = append(titles, t.Title)
+	}
+	return fmt.Sprintf("完成 %d 个任务：%s", len(titles), strings.Join(titles, "、"))
+}
+
+// showCompletionHistory 按天显示最近完成的任务与当天的专注时长
+func showCompletionHistory(w fyne.Window) {
+	weekdays := []string{"周日", "周一", "周二", "周三", "周四", "周五", "周六"}
+	all, _ := model.ListTasks()
+	byID := make(map[int64]model.Task, len(all))
+	for _, t := range all {
+		byID[t.ID] = t
+	}
+
+	rows := container.NewVBox()
+	for _, d := range model.CompletionHistory(time.Now(), completionHistoryDays) {
+		if d.FocusSeconds == 0 && len(d.Tasks) == 0 {
+			continue
+		}
+		header := widget.NewLabel(fmt.Sprintf("%s %s    专注 %s    完成 %d 个",
+			d.Date.Format("2006-01-02"), weekdays[d.Date.Weekday()], model.FormatDuration(d.FocusSeconds), len(d.Tasks)))
+		header.TextStyle = fyne.TextStyle{Bold: true}
+		rows.Add(header)
+		for _, t := range d.Tasks {
+			rows.Add(widget.NewLabel(fmt.Sprintf("    ✓ %s  %s", t.CompletedAt.Format("15:04"), model.TaskPathTitle(byID, t.ID))))
+		}
+	}
+	if len(rows.Objects) == 0 {
+		rows.Add(widget.NewLabel(fmt.Sprintf("最近%d天暂无完成记录", completionHistoryDays)))
+	}
+
+	scroll := container.NewVScroll(rows)
+	scroll.SetMinSize(fyne.NewSize(460, 420))
+	dialog.ShowCustom("每日完成记录", "关闭", scroll, w)
+}
+
+// showArchivedTasks 列出已归档的任务，可逐个取消归档；onChange 在任务取消归档后调用
+func showArchivedTasks(w fyne.Window, onChange func()) {
+	rows := container.NewVBox()
+	var fill func()
+	fill = func() {
+		rows.RemoveAll()
+		all, _ := model.ListTasks()
+		byID := make(map[int64]model.Task, len(all))
+		for _, t := range all {
+			byID[t.ID] = t
+		}
+		archived := model.ArchivedTasks()
+		for _, t := range archived {
+			id := t.ID
+			completed := ""
+			if t.CompletedAt != nil {
+				completed = t.CompletedAt.Format("2006-01-02")
+			}
+			label := widget.NewLabel(fmt.Sprintf("✓ %s  %s", completed, model.TaskPathTitle(byID, id)))
+			label.Truncation = fyne.TextTruncateEllipsis
+			btn := widget.NewButton("取消归档", func() {
+				if err := model.UnarchiveTask(id); err != nil {
+					dialog.ShowError(err, w)
+					return
+				}
+				fill()
+				if onChange != nil {
+					onChange()
+				}
+			})
+			rows.Add(container.NewBorder(nil, nil, nil, btn, label))
+		}
+		if len(archived) == 0 {
+			rows.Add(widget.NewLabel("暂无已归档的任务"))
+		}
+	}
+	fill()
+
+	scroll := container.NewVScroll(rows)
+	scroll.SetMinSize(fyne.NewSize(460, 420))
+	dialog.ShowCustom("已归档的任务", "关闭", scroll, w)
+}
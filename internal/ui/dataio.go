@@ -0,0 +1,214 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"tomato_clock/internal/dataio"
+	"tomato_clock/internal/ics"
+	"tomato_clock/internal/model"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+)
+
+// exportFormats 导出格式选项（显示名 -> 格式、扩展名）
+var exportFormats = []struct {
+	title  string
+	format string
+	ext    string
+}{
+	{"计时记录 CSV", dataio.FormatSessionsCSV, ".csv"},
+	{"任务 CSV", dataio.FormatTasksCSV, ".csv"},
+	{"完整数据 JSON", dataio.FormatJSON, ".json"},
+}
+
+// newDataMenu 创建“数据”菜单：导出与导入。onChanged 在导入成功后调用以刷新界面
+func newDataMenu(w fyne.Window, onChanged func()) *fyne.Menu {
+	return fyne.NewMenu("数据",
+		fyne.NewMenuItem("导出…", func() { showExportDialog(w) }),
+		fyne.NewMenuItem("导入…", func() { showImportDialog(w, onChanged) }),
+		fyne.NewMenuItemSeparator(),
+		fyne.NewMenuItem("导出日历 (.ics)…", func() { showICSExportDialog(w) }),
+		fyne.NewMenuItem("日历订阅地址", func() { showICSFeedInfo(w) }),
+	)
+}
+
+// showICSExportDialog 将专注记录、截止日期与倒数日导出为一次性的 .ics 文件
+func showICSExportDialog(w fyne.Window) {
+	todoCheck := widget.NewCheck("截止日期导出为待办 (VTODO)", nil)
+	dialog.ShowForm("导出日历", "下一步", "取消",
+		[]*widget.FormItem{widget.NewFormItem("", todoCheck)},
+		func(confirm bool) {
+			if !confirm {
+				return
+			}
+			save := dialog.NewFileSave(func(wc fyne.URIWriteCloser, err error) {
+				if err != nil {
+					dialog.ShowError(err, w)
+					return
+				}
+				if wc == nil {
+					return // 用户取消
+				}
+				defer wc.Close()
+				opts := ics.Options{DueAsTodo: todoCheck.Checked}
+				if err := ics.Build(wc, model.AllTasks(), model.AllSessions(), model.AllEvents(time.Now()), opts); err != nil {
+					dialog.ShowError(err, w)
+					return
+				}
+				log.Printf("[ICS] 已导出日历: %s", wc.URI().Path())
+			}, w)
+			save.SetFileName(fmt.Sprintf("tomato_%s.ics", time.Now().Format("20060102")))
+			save.Show()
+		}, w)
+}
+
+// showICSFeedInfo 显示自动更新的订阅文件地址，便于在日历应用中添加订阅
+func showICSFeedInfo(w fyne.Window) {
+	path, err := ics.DefaultFeedPath()
+	if err != nil {
+		dialog.ShowError(err, w)
+		return
+	}
+	uri := storage.NewFileURI(path).String()
+	entry := widget.NewEntry()
+	entry.SetText(uri)
+	content := container.NewVBox(
+		widget.NewLabel("订阅文件会在每次数据变更后自动更新，\n可在日历应用中通过以下地址订阅："),
+		entry,
+	)
+	dialog.ShowCustom("日历订阅地址", "关闭", content, w)
+}
+
+// parseDateEntry 解析可选的 YYYY-MM-DD 日期，空字符串返回零值
+func parseDateEntry(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return time.ParseInLocation("2006-01-02", s, time.Local)
+}
+
+// showExportDialog 选择格式与筛选条件后导出到文件
+func showExportDialog(w fyne.Window) {
+	var titles []string
+	for _, f := range exportFormats {
+		titles = append(titles, f.title)
+	}
+	formatSelect := widget.NewSelect(titles, nil)
+	formatSelect.SetSelected(titles[0])
+
+	fromEntry := widget.NewEntry()
+	fromEntry.SetPlaceHolder("YYYY-MM-DD，可选")
+	toEntry := widget.NewEntry()
+	toEntry.SetPlaceHolder("YYYY-MM-DD，可选（含当天）")
+	labelsEntry := widget.NewEntry()
+	labelsEntry.SetPlaceHolder("多个标签用逗号分隔，可选")
+	interruptedCheck := widget.NewCheck("包含被中断的记录", nil)
+
+	dialog.ShowForm("导出数据", "下一步", "取消",
+		[]*widget.FormItem{
+			widget.NewFormItem("格式", formatSelect),
+			widget.NewFormItem("开始日期", fromEntry),
+			widget.NewFormItem("结束日期", toEntry),
+			widget.NewFormItem("标签", labelsEntry),
+			widget.NewFormItem("", interruptedCheck),
+		},
+		func(confirm bool) {
+			if !confirm {
+				return
+			}
+			from, err := parseDateEntry(fromEntry.Text)
+			if err != nil {
+				dialog.ShowError(errors.New("开始日期格式无效，请使用YYYY-MM-DD"), w)
+				return
+			}
+			to, err := parseDateEntry(toEntry.Text)
+			if err != nil {
+				dialog.ShowError(errors.New("结束日期格式无效，请使用YYYY-MM-DD"), w)
+				return
+			}
+			if !to.IsZero() {
+				to = to.AddDate(0, 0, 1) // 结束日期包含当天
+			}
+			filter := dataio.Filter{From: from, To: to, IncludeInterrupted: interruptedCheck.Checked}
+			for _, l := range strings.Split(labelsEntry.Text, ",") {
+				if l = strings.TrimSpace(l); l != "" {
+					filter.Labels = append(filter.Labels, l)
+				}
+			}
+
+			format, ext := exportFormats[0].format, exportFormats[0].ext
+			for _, f := range exportFormats {
+				if f.title == formatSelect.Selected {
+					format, ext = f.format, f.ext
+				}
+			}
+
+			save := dialog.NewFileSave(func(wc fyne.URIWriteCloser, err error) {
+				if err != nil {
+					dialog.ShowError(err, w)
+					return
+				}
+				if wc == nil {
+					return // 用户取消
+				}
+				defer wc.Close()
+				if err := dataio.Export(wc, format, filter); err != nil {
+					dialog.ShowError(err, w)
+					return
+				}
+				log.Printf("[EXPORT] 已导出 %s: %s", format, wc.URI().Path())
+				dialog.ShowInformation("导出成功", fmt.Sprintf("数据已保存到\n%s", wc.URI().Path()), w)
+			}, w)
+			save.SetFileName(fmt.Sprintf("tomato_%s_%s%s", format, time.Now().Format("20060102"), ext))
+			save.Show()
+		}, w)
+}
+
+// showImportDialog 选择文件后先试运行校验，确认后再正式导入
+func showImportDialog(w fyne.Window, onChanged func()) {
+	open := dialog.NewFileOpen(func(rc fyne.URIReadCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		if rc == nil {
+			return // 用户取消
+		}
+		ds, err := dataio.ReadFile(rc, rc.URI().Name())
+		rc.Close()
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("无法解析导入文件: %w", err), w)
+			return
+		}
+
+		preview, err := dataio.Import(ds, dataio.ImportOptions{DryRun: true})
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		dialog.ShowConfirm("确认导入", preview.Summary()+"\n\n确定要导入吗？", func(ok bool) {
+			if !ok {
+				return
+			}
+			res, err := dataio.Import(ds, dataio.ImportOptions{})
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			if onChanged != nil {
+				onChanged()
+			}
+			dialog.ShowInformation("导入完成", res.Summary(), w)
+		}, w)
+	}, w)
+	open.Show()
+}
@@ -0,0 +1,116 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"tomato_clock/internal/model"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// blockedBadge 返回被阻塞任务的说明，如 "⛔ 等待: 设计、评审"，未被阻塞时返回空字符串
+func blockedBadge(blockers []model.Task) string {
+	if len(blockers) == 0 {
+		return ""
+	}
+	var names []string
+	for _, b := range blockers {
+		names = append(names, b.Title)
+	}
+	return "⛔ 等待: " + strings.Join(names, "、")
+}
+
+// blockerPicker 编辑任务的前置任务：已选任务显示为可删除的标签块，下拉框追加新的前置任务
+type blockerPicker struct {
+	ids   []int64
+	byID  map[int64]model.Task
+	names map[string]int64
+	chips *fyne.Container
+	sel   *widget.Select
+
+	content fyne.CanvasObject
+}
+
+// newBlockerPicker 创建前置任务选择器，候选项为除 t 自身以外的全部任务
+func newBlockerPicker(tasks []model.Task, t model.Task) *blockerPicker {
+	p := &blockerPicker{
+		ids:   append([]int64(nil), t.BlockedBy...),
+		byID:  make(map[int64]model.Task, len(tasks)),
+		names: map[string]int64{},
+	}
+	for _, task := range tasks {
+		p.byID[task.ID] = task
+	}
+	var options []string
+	for _, task := range tasks {
+		if task.ID == t.ID || task.Archived {
+			continue
+		}
+		name := fmt.Sprintf("%s (#%d)", model.TaskPathTitle(p.byID, task.ID), task.ID)
+		options = append(options, name)
+		p.names[name] = task.ID
+	}
+	p.chips = container.New(&flowLayout{})
+	p.sel = widget.NewSelect(options, func(s string) {
+		if id, ok := p.names[s]; ok {
+			p.add(id)
+		}
+	})
+	p.sel.PlaceHolder = "添加前置任务"
+	p.content = container.NewVBox(p.chips, p.sel)
+	p.refresh()
+	return p
+}
+
+func (p *blockerPicker) add(id int64) {
+	for _, existing := range p.ids {
+		if existing == id {
+			p.sel.ClearSelected()
+			return
+		}
+	}
+	p.ids = append(p.ids, id)
+	p.sel.ClearSelected()
+	p.refresh()
+}
+
+func (p *blockerPicker) remove(id int64) {
+	var kept []int64
+	for _, existing := range p.ids {
+		if existing != id {
+			kept = append(kept, existing)
+		}
+	}
+	p.ids = kept
+	p.refresh()
+}
+
+// refresh 重建标签块，已完成的前置任务显示为灰色
+func (p *blockerPicker) refresh() {
+	p.chips.RemoveAll()
+	for _, id := range p.ids {
+		id := id
+		title := fmt.Sprintf("#%d", id)
+		done := false
+		if b, ok := p.byID[id]; ok {
+			title, done = b.Title, b.IsDone
+		}
+		chip := widget.NewButtonWithIcon(title, theme.CancelIcon(), func() { p.remove(id) })
+		chip.IconPlacement = widget.ButtonIconTrailingText
+		chip.Importance = widget.MediumImportance
+		if done {
+			chip.Importance = widget.LowImportance
+		}
+		p.chips.Add(chip)
+	}
+	p.chips.Refresh()
+}
+
+// apply 把前置任务写入任务，循环依赖由 model 校验
+func (p *blockerPicker) apply(t *model.Task) {
+	t.BlockedBy = append([]int64(nil), p.ids...)
+}
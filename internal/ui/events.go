@@ -0,0 +1,339 @@
+package ui
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"tomato_clock/internal/model"
+	"tomato_clock/internal/quickadd"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// 倒数日的历法选项
+const (
+	calendarSolar = "公历"
+	calendarLunar = "农历"
+)
+
+// eventStatusText 返回倒数日的状态说明，如 "还有 21 天"、"已经 9 天"、"就是今天（第 10 周年）"
+func eventStatusText(e model.Event, now time.Time) string {
+	st := e.Status(now)
+	var text string
+	switch {
+	case st.Past:
+		text = fmt.Sprintf("已经 %d 天", st.Days)
+	case st.Days == 0:
+		text = "就是今天"
+	default:
+		text = fmt.Sprintf("还有 %d 天", st.Days)
+	}
+	if e.Yearly && st.Years > 0 {
+		text += fmt.Sprintf("（第 %d 周年）", st.Years)
+	}
+	return text
+}
+
+// topEventText 返回置顶（或最近）倒数日的一行说明，没有倒数日时返回空字符串
+func topEventText(now time.Time) string {
+	e, ok := model.TopEvent(now)
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("📌 %s: %s", e.Title, eventStatusText(e, now))
+}
+
+var lunarInputRe = regexp.MustCompile(`^(?:(\d{4})[-/.年])?(\d{1,2})[-/.月](\d{1,2})日?$`)
+
+// parseLunarInput 解析数字形式的农历日期（如 "2000-08-15"、"8-15"），
+// 省略年份时取今年（农历年），返回对应的公历日期
+func parseLunarInput(text string, leap bool, now time.Time) (time.Time, error) {
+	m := lunarInputRe.FindStringSubmatch(strings.TrimSpace(text))
+	if m == nil {
+		return time.Time{}, fmt.Errorf("农历日期格式应为 YYYY-MM-DD 或 MM-DD，如 2000-08-15")
+	}
+	ld := model.LunarDate{Leap: leap}
+	if m[1] != "" {
+		ld.Year, _ = strconv.Atoi(m[1])
+	} else {
+		cur, err := model.SolarToLunar(now)
+		if err != nil {
+			return time.Time{}, err
+		}
+		ld.Year = cur.Year
+	}
+	ld.Month, _ = strconv.Atoi(m[2])
+	ld.Day, _ = strconv.Atoi(m[3])
+	return model.LunarToSolar(ld, time.Local)
+}
+
+// showEventDialog 新建或编辑倒数日，e 为 nil 时新建；保存后调用 onSaved
+func showEventDialog(w fyne.Window, e *model.Event, onSaved func()) {
+	titleEntry := widget.NewEntry()
+	titleEntry.SetPlaceHolder("事件标题")
+	dateEntry := widget.NewEntry()
+	calendarRadio := widget.NewRadioGroup([]string{calendarSolar, calendarLunar}, nil)
+	calendarRadio.Horizontal = true
+	leapCheck := widget.NewCheck("闰月", nil)
+	yearlyCheck := widget.NewCheck("每年重复（生日、纪念日、节日）", nil)
+	pinCheck := widget.NewCheck("置顶", nil)
+
+	calendarRadio.OnChanged = func(s string) {
+		if s == calendarLunar {
+			dateEntry.SetPlaceHolder("农历，如：2000-08-15 或 8-15")
+			leapCheck.Enable()
+		} else {
+			dateEntry.SetPlaceHolder("如：2025-10-01、10月1日、下周五")
+			leapCheck.SetChecked(false)
+			leapCheck.Disable()
+		}
+	}
+	calendarRadio.SetSelected(calendarSolar)
+
+	if e != nil {
+		titleEntry.SetText(e.Title)
+		yearlyCheck.SetChecked(e.Yearly)
+		pinCheck.SetChecked(e.Pinned)
+		if ld, err := model.SolarToLunar(e.Date); e.Lunar && err == nil {
+			calendarRadio.SetSelected(calendarLunar)
+			dateEntry.SetText(fmt.Sprintf("%d-%02d-%02d", ld.Year, ld.Month, ld.Day))
+			leapCheck.SetChecked(ld.Leap)
+		} else {
+			dateEntry.SetText(e.Date.Format("2006-01-02"))
+		}
+	}
+
+	// 实时显示另一种历法的日期，便于核对
+	hint := widget.NewLabel("")
+	updateHint := func() {
+		hint.SetText("")
+		text := strings.TrimSpace(dateEntry.Text)
+		if text == "" {
+			return
+		}
+		if calendarRadio.Selected == calendarLunar {
+			if d, err := parseLunarInput(text, leapCheck.Checked, time.Now()); err == nil {
+				hint.SetText("公历 " + d.Format("2006-01-02"))
+			} else {
+				hint.SetText(err.Error())
+			}
+		} else if d, ok := quickadd.ParseDate(text, time.Now()); ok {
+			if ld, err := model.SolarToLunar(d); err == nil {
+				hint.SetText(fmt.Sprintf("%s  农历%s", d.Format("2006-01-02"), ld))
+			}
+		}
+	}
+	dateEntry.OnChanged = func(string) { updateHint() }
+	leapCheck.OnChanged = func(bool) { updateHint() }
+	prevCalendar := calendarRadio.OnChanged
+	calendarRadio.OnChanged = func(s string) { prevCalendar(s); updateHint() }
+	updateHint()
+
+	title := "新建倒数日"
+	if e != nil {
+		title = "编辑倒数日"
+	}
+	dialog.ShowForm(title, "保存", "取消",
+		[]*widget.FormItem{
+			widget.NewFormItem("标题", titleEntry),
+			widget.NewFormItem("历法", container.NewHBox(calendarRadio, leapCheck)),
+			widget.NewFormItem("日期", dateEntry),
+			widget.NewFormItem("", hint),
+			widget.NewFormItem("", yearlyCheck),
+			widget.NewFormItem("", pinCheck),
+		},
+		func(confirm bool) {
+			if !confirm {
+				return
+			}
+			var ev model.Event
+			if e != nil {
+				ev = *e
+			}
+			ev.Title = titleEntry.Text
+			ev.Lunar = calendarRadio.Selected == calendarLunar
+			ev.Yearly = yearlyCheck.Checked
+			ev.Pinned = pinCheck.Checked
+			if ev.Lunar {
+				d, err := parseLunarInput(dateEntry.Text, leapCheck.Checked, time.Now())
+				if err != nil {
+					dialog.ShowError(err, w)
+					return
+				}
+				ev.Date = d
+			} else {
+				d, ok := quickadd.ParseDate(dateEntry.Text, time.Now())
+				if !ok {
+					dialog.ShowError(fmt.Errorf("无法识别的日期: %s", dateEntry.Text), w)
+					return
+				}
+				ev.Date = d
+			}
+			var err error
+			if e == nil {
+				err = model.AddEvent(&ev)
+			} else {
+				err = model.UpdateEvent(ev)
+			}
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			onSaved()
+		}, w)
+}
+
+// eventsPanel 倒数日面板：上方突出显示置顶事件，下方列出全部事件
+type eventsPanel struct {
+	w         fyne.Window
+	onChanged func()
+
+	topTitle *widget.Label
+	topDays  *canvas.Text
+	topDate  *widget.Label
+	rows     *fyne.Container
+
+	content fyne.CanvasObject
+}
+
+// newEventsPanel 创建倒数日面板，onChanged 在事件变更后调用以刷新界面其它部分
+func newEventsPanel(w fyne.Window, onChanged func()) *eventsPanel {
+	p := &eventsPanel{w: w, onChanged: onChanged}
+	p.topTitle = widget.NewLabel("")
+	p.topTitle.TextStyle = fyne.TextStyle{Bold: true}
+	p.topTitle.Alignment = fyne.TextAlignCenter
+	p.topDays = canvas.NewText("", theme.Color(theme.ColorNamePrimary))
+	p.topDays.TextSize = 28
+	p.topDays.TextStyle = fyne.TextStyle{Bold: true}
+	p.topDays.Alignment = fyne.TextAlignCenter
+	p.topDate = widget.NewLabel("")
+	p.topDate.Alignment = fyne.TextAlignCenter
+	p.rows = container.NewVBox()
+
+	addBtn := widget.NewButtonWithIcon("新建倒数日", theme.ContentAddIcon(), func() {
+		showEventDialog(w, nil, p.changed)
+	})
+	addBtn.Importance = widget.LowImportance
+	top := container.NewVBox(p.topTitle, p.topDays, p.topDate, widget.NewSeparator())
+	p.content = container.NewBorder(top, container.NewHBox(layout.NewSpacer(), addBtn), nil, nil, container.NewVScroll(p.rows))
+	p.refresh()
+	return p
+}
+
+func (p *eventsPanel) changed() {
+	p.refresh()
+	if p.onChanged != nil {
+		p.onChanged()
+	}
+}
+
+// refresh 重新读取倒数日并重建面板内容（天数每天变化，由定时器调用）
+func (p *eventsPanel) refresh() {
+	now := time.Now()
+	if e, ok := model.TopEvent(now); ok {
+		p.topTitle.SetText(e.Title)
+		p.topDays.Text = eventStatusText(e, now)
+		p.topDate.SetText(e.DateText())
+	} else {
+		p.topTitle.SetText("暂无倒数日")
+		p.topDays.Text = ""
+		p.topDate.SetText("点击下方按钮添加生日、纪念日或重要日期")
+	}
+	p.topDays.Refresh()
+
+	p.rows.RemoveAll()
+	for _, e := range model.AllEvents(now) {
+		e := e
+		name := e.Title
+		if e.Pinned {
+			name = "📌 " + name
+		}
+		lbl := widget.NewLabel(fmt.Sprintf("%s  ·  %s", name, e.DateText()))
+		days := widget.NewLabel(eventStatusText(e, now))
+		days.TextStyle = fyne.TextStyle{Bold: true}
+		if e.Status(now).Past {
+			days.Importance = widget.LowImportance
+		}
+
+		pinIcon := theme.RadioButtonIcon()
+		if e.Pinned {
+			pinIcon = theme.RadioButtonCheckedIcon()
+		}
+		pin := widget.NewButtonWithIcon("", pinIcon, func() {
+			id := e.ID
+			if e.Pinned {
+				id = 0
+			}
+			if err := model.PinEvent(id); err != nil {
+				dialog.ShowError(err, p.w)
+				return
+			}
+			p.changed()
+		})
+		edit := widget.NewButtonWithIcon("", theme.DocumentCreateIcon(), func() {
+			showEventDialog(p.w, &e, p.changed)
+		})
+		del := widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
+			dialog.ShowConfirm("确认删除", fmt.Sprintf("删除倒数日 '%s'？", e.Title), func(ok bool) {
+				if !ok {
+					return
+				}
+				if err := model.DeleteEvent(e.ID); err != nil {
+					dialog.ShowError(err, p.w)
+					return
+				}
+				p.changed()
+			}, p.w)
+		})
+		for _, b := range []*widget.Button{pin, edit, del} {
+			b.Importance = widget.LowImportance
+		}
+		p.rows.Add(container.NewHBox(lbl, layout.NewSpacer(), days, pin, edit, del))
+	}
+	p.rows.Refresh()
+}
+
+// offerLegacyCountdowns 升级后询问一次是否把旧版“新建倒数日”创建的任务转换为倒数日，
+// 未勾选的任务保持为普通任务；转换后调用 onConverted
+func offerLegacyCountdowns(w fyne.Window, onConverted func()) {
+	tasks := model.LegacyCountdownTasks()
+	if len(tasks) == 0 {
+		return
+	}
+	checks := make([]*widget.Check, len(tasks))
+	rows := container.NewVBox()
+	for i, t := range tasks {
+		checks[i] = widget.NewCheck(fmt.Sprintf("%s  ·  %s", t.Title, t.DueDate.Format("2006-01-02")), nil)
+		checks[i].SetChecked(true)
+		rows.Add(checks[i])
+	}
+	hint := widget.NewLabel("旧版的倒数日保存为带截止日期的任务。勾选要转换为倒数日的项目，转换后原任务将删除；未勾选的保持为普通任务。此提示只出现一次。")
+	hint.Wrapping = fyne.TextWrapWord
+	scroll := container.NewVScroll(rows)
+	scroll.SetMinSize(fyne.NewSize(420, 240))
+	dialog.ShowCustomConfirm("转换旧版倒数日", "转换", "保持为任务",
+		container.NewBorder(hint, nil, nil, nil, scroll), func(ok bool) {
+			var ids []int64
+			for i, c := range checks {
+				if ok && c.Checked {
+					ids = append(ids, tasks[i].ID)
+				}
+			}
+			if _, err := model.ResolveLegacyCountdowns(ids); err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			if len(ids) > 0 && onConverted != nil {
+				onConverted()
+			}
+		}, w)
+}
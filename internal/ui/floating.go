@@ -28,7 +28,15 @@ func ShowFloating(app fyne.App, timer *logic.Timer, sessionID int64, onEnd func(
 
 	win := app.NewWindow("")
 
-	endBtn := widget.NewButtonWithIcon("结束", theme.MediaStopIcon(), func() {
+	// 倒计时结束后按钮变为停止闹铃并关闭小窗
+	finished := false
+	var endBtn *widget.Button
+	endBtn = widget.NewButtonWithIcon("结束", theme.MediaStopIcon(), func() {
+		if finished {
+			stopAlarm()
+			win.Close()
+			return
+		}
 		// 判断本次计时是否被中断：
 		// 仅在倒计时模式且仍有剩余时间时，才标记为中断。
 		interrupted := false
@@ -64,6 +72,14 @@ func ShowFloating(app fyne.App, timer *logic.Timer, sessionID int64, onEnd func(
 			if tick.Done {
 				_ = model.EndSession(sessionID, false)
 				runOnMain(onEnd)
+				if timer.Mode == logic.ModeCountDown {
+					runOnMain(func() {
+						finished = true
+						ringAlarm()
+						endBtn.SetText("停止提示音")
+						win.SetOnClosed(stopAlarm)
+					})
+				}
 			}
 		}
 	}()
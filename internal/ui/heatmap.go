@@ -0,0 +1,297 @@
+package ui
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"time"
+
+	"tomato_clock/internal/model"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/fogleman/gg"
+)
+
+// 热力图布局参数（Fyne 坐标单位）
+const (
+	heatmapWeeks   = 53 // 展示最近 53 周
+	heatmapLeftPad = 30 // 左侧星期标签宽度
+	heatmapTopPad  = 16 // 顶部月份标签高度
+	heatmapGap     = 2  // 格子间距
+)
+
+// heatmapLevels 各颜色等级的专注秒数下限（不含 0 级）
+var heatmapLevels = []int{1, 3600, 2 * 3600, 4 * 3600}
+
+// heatmapColors 由浅到深的莫兰迪灰绿色，下标与等级对应
+var heatmapColors = []color.Color{
+	color.NRGBA{R: 232, G: 232, B: 228, A: 255},
+	color.NRGBA{R: 206, G: 219, B: 210, A: 255},
+	color.NRGBA{R: 176, G: 190, B: 181, A: 255},
+	color.NRGBA{R: 147, G: 161, B: 152, A: 255},
+	color.NRGBA{R: 104, G: 124, B: 112, A: 255},
+}
+
+var heatmapMonths = []string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"}
+
+// Heatmap 是类似 GitHub 贡献图的年度专注热力图，每个格子代表一天
+type Heatmap struct {
+	widget.BaseWidget
+
+	start  time.Time // 第一列周一的零点
+	label  string    // 为空时统计全部标签
+	values map[time.Time]int
+
+	hoverDay time.Time // 鼠标所在的日期，零值表示不在格子上
+	hoverPos fyne.Position
+
+	// OnDayTapped 点击某一天时回调
+	OnDayTapped func(day time.Time)
+
+	raster      *canvas.Raster
+	tooltipBg   *canvas.Rectangle
+	tooltipText *canvas.Text
+}
+
+// NewHeatmap 创建热力图并加载最近一年的数据
+func NewHeatmap() *Heatmap {
+	h := &Heatmap{}
+	h.ExtendBaseWidget(h)
+	h.raster = canvas.NewRaster(h.draw)
+	h.tooltipBg = canvas.NewRectangle(color.NRGBA{R: 0x33, G: 0x34, B: 0x35, A: 0xE6})
+	h.tooltipBg.CornerRadius = 4
+	h.tooltipText = canvas.NewText("", color.White)
+	h.tooltipText.TextSize = theme.CaptionTextSize()
+	h.Reload()
+	return h
+}
+
+// SetLabel 设置标签筛选（空字符串表示全部）并重新加载数据
+func (h *Heatmap) SetLabel(label string) {
+	h.label = label
+	h.Reload()
+}
+
+// Reload 重新从统计层读取每日专注时长
+func (h *Heatmap) Reload() {
+	today := model.StartOfDay(time.Now())
+	monday := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+	h.start = monday.AddDate(0, 0, -7*(heatmapWeeks-1))
+	h.values = model.DailyFocus(h.start, today.AddDate(0, 0, 1), h.label)
+	h.Refresh()
+}
+
+// cellSize 根据控件尺寸计算格子边长（不含间距）
+func (h *Heatmap) cellSize(size fyne.Size) float32 {
+	cw := (size.Width - heatmapLeftPad) / heatmapWeeks
+	ch := (size.Height - heatmapTopPad) / 7
+	if ch < cw {
+		cw = ch
+	}
+	return cw - heatmapGap
+}
+
+// dayAt 返回坐标所在格子的日期，不在格子上或在未来时返回零值
+func (h *Heatmap) dayAt(pos fyne.Position) time.Time {
+	cell := h.cellSize(h.Size())
+	if cell <= 0 {
+		return time.Time{}
+	}
+	step := cell + heatmapGap
+	col := int((pos.X - heatmapLeftPad) / step)
+	row := int((pos.Y - heatmapTopPad) / step)
+	if pos.X < heatmapLeftPad || pos.Y < heatmapTopPad || col >= heatmapWeeks || row >= 7 {
+		return time.Time{}
+	}
+	day := h.start.AddDate(0, 0, col*7+row)
+	if day.After(time.Now()) {
+		return time.Time{}
+	}
+	return day
+}
+
+// heatmapLevel 返回专注秒数对应的颜色等级
+func heatmapLevel(sec int) int {
+	lv := 0
+	for i, threshold := range heatmapLevels {
+		if sec >= threshold {
+			lv = i + 1
+		}
+	}
+	return lv
+}
+
+// draw 光栅化绘制格子与坐标轴；坐标轴文字仅用 ASCII，避免 gg 默认字体缺字
+func (h *Heatmap) draw(w, hPx int) image.Image {
+	dc := gg.NewContext(w, hPx)
+	dc.SetRGB(1, 1, 1)
+	dc.Clear()
+
+	size := h.Size()
+	if size.Width <= 0 {
+		return dc.Image()
+	}
+	scale := float64(w) / float64(size.Width)
+	cell := float64(h.cellSize(size)) * scale
+	if cell <= 0 {
+		return dc.Image()
+	}
+	step := cell + heatmapGap*scale
+	left := heatmapLeftPad * scale
+	top := heatmapTopPad * scale
+	now := time.Now()
+
+	dc.SetRGB(0.45, 0.45, 0.45)
+	for row, name := range map[int]string{0: "Mon", 2: "Wed", 4: "Fri"} {
+		dc.DrawStringAnchored(name, left-4*scale, top+step*float64(row)+cell/2, 1, 0.5)
+	}
+
+	lastMonth := -1
+	for col := 0; col < heatmapWeeks; col++ {
+		weekStart := h.start.AddDate(0, 0, col*7)
+		if m := int(weekStart.Month()); m != lastMonth && weekStart.Day() <= 7 {
+			dc.SetRGB(0.45, 0.45, 0.45)
+			dc.DrawString(heatmapMonths[m-1], left+step*float64(col), top-4*scale)
+			lastMonth = m
+		}
+		for row := 0; row < 7; row++ {
+			day := weekStart.AddDate(0, 0, row)
+			if day.After(now) {
+				continue
+			}
+			dc.SetColor(heatmapColors[heatmapLevel(h.values[day])])
+			dc.DrawRoundedRectangle(left+step*float64(col), top+step*float64(row), cell, cell, 2*scale)
+			dc.Fill()
+			if day.Equal(h.hoverDay) {
+				dc.SetRGB(0.2, 0.2, 0.2)
+				dc.SetLineWidth(1.5 * scale)
+				dc.DrawRoundedRectangle(left+step*float64(col), top+step*float64(row), cell, cell, 2*scale)
+				dc.Stroke()
+			}
+		}
+	}
+	return dc.Image()
+}
+
+// MouseIn 实现 desktop.Hoverable
+func (h *Heatmap) MouseIn(e *desktop.MouseEvent) { h.MouseMoved(e) }
+
+// MouseMoved 更新悬停的日期与提示框位置
+func (h *Heatmap) MouseMoved(e *desktop.MouseEvent) {
+	day := h.dayAt(e.Position)
+	if day.Equal(h.hoverDay) && day.IsZero() {
+		return
+	}
+	h.hoverDay = day
+	h.hoverPos = e.Position
+	h.Refresh()
+}
+
+// MouseOut 隐藏提示框
+func (h *Heatmap) MouseOut() {
+	h.hoverDay = time.Time{}
+	h.Refresh()
+}
+
+// Tapped 点击格子时回调 OnDayTapped
+func (h *Heatmap) Tapped(e *fyne.PointEvent) {
+	day := h.dayAt(e.Position)
+	if day.IsZero() || h.OnDayTapped == nil {
+		return
+	}
+	h.OnDayTapped(day)
+}
+
+// CreateRenderer 创建渲染器
+func (h *Heatmap) CreateRenderer() fyne.WidgetRenderer {
+	h.raster.SetMinSize(fyne.NewSize(heatmapLeftPad+heatmapWeeks*12, heatmapTopPad+7*12))
+	return &heatmapRenderer{h: h}
+}
+
+type heatmapRenderer struct {
+	h *Heatmap
+}
+
+func (r *heatmapRenderer) Layout(size fyne.Size) {
+	r.h.raster.Resize(size)
+	r.layoutTooltip(size)
+}
+
+// layoutTooltip 将提示框放在鼠标右上方，超出右边界时改放左侧
+func (r *heatmapRenderer) layoutTooltip(size fyne.Size) {
+	h := r.h
+	if h.hoverDay.IsZero() {
+		h.tooltipBg.Hide()
+		h.tooltipText.Hide()
+		return
+	}
+	sec := h.values[h.hoverDay]
+	text := fmt.Sprintf("%s  暂无专注", h.hoverDay.Format("2006-01-02"))
+	if sec > 0 {
+		text = fmt.Sprintf("%s  %s (%d秒)", h.hoverDay.Format("2006-01-02"), model.FormatDuration(sec), sec)
+	}
+	h.tooltipText.Text = text
+	h.tooltipText.Refresh()
+
+	pad := theme.InnerPadding() / 2
+	textSize := h.tooltipText.MinSize()
+	boxSize := fyne.NewSize(textSize.Width+2*pad, textSize.Height+2*pad)
+	pos := fyne.NewPos(h.hoverPos.X+12, h.hoverPos.Y-boxSize.Height-4)
+	if pos.X+boxSize.Width > size.Width {
+		pos.X = h.hoverPos.X - boxSize.Width - 12
+	}
+	if pos.Y < 0 {
+		pos.Y = h.hoverPos.Y + 16
+	}
+	h.tooltipBg.Resize(boxSize)
+	h.tooltipBg.Move(pos)
+	h.tooltipText.Resize(textSize)
+	h.tooltipText.Move(pos.Add(fyne.NewPos(pad, pad)))
+	h.tooltipBg.Show()
+	h.tooltipText.Show()
+}
+
+func (r *heatmapRenderer) MinSize() fyne.Size {
+	return r.h.raster.MinSize()
+}
+
+func (r *heatmapRenderer) Refresh() {
+	r.h.raster.Refresh()
+	r.layoutTooltip(r.h.Size())
+	canvas.Refresh(r.h)
+}
+
+func (r *heatmapRenderer) Objects() []fyne.CanvasObject {
+	return []fyne.CanvasObject{r.h.raster, r.h.tooltipBg, r.h.tooltipText}
+}
+
+func (r *heatmapRenderer) Destroy() {}
+
+// showHeatmapWindow 打开年度热力图窗口，点击某天时调用 onDay
+func showHeatmapWindow(app fyne.App, onDay func(day time.Time)) {
+	win := app.NewWindow("专注热力图")
+	hm := NewHeatmap()
+	hm.OnDayTapped = onDay
+
+	const allLabels = "全部标签"
+	labelSelect := widget.NewSelect(append([]string{allLabels}, model.Labels()...), func(s string) {
+		if s == allLabels {
+			s = ""
+		}
+		hm.SetLabel(s)
+	})
+	labelSelect.SetSelected(allLabels)
+
+	hint := widget.NewLabel("点击某一天可在历史记录中查看当天的专注记录")
+	hint.Importance = widget.LowImportance
+	top := container.NewBorder(nil, nil, nil, labelSelect, hint)
+
+	win.SetContent(container.NewBorder(top, nil, nil, nil, hm))
+	win.Resize(fyne.NewSize(860, 220))
+	win.Show()
+}
@@ -0,0 +1,186 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+
+	"tomato_clock/internal/model"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// noteBadge 返回任务列表中的备注标记：有清单项时为进度如 "☑ 2/5"，其它备注为 "📝"，没有备注时为空字符串
+func noteBadge(note string) string {
+	done, total := model.NoteProgress(note)
+	switch {
+	case total > 0:
+		return fmt.Sprintf("☑ %d/%d", done, total)
+	case strings.TrimSpace(note) != "":
+		return "📝"
+	}
+	return ""
+}
+
+// newNoteEntry 新建/编辑任务对话框中的多行备注输入框
+func newNoteEntry(note string) *widget.Entry {
+	e := widget.NewMultiLineEntry()
+	e.Wrapping = fyne.TextWrapWord
+	e.SetMinRowsVisible(3)
+	e.SetPlaceHolder("可选，支持 Markdown 与 - [ ] 清单")
+	e.SetText(note)
+	return e
+}
+
+// notePane 任务详情面板：显示所选任务的 Markdown 备注，可切换到编辑模式。
+// 预览中的清单项可直接勾选，指向本地文件的链接点击后用系统程序打开。
+type notePane struct {
+	w        fyne.Window
+	onSaved  func()
+	task     *model.Task
+	editing  bool
+	title    *widget.Label
+	editor   *widget.Entry
+	preview  *fyne.Container
+	editBtn  *widget.Button
+	body     *fyne.Container
+	scroller *container.Scroll
+
+	content fyne.CanvasObject
+}
+
+// newNotePane 创建任务详情面板，onSaved 在备注修改后调用以刷新任务列表
+func newNotePane(w fyne.Window, onSaved func()) *notePane {
+	p := &notePane{w: w, onSaved: onSaved}
+	p.title = widget.NewLabel("")
+	p.title.TextStyle = fyne.TextStyle{Bold: true}
+	p.title.Truncation = fyne.TextTruncateEllipsis
+	p.editor = widget.NewMultiLineEntry()
+	p.editor.Wrapping = fyne.TextWrapWord
+	p.editor.SetPlaceHolder("支持 Markdown：# 标题、**加粗**、- [ ] 清单、[文件](~/文档/资料.pdf)")
+	p.preview = container.NewVBox()
+	p.scroller = container.NewVScroll(p.preview)
+	p.body = container.NewStack(p.scroller)
+	p.editBtn = widget.NewButtonWithIcon("编辑", theme.DocumentCreateIcon(), p.toggleEdit)
+	p.editBtn.Importance = widget.LowImportance
+	p.content = container.NewBorder(
+		container.NewBorder(nil, nil, nil, p.editBtn, p.title),
+		nil, nil, nil, p.body)
+	p.show(nil)
+	return p
+}
+
+// show 切换到指定任务，task 为 nil 时显示提示；编辑中的同一任务不会被打断
+func (p *notePane) show(task *model.Task) {
+	if p.editing && task != nil && p.task != nil && task.ID == p.task.ID {
+		return
+	}
+	if p.editing {
+		p.save()
+	}
+	p.task = nil
+	if task != nil {
+		t := *task
+		p.task = &t
+	}
+	p.editing = false
+	p.editBtn.SetText("编辑")
+	p.editBtn.SetIcon(theme.DocumentCreateIcon())
+	if task == nil {
+		p.title.SetText("任务详情")
+		p.editBtn.Disable()
+	} else {
+		p.title.SetText(task.Title)
+		p.editBtn.Enable()
+	}
+	p.body.Objects = []fyne.CanvasObject{p.scroller}
+	p.body.Refresh()
+	p.render()
+}
+
+// toggleEdit 在预览与编辑之间切换，离开编辑时保存
+func (p *notePane) toggleEdit() {
+	if p.task == nil {
+		return
+	}
+	if p.editing {
+		p.save()
+		p.editing = false
+		p.editBtn.SetText("编辑")
+		p.editBtn.SetIcon(theme.DocumentCreateIcon())
+		p.body.Objects = []fyne.CanvasObject{p.scroller}
+		p.render()
+	} else {
+		p.editing = true
+		p.editor.SetText(p.task.Note)
+		p.editBtn.SetText("完成")
+		p.editBtn.SetIcon(theme.ConfirmIcon())
+		p.body.Objects = []fyne.CanvasObject{p.editor}
+	}
+	p.body.Refresh()
+}
+
+// save 保存编辑框中的备注
+func (p *notePane) save() {
+	if p.task == nil || p.editor.Text == p.task.Note {
+		return
+	}
+	if err := model.SetTaskNote(p.task.ID, p.editor.Text); err != nil {
+		dialog.ShowError(err, p.w)
+		return
+	}
+	p.task.Note = p.editor.Text
+	if p.onSaved != nil {
+		p.onSaved()
+	}
+}
+
+// render 把备注渲染为 RichText，清单项渲染为可勾选的复选框
+func (p *notePane) render() {
+	p.preview.RemoveAll()
+	if p.task == nil {
+		p.preview.Add(widget.NewLabel("选中任务后在这里查看和编辑备注"))
+		p.preview.Refresh()
+		return
+	}
+	if strings.TrimSpace(p.task.Note) == "" {
+		hint := widget.NewLabel("暂无备注，点击右上角「编辑」添加")
+		hint.Importance = widget.LowImportance
+		p.preview.Add(hint)
+		p.preview.Refresh()
+		return
+	}
+	taskID := p.task.ID
+	for _, b := range model.SplitNote(p.task.Note) {
+		if !b.Item {
+			if strings.TrimSpace(b.Text) == "" {
+				continue
+			}
+			rt := widget.NewRichTextFromMarkdown(model.LocalNoteLinks(b.Text))
+			rt.Wrapping = fyne.TextWrapWord
+			p.preview.Add(rt)
+			continue
+		}
+		line := b.Line
+		chk := widget.NewCheck(b.Text, func(bool) {
+			if err := model.ToggleTaskNoteItem(taskID, line); err != nil {
+				dialog.ShowError(err, p.w)
+				return
+			}
+			if p.onSaved != nil {
+				p.onSaved()
+			}
+		})
+		chk.Checked = b.Done
+		// 缩进的子清单项向右错开
+		indent := canvas.NewRectangle(color.Transparent)
+		indent.SetMinSize(fyne.NewSize(float32(b.Indent)*theme.Padding()*2, 0))
+		p.preview.Add(container.NewHBox(indent, chk))
+	}
+	p.preview.Refresh()
+}
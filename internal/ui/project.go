@@ -0,0 +1,156 @@
+package ui
+
+import (
+	"fmt"
+
+	"tomato_clock/internal/model"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// newProjectOption 项目选择框中用于新建项目的选项
+const newProjectOption = "新建项目…"
+
+// projectPicker 任务对话框中的项目选择框，可以就地新建项目
+type projectPicker struct {
+	w      fyne.Window
+	sel    *widget.Select
+	ids    map[string]int64
+	lastID int64
+}
+
+// newProjectPicker 创建项目选择框并选中 projectID
+func newProjectPicker(w fyne.Window, projectID int64) *projectPicker {
+	p := &projectPicker{w: w, lastID: projectID}
+	p.sel = widget.NewSelect(nil, p.onSelected)
+	p.reload(projectID)
+	return p
+}
+
+// reload 重新读取项目列表并选中 id
+func (p *projectPicker) reload(id int64) {
+	p.ids = map[string]int64{model.NoProject: 0}
+	options := []string{model.NoProject}
+	selected := model.NoProject
+	for _, proj := range model.AllProjects() {
+		options = append(options, proj.Name)
+		p.ids[proj.Name] = proj.ID
+		if proj.ID == id {
+			selected = proj.Name
+		}
+	}
+	p.sel.Options = append(options, newProjectOption)
+	p.lastID = p.ids[selected]
+	p.sel.SetSelected(selected)
+}
+
+func (p *projectPicker) onSelected(s string) {
+	if s != newProjectOption {
+		p.lastID = p.ids[s]
+		return
+	}
+	entry := widget.NewEntry()
+	entry.SetPlaceHolder("项目名称")
+	dialog.ShowForm("新建项目", "创建", "取消",
+		[]*widget.FormItem{widget.NewFormItem("名称", entry)},
+		func(ok bool) {
+			if !ok {
+				p.reload(p.lastID)
+				return
+			}
+			proj, err := model.AddProject(entry.Text)
+			if err != nil {
+				dialog.ShowError(err, p.w)
+				p.reload(p.lastID)
+				return
+			}
+			p.reload(proj.ID)
+		}, p.w)
+}
+
+// ProjectID 返回当前选中的项目 ID，0 表示无项目
+func (p *projectPicker) ProjectID() int64 {
+	return p.lastID
+}
+
+// newProjectMenu 创建“项目”菜单，onChanged 在项目变更后调用以刷新界面
+func newProjectMenu(w fyne.Window, onChanged func()) *fyne.Menu {
+	return fyne.NewMenu("项目",
+		fyne.NewMenuItem("管理项目…", func() { showProjectManager(w, onChanged) }),
+	)
+}
+
+// showProjectManager 列出全部项目，可新建、重命名与删除
+func showProjectManager(w fyne.Window, onChanged func()) {
+	rows := container.NewVBox()
+
+	var rebuild func()
+	rebuild = func() {
+		rows.RemoveAll()
+		projects := model.AllProjects()
+		if len(projects) == 0 {
+			rows.Add(widget.NewLabel("暂无项目"))
+		}
+		for _, proj := range projects {
+			proj := proj
+			rename := widget.NewButtonWithIcon("", theme.DocumentCreateIcon(), func() {
+				entry := widget.NewEntry()
+				entry.SetText(proj.Name)
+				dialog.ShowForm("重命名项目", "保存", "取消",
+					[]*widget.FormItem{widget.NewFormItem("名称", entry)},
+					func(ok bool) {
+						if !ok {
+							return
+						}
+						if err := model.RenameProject(proj.ID, entry.Text); err != nil {
+							dialog.ShowError(err, w)
+							return
+						}
+						rebuild()
+						onChanged()
+					}, w)
+			})
+			rename.Importance = widget.LowImportance
+			del := widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
+				dialog.ShowConfirm("确认删除", fmt.Sprintf("删除项目 '%s'？\n其中的任务将变为%s。", proj.Name, model.NoProject), func(ok bool) {
+					if !ok {
+						return
+					}
+					if err := model.DeleteProject(proj.ID); err != nil {
+						dialog.ShowError(err, w)
+						return
+					}
+					rebuild()
+					onChanged()
+				}, w)
+			})
+			del.Importance = widget.LowImportance
+			rows.Add(container.NewHBox(widget.NewLabel(proj.Name), layout.NewSpacer(), rename, del))
+		}
+		rows.Refresh()
+	}
+	rebuild()
+
+	nameEntry := widget.NewEntry()
+	nameEntry.SetPlaceHolder("新项目名称")
+	addBtn := widget.NewButtonWithIcon("", theme.ContentAddIcon(), func() {
+		if _, err := model.AddProject(nameEntry.Text); err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		nameEntry.SetText("")
+		rebuild()
+		onChanged()
+	})
+	nameEntry.OnSubmitted = func(string) { addBtn.OnTapped() }
+
+	scroll := container.NewVScroll(rows)
+	scroll.SetMinSize(fyne.NewSize(320, 200))
+	content := container.NewBorder(nil, container.NewBorder(nil, nil, nil, addBtn, nameEntry), nil, nil, scroll)
+	dialog.ShowCustom("管理项目", "关闭", content, w)
+}
@@ -0,0 +1,59 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"tomato_clock/internal/model"
+	"tomato_clock/internal/quickadd"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// newQuickAddBar 创建任务列表上方的快速添加输入框：输入时在下方实时预览解析结果，
+// 回车或点击按钮后创建任务。onCreated 在任务创建后调用，出错时由 onError 显示。
+func newQuickAddBar(onCreated func(model.Task), onError func(error)) fyne.CanvasObject {
+	entry := widget.NewEntry()
+	entry.SetPlaceHolder("快速添加，如：写周报 #工作 @周五 !高 3🍅")
+	preview := widget.NewLabel("")
+	preview.TextStyle = fyne.TextStyle{Italic: true}
+	preview.Wrapping = fyne.TextWrapWord
+	preview.Hide()
+
+	entry.OnChanged = func(s string) {
+		if strings.TrimSpace(s) == "" {
+			preview.Hide()
+			return
+		}
+		preview.SetText(quickadd.Parse(s, time.Now()).Summary())
+		preview.Show()
+	}
+	submit := func() {
+		r := quickadd.Parse(entry.Text, time.Now())
+		if r.Title == "" {
+			if strings.TrimSpace(entry.Text) != "" {
+				onError(fmt.Errorf("请输入任务标题"))
+			}
+			return
+		}
+		task := r.Task()
+		if task.DueDate != nil {
+			task.RemindBefore, _ = model.ParseRemindOffsets(defaultRemindOffsets)
+		}
+		if err := model.CreateTask(&task); err != nil {
+			onError(err)
+			return
+		}
+		entry.SetText("")
+		onCreated(task)
+	}
+	entry.OnSubmitted = func(string) { submit() }
+	addBtn := widget.NewButtonWithIcon("", theme.ContentAddIcon(), submit)
+	addBtn.Importance = widget.LowImportance
+
+	return container.NewVBox(container.NewBorder(nil, nil, nil, addBtn, entry), preview)
+}
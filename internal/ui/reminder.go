@@ -0,0 +1,192 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"tomato_clock/internal/audio"
+	"tomato_clock/internal/model"
+	"tomato_clock/internal/quickadd"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// 截止日期的输入格式，时间部分可省略
+const (
+	dueDateLayout     = "2006-01-02"
+	dueDateTimeLayout = "2006-01-02 15:04"
+)
+
+// defaultRemindOffsets 新建任务时提醒输入框的默认值
+const defaultRemindOffsets = "1d"
+
+// dueDatePicker 新建/编辑任务对话框中的截止日期与提前提醒输入
+type dueDatePicker struct {
+	date   *widget.Entry
+	remind *widget.Entry
+}
+
+// newDueDatePicker 创建截止日期输入并填入任务当前的设置
+func newDueDatePicker(t model.Task) *dueDatePicker {
+	p := &dueDatePicker{date: widget.NewEntry(), remind: widget.NewEntry()}
+	p.date.SetPlaceHolder("可选，如：2025-09-30 或 2025-09-30 18:00")
+	p.remind.SetPlaceHolder("截止前提醒，如：1d, 2h, 30m")
+	if t.DueDate != nil {
+		d := *t.DueDate
+		if d.Equal(model.StartOfDay(d)) {
+			p.date.SetText(d.Format(dueDateLayout))
+		} else {
+			p.date.SetText(d.In(time.Local).Format(dueDateTimeLayout))
+		}
+	}
+	if t.ID == 0 {
+		p.remind.SetText(defaultRemindOffsets)
+	} else {
+		p.remind.SetText(model.FormatRemindOffsets(t.RemindBefore))
+	}
+	return p
+}
+
+// formItems 返回放入表单的输入项
+func (p *dueDatePicker) formItems() []*widget.FormItem {
+	return []*widget.FormItem{
+		widget.NewFormItem("截止日期", p.date),
+		widget.NewFormItem("提醒", p.remind),
+	}
+}
+
+// apply 校验输入并写入任务，截止日期为空时清除截止日期
+func (p *dueDatePicker) apply(t *model.Task) error {
+	offsets, err := model.ParseRemindOffsets(p.remind.Text)
+	if err != nil {
+		return err
+	}
+	text := strings.TrimSpace(p.date.Text)
+	if text == "" {
+		t.DueDate = nil
+		t.RemindBefore = offsets
+		return nil
+	}
+	due, err := time.ParseInLocation(dueDateTimeLayout, text, time.Local)
+	if err != nil {
+		due, err = time.ParseInLocation(dueDateLayout, text, time.Local)
+	}
+	if err != nil {
+		// 也接受 "明天"、"下周五 18:00" 这类写法
+		var ok bool
+		if due, ok = quickadd.ParseDate(text, time.Now()); !ok {
+			return fmt.Errorf("截止日期格式应为 YYYY-MM-DD 或 YYYY-MM-DD HH:MM")
+		}
+	}
+	t.DueDate = &due
+	t.RemindBefore = offsets
+	return nil
+}
+
+// dueBadge 返回任务列表中的截止日期标记，逾期时为 "⚠ 逾期N天"
+func dueBadge(t model.Task, now time.Time) string {
+	if t.DueDate == nil || t.IsDone {
+		return ""
+	}
+	day := model.DueDay(t)
+	if model.IsOverdue(t, now) {
+		days := int(model.StartOfDay(now).Sub(day).Hours() / 24)
+		if days <= 0 {
+			return "⚠ 已逾期"
+		}
+		return fmt.Sprintf("⚠ 逾期%d天", days)
+	}
+	return "📅" + dueText(t)
+}
+
+// dueText 返回截止日期的简短文本，有具体时间时带上时间
+func dueText(t model.Task) string {
+	if t.DueDate.Equal(model.StartOfDay(*t.DueDate)) {
+		return model.DueDay(t).Format("01-02")
+	}
+	return t.DueDate.In(time.Local).Format("01-02 15:04")
+}
+
+// remainingText 返回距截止的剩余时间，不足一分钟按一分钟计，如 "1天3小时"、"1小时53分钟"
+func remainingText(d time.Duration) string {
+	minutes := int((d + time.Minute - 1) / time.Minute)
+	days := minutes / (24 * 60)
+	if days == 0 {
+		return model.FormatDuration(minutes * 60)
+	}
+	if hours := minutes % (24 * 60) / 60; hours > 0 {
+		return fmt.Sprintf("%d天%d小时", days, hours)
+	}
+	return fmt.Sprintf("%d天", days)
+}
+
+// reminderText 返回提醒通知的内容。剩余时间按 now 计算而不是提醒的提前量，
+// 截止前不久才新建的任务或错过的提醒也显示实际还剩多久
+func reminderText(r model.Reminder, now time.Time) string {
+	if r.Offset == model.ReminderOverdue {
+		return fmt.Sprintf("「%s」已过截止时间（%s）", r.Task.Title, dueText(r.Task))
+	}
+	return fmt.Sprintf("「%s」将在%s后截止（%s）", r.Task.Title, remainingText(r.Due.Sub(now)), dueText(r.Task))
+}
+
+// digestText 返回每日到期汇总的内容，now 用于判断今天已经逾期的任务
+func digestText(d model.Digest, now time.Time) string {
+	var lines []string
+	section := func(name string, tasks []model.Task) {
+		if len(tasks) == 0 {
+			return
+		}
+		lines = append(lines, fmt.Sprintf("%s（%d）:", name, len(tasks)))
+		for _, t := range tasks {
+			lines = append(lines, fmt.Sprintf("  %s  %s", t.Title, dueBadge(t, now)))
+		}
+	}
+	section("已逾期", d.Overdue)
+	section("今天到期", d.Today)
+	section("本周到期", d.ThisWeek)
+	return strings.Join(lines, "\n")
+}
+
+// startReminderScheduler 每分钟检查一次截止提醒并发送桌面通知，每天首次检查时显示到期汇总。
+// 已发出的提醒记录在数据文件中，重启后不会重复提醒。onFired 在发出提醒后调用以刷新界面。
+func startReminderScheduler(app fyne.App, w fyne.Window, onFired func()) {
+	check := func(now time.Time) {
+		rs, err := model.TakeDueReminders(now)
+		if err != nil {
+			log.Printf("[ERROR] 保存提醒记录失败: %v", err)
+		}
+		for _, r := range rs {
+			title := "任务即将截止"
+			if r.Offset == model.ReminderOverdue {
+				title = "任务已逾期"
+			}
+			app.SendNotification(fyne.NewNotification(title, reminderText(r, now)))
+		}
+
+		d, ok, err := model.TakeDailyDigest(now)
+		if err != nil {
+			log.Printf("[ERROR] 保存每日汇总日期失败: %v", err)
+		}
+		if ok {
+			text := digestText(d, now)
+			app.SendNotification(fyne.NewNotification("今日到期汇总", text))
+			runOnMain(func() { dialog.ShowInformation("今日到期汇总", text, w) })
+		}
+		if len(rs) > 0 {
+			runOnMain(func() { playSound(audio.EventTaskDue) })
+			if onFired != nil {
+				runOnMain(onFired)
+			}
+		}
+	}
+	go func() {
+		check(time.Now())
+		for now := range time.Tick(time.Minute) {
+			check(now)
+		}
+	}()
+}
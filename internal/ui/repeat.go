@@ -0,0 +1,139 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"tomato_clock/internal/model"
+
+	"fyne.io/fyne/v2/widget"
+)
+
+// repeatOptions 重复方式选项（显示名 -> 规则种类）
+var repeatOptions = []struct {
+	title string
+	kind  string
+	hint  string // 需要参数时输入框的提示
+}{
+	{"不重复", model.RepeatNone, ""},
+	{"每天", model.RepeatDaily, ""},
+	{"工作日", model.RepeatWorkday, ""},
+	{"每周", model.RepeatWeekly, ""},
+	{"每周指定几天", model.RepeatWeekly, "如：1,3,5 表示周一、三、五"},
+	{"每隔N天", model.RepeatEvery, "间隔天数，如：3"},
+}
+
+// repeatPicker 新建/编辑任务对话框中的重复规则输入
+type repeatPicker struct {
+	kind  *widget.Select
+	arg   *widget.Entry
+	spawn *widget.Check
+}
+
+// newRepeatPicker 创建重复规则输入并填入任务当前的规则
+func newRepeatPicker(t model.Task) *repeatPicker {
+	p := &repeatPicker{
+		arg:   widget.NewEntry(),
+		spawn: widget.NewCheck("每期生成新任务（保留旧任务）", nil),
+	}
+	var titles []string
+	for _, o := range repeatOptions {
+		titles = append(titles, o.title)
+	}
+	p.kind = widget.NewSelect(titles, func(s string) {
+		for _, o := range repeatOptions {
+			if o.title != s {
+				continue
+			}
+			p.arg.SetPlaceHolder(o.hint)
+			if o.hint == "" {
+				p.arg.SetText("")
+				p.arg.Disable()
+			} else {
+				p.arg.Enable()
+			}
+			if o.kind == model.RepeatNone {
+				p.spawn.Disable()
+			} else {
+				p.spawn.Enable()
+			}
+		}
+	})
+
+	spec, err := model.ParseRepeatRule(t.RepeatRule)
+	if err != nil {
+		spec = model.RepeatSpec{Kind: model.RepeatNone}
+	}
+	switch {
+	case spec.Kind == model.RepeatWeekly && len(spec.Weekdays) > 0:
+		p.kind.SetSelected("每周指定几天")
+		_, arg, _ := strings.Cut(spec.String(), ":")
+		p.arg.SetText(arg)
+	case spec.Kind == model.RepeatEvery:
+		p.kind.SetSelected("每隔N天")
+		p.arg.SetText(fmt.Sprint(spec.Every))
+	default:
+		for _, o := range repeatOptions {
+			if o.kind == spec.Kind && o.hint == "" {
+				p.kind.SetSelected(o.title)
+				break
+			}
+		}
+	}
+	p.spawn.SetChecked(t.RepeatSpawn)
+	return p
+}
+
+// formItems 返回放入表单的输入项
+func (p *repeatPicker) formItems() []*widget.FormItem {
+	return []*widget.FormItem{
+		widget.NewFormItem("重复", p.kind),
+		widget.NewFormItem("重复参数", p.arg),
+		widget.NewFormItem("", p.spawn),
+	}
+}
+
+// apply 校验输入并把规则写入任务
+func (p *repeatPicker) apply(t *model.Task) error {
+	rule := model.RepeatNone
+	for _, o := range repeatOptions {
+		if o.title != p.kind.Selected {
+			continue
+		}
+		rule = o.kind
+		if o.hint != "" {
+			arg := strings.ReplaceAll(strings.TrimSpace(p.arg.Text), "，", ",")
+			rule += ":" + arg
+		}
+	}
+	spec, err := model.ParseRepeatRule(rule)
+	if err != nil {
+		return err
+	}
+	t.RepeatRule = spec.String()
+	t.RepeatSpawn = spec.IsRepeating() && p.spawn.Checked
+	return nil
+}
+
+// repeatHistoryText 概括重复任务最近各期的完成情况，非重复任务返回空字符串
+func repeatHistoryText(t model.Task) string {
+	hist := model.OccurrenceHistory(t.Series())
+	if len(hist) == 0 {
+		return ""
+	}
+	const recent = 14
+	if len(hist) > recent {
+		hist = hist[len(hist)-recent:]
+	}
+	var done int
+	var marks []string
+	for _, o := range hist {
+		mark := "✗"
+		if o.Done {
+			done++
+			mark = "✓"
+		}
+		marks = append(marks, o.Date.Format("01-02")+mark)
+	}
+	return fmt.Sprintf("最近%d期完成%d期\n%s", len(hist), done, strings.Join(marks, " "))
+}
@@ -0,0 +1,82 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"tomato_clock/internal/report"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// reportPeriods 报表周期选项（显示名 -> 周期名称）
+var reportPeriods = []struct {
+	title string
+	name  string
+}{
+	{"本周", report.PeriodWeek},
+	{"上周", report.PeriodLastWeek},
+	{"本月", report.PeriodMonth},
+	{"上月", report.PeriodLastMonth},
+}
+
+// newReportMenu 创建“报表”菜单，各菜单项直接进入对应周期的导出流程
+func newReportMenu(w fyne.Window) *fyne.Menu {
+	var items []*fyne.MenuItem
+	for _, p := range reportPeriods {
+		p := p
+		items = append(items, fyne.NewMenuItem(fmt.Sprintf("导出%s报表…", p.title), func() {
+			showReportDialog(w, p.name)
+		}))
+	}
+	items = append(items, fyne.NewMenuItemSeparator(),
+		fyne.NewMenuItem("每日完成记录…", func() { showCompletionHistory(w) }))
+	return fyne.NewMenu("报表", items...)
+}
+
+// showReportDialog 选择输出格式后保存指定周期的报表
+func showReportDialog(w fyne.Window, periodName string) {
+	formatSelect := widget.NewSelect([]string{"Markdown", "HTML"}, nil)
+	formatSelect.SetSelected("HTML")
+
+	dialog.ShowForm("导出报表", "下一步", "取消",
+		[]*widget.FormItem{widget.NewFormItem("格式", formatSelect)},
+		func(confirm bool) {
+			if !confirm {
+				return
+			}
+			format, ext := report.FormatHTML, ".html"
+			if formatSelect.Selected == "Markdown" {
+				format, ext = report.FormatMarkdown, ".md"
+			}
+
+			p, err := report.NewPeriod(periodName, time.Now())
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			r := report.Generate(p, report.Goal{Label: studyGoalLabel, DailySeconds: studyGoalSeconds})
+
+			save := dialog.NewFileSave(func(wc fyne.URIWriteCloser, err error) {
+				if err != nil {
+					dialog.ShowError(err, w)
+					return
+				}
+				if wc == nil {
+					return // 用户取消
+				}
+				defer wc.Close()
+				if err := report.Render(wc, r, format); err != nil {
+					dialog.ShowError(err, w)
+					return
+				}
+				log.Printf("[REPORT] 已导出报表: %s", wc.URI().Path())
+				dialog.ShowInformation("导出成功", fmt.Sprintf("报表已保存到\n%s", wc.URI().Path()), w)
+			}, w)
+			save.SetFileName(fmt.Sprintf("tomato_%s_%s%s", p.Name, p.From.Format("20060102"), ext))
+			save.Show()
+		}, w)
+}
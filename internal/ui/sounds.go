@@ -0,0 +1,267 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"tomato_clock/internal/audio"
+	"tomato_clock/internal/config"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// sounds 按声音方案加载的各事件提示音，加载失败时为 nil
+var sounds *audio.Board
+
+// loadSounds 按配置文件中的声音方案加载提示音，返回的错误用于在界面上提示
+func loadSounds() error {
+	var saved map[string]config.Sound
+	if cfg, err := config.Load(); err == nil {
+		saved = cfg.Sounds
+	}
+	board, err := audio.NewBoard(audio.Scheme(saved))
+	if sounds != nil {
+		sounds.StopAll()
+	}
+	sounds = board
+	if err != nil {
+		log.Printf("[ERROR] 加载提示音失败: %v", err)
+	} else {
+		log.Printf("[DEBUG] 提示音方案已加载，查找目录: %s", strings.Join(audio.SoundDirs(), "、"))
+	}
+	return err
+}
+
+// playSound 播放事件的提示音，静音时不播放
+func playSound(e audio.Event) {
+	if muteAlerts || sounds == nil {
+		return
+	}
+	if err := sounds.Play(e); err != nil {
+		log.Printf("[ERROR] 播放%s提示音失败: %v", e.Title(), err)
+	}
+}
+
+// stopSound 停止事件的提示音
+func stopSound(e audio.Event) {
+	if sounds != nil {
+		sounds.Stop(e)
+	}
+}
+
+// stopAllSounds 停止全部提示音
+func stopAllSounds() {
+	if sounds != nil {
+		sounds.StopAll()
+	}
+}
+
+// 播放方式选项（显示名 -> 播放方式）
+var playModeOptions = []struct {
+	title string
+	mode  string
+}{
+	{"播放一次", audio.PlayOnce},
+	{"循环直到关闭", audio.PlayLoop},
+	{"只播放前 N 秒", audio.PlayFirst},
+}
+
+const noSoundOption = "（无）"
+
+// soundFileText 把方案中的文件设置转为输入框中显示的文字
+func soundFileText(file string) string {
+	switch {
+	case file == "":
+		return noSoundOption
+	case strings.HasPrefix(file, audio.BuiltinPrefix):
+		return "内置: " + strings.TrimPrefix(file, audio.BuiltinPrefix)
+	case strings.HasPrefix(file, audio.SynthPrefix):
+		return "合成: " + strings.TrimPrefix(file, audio.SynthPrefix)
+	}
+	return file
+}
+
+// parseSoundFile 是 soundFileText 的逆操作
+func parseSoundFile(text string) string {
+	text = strings.TrimSpace(text)
+	switch {
+	case text == "" || text == noSoundOption:
+		return ""
+	case strings.HasPrefix(text, "内置: "):
+		return audio.BuiltinPrefix + strings.TrimPrefix(text, "内置: ")
+	case strings.HasPrefix(text, "合成: "):
+		return audio.SynthPrefix + strings.TrimPrefix(text, "合成: ")
+	}
+	return text
+}
+
+// soundRow 设置对话框中一个事件的编辑控件
+type soundRow struct {
+	file    *widget.SelectEntry
+	volume  *widget.Slider
+	mode    *widget.Select
+	seconds *widget.Entry
+}
+
+// sound 返回当前编辑的设置
+func (r *soundRow) sound() config.Sound {
+	s := config.Sound{File: parseSoundFile(r.file.Text), Volume: int(r.volume.Value), Mode: audio.PlayOnce}
+	for _, o := range playModeOptions {
+		if o.title == r.mode.Selected {
+			s.Mode = o.mode
+		}
+	}
+	if s.Mode == audio.PlayFirst {
+		s.Seconds, _ = strconv.Atoi(strings.TrimSpace(r.seconds.Text))
+	}
+	return s
+}
+
+// showSoundSettings 编辑各事件的提示音：声音文件、音量与播放方式，可以试听；以及倒计时结束的闹铃方式、正念铃声的间隔与滴答声
+func showSoundSettings(w fyne.Window) {
+	var saved map[string]config.Sound
+	var savedAlarm config.Alarm
+	var savedTick config.Tick
+	savedBell := bellSetting
+	if cfg, err := config.Load(); err == nil {
+		saved, savedAlarm, savedBell, savedTick = cfg.Sounds, cfg.Alarm, cfg.Bell, cfg.Tick
+	}
+	scheme := audio.Scheme(saved)
+
+	fileOptions := []string{noSoundOption}
+	for _, name := range audio.Builtins() {
+		fileOptions = append(fileOptions, soundFileText(audio.BuiltinPrefix+name))
+	}
+	for _, name := range audio.SynthPresets() {
+		fileOptions = append(fileOptions, soundFileText(audio.SynthPrefix+name))
+	}
+	var modeTitles []string
+	for _, o := range playModeOptions {
+		modeTitles = append(modeTitles, o.title)
+	}
+
+	// 同一时间只试听一个声音，再次点击同一个「试听」停止
+	var previewing *audio.AlertPlayer
+	var previewEvent audio.Event
+	stopPreview := func() {
+		if previewing != nil {
+			previewing.Stop()
+			previewing = nil
+		}
+	}
+
+	rows := map[audio.Event]*soundRow{}
+	list := container.NewVBox()
+	for _, e := range audio.Events {
+		s := scheme[e]
+		r := &soundRow{
+			file:    widget.NewSelectEntry(fileOptions),
+			volume:  widget.NewSlider(0, 100),
+			mode:    widget.NewSelect(modeTitles, nil),
+			seconds: widget.NewEntry(),
+		}
+		rows[e] = r
+		r.file.SetText(soundFileText(s.File))
+		r.file.SetPlaceHolder("声音文件路径")
+		r.volume.Step = 5
+		r.volume.SetValue(float64(s.Volume))
+		volumeLabel := widget.NewLabel(fmt.Sprintf("%d%%", s.Volume))
+		r.volume.OnChanged = func(v float64) { volumeLabel.SetText(fmt.Sprintf("%d%%", int(v))) }
+		r.seconds.SetText(strconv.Itoa(s.Seconds))
+		r.mode.OnChanged = func(title string) {
+			if title == playModeOptions[2].title {
+				r.seconds.Enable()
+			} else {
+				r.seconds.Disable()
+			}
+		}
+		for _, o := range playModeOptions {
+			if o.mode == s.Mode {
+				r.mode.SetSelected(o.title)
+			}
+		}
+
+		browse := widget.NewButtonWithIcon("", theme.FolderOpenIcon(), func() {
+			fd := dialog.NewFileOpen(func(rc fyne.URIReadCloser, err error) {
+				if err != nil || rc == nil {
+					return
+				}
+				rc.Close()
+				r.file.SetText(rc.URI().Path())
+			}, w)
+			fd.SetFilter(storage.NewExtensionFileFilter(audio.SupportedExtensions))
+			fd.Show()
+		})
+		e := e
+		preview := widget.NewButtonWithIcon("试听", theme.MediaPlayIcon(), func() {
+			wasPlaying := previewing != nil && previewing.Playing() && previewEvent == e
+			stopPreview()
+			if wasPlaying {
+				return
+			}
+			p, err := audio.Preview(r.sound())
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			previewing, previewEvent = p, e
+		})
+
+		title := widget.NewLabel(e.Title())
+		title.TextStyle = fyne.TextStyle{Bold: true}
+		volume := container.NewGridWrap(fyne.NewSize(160, r.volume.MinSize().Height), r.volume)
+		list.Add(container.NewVBox(
+			title,
+			container.NewBorder(nil, nil, nil, container.NewHBox(browse, preview), r.file),
+			container.NewHBox(widget.NewLabel("音量"), volume, volumeLabel, r.mode, r.seconds, widget.NewLabel("秒")),
+			widget.NewSeparator(),
+		))
+	}
+
+	alarmSettings := newAlarmForm(savedAlarm, fileOptions)
+	list.Add(alarmSettings.content())
+	bellSettings := newBellForm(savedBell)
+	list.Add(widget.NewSeparator())
+	list.Add(bellSettings.content())
+	tickSettings := newTickForm(savedTick, fileOptions)
+	list.Add(widget.NewSeparator())
+	list.Add(tickSettings.content())
+
+	hint := widget.NewLabel("「内置」声音已编译进程序，resources/sounds 中的同名文件优先；「合成」音色由程序实时生成（bell 柔和钟声、double_beep 双响、chime 上升和弦、woodblock 木鱼）。支持 MP3、WAV、OGG、FLAC；相对路径相对于 resources/sounds。再次点击「试听」停止。")
+	hint.Wrapping = fyne.TextWrapWord
+	hint.Importance = widget.LowImportance
+
+	d := dialog.NewCustomConfirm("提示音设置", "保存", "取消",
+		container.NewBorder(hint, nil, nil, nil, container.NewVScroll(list)),
+		func(ok bool) {
+			stopPreview()
+			if !ok {
+				return
+			}
+			m := map[string]config.Sound{}
+			for e, r := range rows {
+				m[string(e)] = r.sound()
+			}
+			a := alarmSettings.alarm()
+			bellSetting = bellSettings.bell(bellSetting.Enabled)
+			b := bellSetting
+			tick := tickSettings.tick()
+			if err := config.Update(func(cfg *config.Config) { cfg.Sounds, cfg.Alarm, cfg.Bell, cfg.Tick = m, a, b, tick }); err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			setTickTrack(tick)
+			if err := loadSounds(); err != nil {
+				dialog.ShowError(err, w)
+			}
+		}, w)
+	d.Resize(fyne.NewSize(620, 560))
+	d.Show()
+}
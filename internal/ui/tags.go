@@ -0,0 +1,134 @@
+package ui
+
+import (
+	"strings"
+
+	"tomato_clock/internal/model"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// flowLayout 从左到右排列子元素，一行放不下时自动换行
+type flowLayout struct {
+	width float32 // 计算最小尺寸时假定的可用宽度
+}
+
+func (f *flowLayout) rows(objects []fyne.CanvasObject, width float32) (positions []fyne.Position, height float32) {
+	pad := theme.Padding()
+	var x, y, rowH float32
+	for _, o := range objects {
+		if !o.Visible() {
+			positions = append(positions, fyne.Position{})
+			continue
+		}
+		size := o.MinSize()
+		if x > 0 && x+size.Width > width {
+			x = 0
+			y += rowH + pad
+			rowH = 0
+		}
+		positions = append(positions, fyne.NewPos(x, y))
+		x += size.Width + pad
+		if size.Height > rowH {
+			rowH = size.Height
+		}
+	}
+	return positions, y + rowH
+}
+
+func (f *flowLayout) Layout(objects []fyne.CanvasObject, size fyne.Size) {
+	f.width = size.Width
+	positions, _ := f.rows(objects, size.Width)
+	for i, o := range objects {
+		o.Resize(o.MinSize())
+		o.Move(positions[i])
+	}
+}
+
+func (f *flowLayout) MinSize(objects []fyne.CanvasObject) fyne.Size {
+	width := f.width
+	if width <= 0 {
+		width = 260
+	}
+	_, height := f.rows(objects, width)
+	return fyne.NewSize(width, height)
+}
+
+// tagEditor 以标签块的形式编辑任务的多个标签，第一个标签为主标签
+type tagEditor struct {
+	tags  []string
+	chips *fyne.Container
+	entry *widget.SelectEntry
+
+	content fyne.CanvasObject
+}
+
+// newTagEditor 创建标签编辑器，suggestions 为下拉候选的已有标签
+func newTagEditor(tags []string, suggestions []string) *tagEditor {
+	e := &tagEditor{tags: model.NormalizeTags(tags)}
+	e.chips = container.New(&flowLayout{})
+	e.entry = widget.NewSelectEntry(suggestions)
+	e.entry.SetPlaceHolder("输入标签后回车，多个用逗号分隔")
+	e.entry.OnSubmitted = func(s string) { e.add(s) }
+	addBtn := widget.NewButtonWithIcon("", theme.ContentAddIcon(), func() { e.add(e.entry.Text) })
+	addBtn.Importance = widget.LowImportance
+	e.content = container.NewVBox(e.chips, container.NewBorder(nil, nil, nil, addBtn, e.entry))
+	e.refresh()
+	return e
+}
+
+// add 解析输入并追加标签
+func (e *tagEditor) add(s string) {
+	s = strings.NewReplacer("，", ",", "、", ",", " ", ",").Replace(s)
+	e.tags = model.NormalizeTags(append(e.tags, strings.Split(s, ",")...))
+	e.entry.SetText("")
+	e.refresh()
+}
+
+// remove 删除指定标签
+func (e *tagEditor) remove(tag string) {
+	var kept []string
+	for _, t := range e.tags {
+		if t != tag {
+			kept = append(kept, t)
+		}
+	}
+	e.tags = kept
+	e.refresh()
+}
+
+// refresh 重建标签块，第一个标签高亮表示主标签
+func (e *tagEditor) refresh() {
+	e.chips.RemoveAll()
+	for i, tag := range e.tags {
+		tag := tag
+		chip := widget.NewButtonWithIcon("#"+tag, theme.CancelIcon(), func() { e.remove(tag) })
+		chip.IconPlacement = widget.ButtonIconTrailingText
+		chip.Importance = widget.LowImportance
+		if i == 0 {
+			chip.Importance = widget.HighImportance
+		}
+		e.chips.Add(chip)
+	}
+	e.chips.Refresh()
+}
+
+// Tags 返回当前标签（含输入框中尚未提交的内容）
+func (e *tagEditor) Tags() []string {
+	if strings.TrimSpace(e.entry.Text) != "" {
+		e.add(e.entry.Text)
+	}
+	return append([]string(nil), e.tags...)
+}
+
+// apply 把标签写入任务，主标签同步到旧版 Label 字段
+func (e *tagEditor) apply(t *model.Task) {
+	t.Tags = e.Tags()
+	t.Label = ""
+	if len(t.Tags) > 0 {
+		t.Label = t.Tags[0]
+	}
+}
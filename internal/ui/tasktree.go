@@ -0,0 +1,141 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+
+	"tomato_clock/internal/model"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// noParentOption 父任务选择中表示顶层任务的选项
+const noParentOption = "（顶层任务）"
+
+// doneSectionKey 任务树中“已完成”分组的键，分组下是已完成的顶层任务
+const (
+	doneSectionKey  int64 = -1
+	doneSectionNode       = "done"
+)
+
+// taskNodeID 将任务 ID 转为任务树的节点 ID，根节点为空字符串
+func taskNodeID(id int64) widget.TreeNodeID {
+	switch id {
+	case 0:
+		return ""
+	case doneSectionKey:
+		return doneSectionNode
+	}
+	return strconv.FormatInt(id, 10)
+}
+
+// parseTaskNodeID 将任务树的节点 ID 还原为任务 ID，根节点返回 0
+func parseTaskNodeID(uid widget.TreeNodeID) int64 {
+	if uid == doneSectionNode {
+		return doneSectionKey
+	}
+	id, _ := strconv.ParseInt(uid, 10, 64)
+	return id
+}
+
+// parentOptions 返回可作为 exclude 父任务的候选项（显示名 -> 任务 ID），
+// 排除 exclude 自身及其子孙任务以避免形成环；exclude 为 0 时不排除。
+func parentOptions(tasks []model.Task, exclude int64) ([]string, map[string]int64) {
+	byID := make(map[int64]model.Task, len(tasks))
+	for _, t := range tasks {
+		byID[t.ID] = t
+	}
+	options := []string{noParentOption}
+	ids := map[string]int64{noParentOption: 0}
+
+	idx := model.ChildIndex(tasks)
+	var walk func(parent int64)
+	walk = func(parent int64) {
+		for _, id := range idx[parent] {
+			if exclude != 0 && id == exclude {
+				continue
+			}
+			name := fmt.Sprintf("%s (#%d)", model.TaskPathTitle(byID, id), id)
+			options = append(options, name)
+			ids[name] = id
+			walk(id)
+		}
+	}
+	walk(0)
+	return options, ids
+}
+
+// parentOptionName 返回 parentID 在 parentOptions 结果中的显示名
+func parentOptionName(ids map[string]int64, parentID int64) string {
+	for name, id := range ids {
+		if id == parentID {
+			return name
+		}
+	}
+	return noParentOption
+}
+
+// showDeleteTaskDialog 删除任务；有子任务时让用户选择把子任务移到哪里，或连同子任务一起删除
+func showDeleteTaskDialog(w fyne.Window, t model.Task, tasks []model.Task, onDeleted func()) {
+	children := model.ChildIndex(tasks)[t.ID]
+	if len(children) == 0 {
+		dialog.ShowConfirm("确认删除", fmt.Sprintf("删除任务 '%s'?", t.Title), func(ok bool) {
+			if !ok {
+				return
+			}
+			if err := model.DeleteTask(t.ID); err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			onDeleted()
+		}, w)
+		return
+	}
+
+	const (
+		moveUp    = "移到上一级"
+		moveOther = "移到其他任务下"
+		deleteAll = "连同子任务一起删除"
+	)
+	options, ids := parentOptions(tasks, t.ID)
+	target := widget.NewSelect(options, nil)
+	target.SetSelected(parentOptionName(ids, t.ParentID))
+	target.Disable()
+	mode := widget.NewRadioGroup([]string{moveUp, moveOther, deleteAll}, func(s string) {
+		if s == moveOther {
+			target.Enable()
+		} else {
+			target.Disable()
+		}
+	})
+	mode.SetSelected(moveUp)
+
+	dialog.ShowForm("确认删除",
+		"删除", "取消",
+		[]*widget.FormItem{
+			widget.NewFormItem("", widget.NewLabel(fmt.Sprintf("任务 '%s' 有 %d 个子任务，删除后子任务：", t.Title, len(children)))),
+			widget.NewFormItem("", mode),
+			widget.NewFormItem("移到", target),
+		},
+		func(ok bool) {
+			if !ok {
+				return
+			}
+			var err error
+			switch mode.Selected {
+			case deleteAll:
+				err = model.DeleteTaskTree(t.ID)
+			case moveOther:
+				err = model.DeleteTaskRehome(t.ID, ids[target.Selected])
+			default:
+				err = model.DeleteTaskRehome(t.ID, t.ParentID)
+			}
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			onDeleted()
+		}, w)
+}
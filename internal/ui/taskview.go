@@ -0,0 +1,279 @@
+package ui
+
+import (
+	"log"
+
+	"tomato_clock/internal/config"
+	"tomato_clock/internal/model"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// sortModeOptions 排序方式选项（显示名 -> 排序方式）
+var sortModeOptions = []struct {
+	title string
+	mode  string
+}{
+	{"手动排序", model.SortManual},
+	{"按优先级", model.SortPriority},
+	{"按截止日期", model.SortDueDate},
+	{"最近专注", model.SortRecentFocus},
+	{"按标题", model.SortTitle},
+}
+
+const (
+	allTagsOption     = "全部标签"
+	allProjectsOption = "全部项目"
+)
+
+// taskViewBar 任务列表上方的排序与筛选栏，修改后立即保存到配置文件
+type taskViewBar struct {
+	view     config.TaskView
+	onChange func()
+
+	sortSel     *widget.Select
+	tagSel      *widget.Select
+	projectSel  *widget.Select
+	hideDone    *widget.Check
+	overdueOnly *widget.Check
+	nextActions *widget.Check
+	archiveSel  *widget.Select
+	search      *widget.Entry
+	projectIDs  map[string]int64
+
+	content fyne.CanvasObject
+}
+
+// newTaskViewBar 创建排序与筛选栏，初始状态从配置文件恢复
+func newTaskViewBar(onChange func()) *taskViewBar {
+	b := &taskViewBar{onChange: onChange}
+	if cfg, err := config.Load(); err == nil {
+		b.view = cfg.TaskView
+	}
+	if b.view.SortMode == "" {
+		b.view.SortMode = model.SortManual
+	}
+
+	var sortTitles []string
+	for _, o := range sortModeOptions {
+		sortTitles = append(sortTitles, o.title)
+	}
+	b.sortSel = widget.NewSelect(sortTitles, nil)
+	for _, o := range sortModeOptions {
+		if o.mode == b.view.SortMode {
+			b.sortSel.SetSelected(o.title)
+		}
+	}
+	b.sortSel.OnChanged = func(s string) {
+		for _, o := range sortModeOptions {
+			if o.title == s {
+				b.view.SortMode = o.mode
+			}
+		}
+		b.changed()
+	}
+
+	b.hideDone = widget.NewCheck("隐藏已完成", func(on bool) {
+		b.view.HideDone = on
+		b.changed()
+	})
+	b.hideDone.Checked = b.view.HideDone
+	b.overdueOnly = widget.NewCheck("只看逾期", func(on bool) {
+		b.view.OverdueOnly = on
+		b.changed()
+	})
+	b.overdueOnly.Checked = b.view.OverdueOnly
+	b.nextActions = widget.NewCheck("下一步行动", func(on bool) {
+		b.view.NextActions = on
+		b.changed()
+	})
+	b.nextActions.Checked = b.view.NextActions
+
+	b.archiveSel = newArchiveSelect(b.view.ArchiveAfterDays, func(days int) {
+		b.view.ArchiveAfterDays = days
+		archiveCompleted(days)
+		b.changed()
+	})
+
+	b.tagSel = widget.NewSelect(nil, nil)
+	b.projectSel = widget.NewSelect(nil, nil)
+	b.refreshOptions()
+	b.tagSel.OnChanged = func(s string) {
+		if s == allTagsOption {
+			s = ""
+		}
+		if s != b.view.Tag {
+			b.view.Tag = s
+			b.changed()
+		}
+	}
+	b.projectSel.OnChanged = func(s string) {
+		if id := b.projectIDs[s]; id != b.view.ProjectID {
+			b.view.ProjectID = id
+			b.changed()
+		}
+	}
+
+	// 搜索关键字只影响当前显示，不保存到配置文件
+	b.search = widget.NewEntry()
+	b.search.SetPlaceHolder("搜索标题、备注或标签")
+	b.search.OnChanged = func(string) {
+		if b.onChange != nil {
+			b.onChange()
+		}
+	}
+
+	b.content = container.NewVBox(
+		b.search,
+		container.NewGridWithColumns(3, b.sortSel, b.tagSel, b.projectSel),
+		container.NewHBox(b.hideDone, b.overdueOnly, b.nextActions, layout.NewSpacer(), b.archiveSel),
+	)
+	return b
+}
+
+// refreshOptions 根据当前的标签与项目重建筛选选项
+func (b *taskViewBar) refreshOptions() {
+	tags := append([]string{allTagsOption}, model.Tags()...)
+	b.tagSel.Options = tags
+	tag := allTagsOption
+	for _, t := range tags {
+		if t == b.view.Tag {
+			tag = t
+		}
+	}
+	b.tagSel.Selected = tag
+	b.tagSel.Refresh()
+
+	b.projectIDs = map[string]int64{allProjectsOption: 0, model.NoProject: model.NoProjectID}
+	options := []string{allProjectsOption, model.NoProject}
+	project := allProjectsOption
+	if b.view.ProjectID == model.NoProjectID {
+		project = model.NoProject
+	}
+	for _, p := range model.AllProjects() {
+		options = append(options, p.Name)
+		b.projectIDs[p.Name] = p.ID
+		if p.ID == b.view.ProjectID {
+			project = p.Name
+		}
+	}
+	b.projectSel.Options = options
+	b.projectSel.Selected = project
+	b.projectSel.Refresh()
+}
+
+// changed 保存视图设置并通知任务列表刷新
+func (b *taskViewBar) changed() {
+	view := b.view
+	if err := config.Update(func(cfg *config.Config) { cfg.TaskView = view }); err != nil {
+		log.Printf("[ERROR] 保存任务列表视图失败: %v", err)
+	}
+	if b.onChange != nil {
+		b.onChange()
+	}
+}
+
+// filter 返回当前的筛选条件
+func (b *taskViewBar) filter() model.TaskFilter {
+	return model.TaskFilter{
+		HideDone:    b.view.HideDone,
+		Tag:         b.view.Tag,
+		ProjectID:   b.view.ProjectID,
+		OverdueOnly: b.view.OverdueOnly,
+		NextActions: b.view.NextActions,
+		Query:       b.search.Text,
+	}
+}
+
+// manualOrder 是否处于手动排序模式（只有此时才能拖动排序）
+func (b *taskViewBar) manualOrder() bool {
+	return b.view.SortMode == model.SortManual
+}
+
+// dragHandle 任务行左侧的拖动手柄，松开时报告纵向拖动距离
+type dragHandle struct {
+	widget.Icon
+	dy        float32
+	OnDragEnd func(dy float32)
+}
+
+func newDragHandle() *dragHandle {
+	h := &dragHandle{}
+	h.Resource = theme.MenuIcon()
+	h.ExtendBaseWidget(h)
+	return h
+}
+
+// Dragged 实现 fyne.Draggable
+func (h *dragHandle) Dragged(e *fyne.DragEvent) {
+	h.dy += e.Dragged.DY
+}
+
+// DragEnd 实现 fyne.Draggable
+func (h *dragHandle) DragEnd() {
+	dy := h.dy
+	h.dy = 0
+	if h.OnDragEnd != nil {
+		h.OnDragEnd(dy)
+	}
+}
+
+// siblingsOf 返回 id 在任务树中所在的同级列表
+func siblingsOf(children map[int64][]int64, id int64) []int64 {
+	for _, ids := range children {
+		for _, c := range ids {
+			if c == id {
+				return ids
+			}
+		}
+	}
+	return nil
+}
+
+// moveInList 返回把 id 在 ids 中移动 steps 个位置后的新顺序（超出范围时移到两端）
+func moveInList(ids []int64, id int64, steps int) []int64 {
+	from := -1
+	for i, c := range ids {
+		if c == id {
+			from = i
+		}
+	}
+	if from < 0 {
+		return ids
+	}
+	to := from + steps
+	if to < 0 {
+		to = 0
+	}
+	if to >= len(ids) {
+		to = len(ids) - 1
+	}
+	res := append([]int64(nil), ids[:from]...)
+	res = append(res, ids[from+1:]...)
+	res = append(res[:to], append([]int64{id}, res[to:]...)...)
+	return res
+}
+
+// newPrioritySelect 创建优先级选择框并选中 p
+func newPrioritySelect(p int) *widget.Select {
+	sel := widget.NewSelect(model.PriorityNames, nil)
+	if p < 0 || p >= len(model.PriorityNames) {
+		p = model.PriorityNone
+	}
+	sel.SetSelected(model.PriorityNames[p])
+	return sel
+}
+
+// selectedPriority 返回优先级选择框当前对应的优先级
+func selectedPriority(sel *widget.Select) int {
+	for i, name := range model.PriorityNames {
+		if name == sel.Selected {
+			return i
+		}
+	}
+	return model.PriorityNone
+}
@@ -0,0 +1,149 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"tomato_clock/internal/audio"
+	"tomato_clock/internal/config"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// tickTrack 专注时的滴答声，关闭时为 nil；由计时更新驱动，暂停、结束时随之暂停、停止
+var tickTrack *audio.TickTrack
+
+// loadTick 读取滴答声设置
+func loadTick() {
+	var c config.Tick
+	if cfg, err := config.Load(); err == nil {
+		c = cfg.Tick
+	}
+	setTickTrack(c)
+}
+
+// setTickTrack 按设置替换滴答声，正在播放的旧滴答声停止，下一次计时更新时按新设置开始
+func setTickTrack(c config.Tick) {
+	if tickTrack != nil {
+		tickTrack.Stop()
+		tickTrack = nil
+	}
+	if c.Sound != "" {
+		tickTrack = audio.NewTickTrack(c)
+	}
+}
+
+// updateTick 每次计时更新时调用，按播放时段开始或停止滴答声
+func updateTick(elapsed, remain int, countdown bool) {
+	if tickTrack == nil {
+		return
+	}
+	if err := tickTrack.Update(elapsed, remain, countdown, false); err != nil {
+		log.Printf("[ERROR] 播放滴答声失败: %v", err)
+		tickTrack = nil
+	}
+}
+
+// pauseTick 随计时器暂停或继续滴答声
+func pauseTick(paused bool) {
+	if tickTrack != nil {
+		tickTrack.SetPaused(paused)
+	}
+}
+
+// stopTick 专注结束时停止滴答声
+func stopTick() {
+	if tickTrack != nil {
+		tickTrack.Stop()
+	}
+}
+
+// tickForm 提示音设置中滴答声的编辑控件
+type tickForm struct {
+	sound   *widget.SelectEntry
+	every   *widget.Entry
+	volume  *widget.Slider
+	mode    *widget.Select
+	minutes *widget.Entry
+}
+
+// newTickForm 创建滴答声的编辑控件，fileOptions 为可选的声音
+func newTickForm(c config.Tick, fileOptions []string) *tickForm {
+	f := &tickForm{
+		sound:   widget.NewSelectEntry(fileOptions),
+		every:   widget.NewEntry(),
+		volume:  widget.NewSlider(0, 100),
+		minutes: widget.NewEntry(),
+	}
+	var titles []string
+	for _, m := range audio.TickModes {
+		titles = append(titles, audio.TickModeTitle(m))
+	}
+	f.mode = widget.NewSelect(titles, func(title string) {
+		if title == audio.TickModeTitle(audio.TickAlways) {
+			f.minutes.Disable()
+		} else {
+			f.minutes.Enable()
+		}
+	})
+	f.sound.SetText(soundFileText(c.Sound))
+	f.every.SetText(strconv.Itoa(max(c.Every, 1)))
+	f.volume.Step = 5
+	if c.Sound == "" && c.Volume == 0 {
+		c.Volume = 30
+	}
+	f.volume.SetValue(float64(c.Volume))
+	if c.Minutes <= 0 {
+		c.Minutes = 5
+	}
+	f.minutes.SetText(strconv.Itoa(c.Minutes))
+	f.mode.SetSelected(audio.TickModeTitle(c.Mode))
+	return f
+}
+
+// tick 返回当前编辑的设置
+func (f *tickForm) tick() config.Tick {
+	num := func(e *widget.Entry) int {
+		n, _ := strconv.Atoi(strings.TrimSpace(e.Text))
+		return max(n, 0)
+	}
+	t := config.Tick{
+		Sound:   parseSoundFile(f.sound.Text),
+		Every:   max(num(f.every), 1),
+		Volume:  int(f.volume.Value),
+		Mode:    audio.TickAlways,
+		Minutes: num(f.minutes),
+	}
+	for _, m := range audio.TickModes {
+		if audio.TickModeTitle(m) == f.mode.Selected {
+			t.Mode = m
+		}
+	}
+	return t
+}
+
+// content 返回设置对话框中的滴答声区域
+func (f *tickForm) content() fyne.CanvasObject {
+	title := widget.NewLabel("滴答声")
+	title.TextStyle = fyne.TextStyle{Bold: true}
+	f.sound.SetPlaceHolder("选择「（无）」关闭滴答声")
+	volumeLabel := widget.NewLabel(fmt.Sprintf("%d%%", int(f.volume.Value)))
+	f.volume.OnChanged = func(v float64) {
+		volumeLabel.SetText(fmt.Sprintf("%d%%", int(v)))
+	}
+	volume := container.NewGridWrap(fyne.NewSize(160, f.volume.MinSize().Height), f.volume)
+	hint := widget.NewLabel("专注时按节拍播放，暂停计时时一起暂停；提示音或正念铃声响起时自动静音让位。「最后 N 分钟」只在倒计时中有效。")
+	hint.Wrapping = fyne.TextWrapWord
+	hint.Importance = widget.LowImportance
+	return container.NewVBox(
+		title,
+		f.sound,
+		container.NewHBox(widget.NewLabel("每"), f.every, widget.NewLabel("秒一下"), widget.NewLabel("音量"), volume, volumeLabel),
+		container.NewHBox(widget.NewLabel("时段"), f.mode, f.minutes, widget.NewLabel("分钟")),
+		hint,
+	)
+}
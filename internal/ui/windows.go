@@ -5,9 +5,6 @@ import (
 	"fmt"
 	"log"
 	"math"
-	"math/rand"
-	"os"
-	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
@@ -30,90 +27,48 @@ import (
 
 // 全局饼图变量
 var pieChart24h *PieChart
+
+// pieGroupMode 24小时占比图的分组方式，见 model.GroupBy* 常量
+var pieGroupMode = model.GroupByLabel
+
+// pieGroupModes 分组方式选项（显示名 -> 分组方式）
+var pieGroupModes = []struct {
+	title string
+	mode  string
+}{
+	{"按主标签", model.GroupByLabel},
+	{"按项目", model.GroupByProject},
+	{"按标签（多标签重复计入）", model.GroupByTag},
+}
 var pieChartToday *PieChart
 var studyGoalLabel = "学习"       // 可以配置的目标标签
 var studyGoalSeconds = 8 * 3600 // 8小时目标
 
-// 全局音频播放器
-var alertPlayer *audio.AlertPlayer // 倒计时结束提示音（alert.mp3）
-var hintPlayer *audio.AlertPlayer  // 随机提示音（alarm.mp3）
-
-// 全局设置
-var (
-	muteAlerts = false // 是否静音提示音
-	// 随机提示音功能开关
-	randomHintEnabled = false
-)
-
-// 初始化音频播放器
-func initAudioPlayer(app fyne.App) {
-	log.Printf("[DEBUG] 开始初始化音频播放器")
+// goalReachedDay 今日目标已达成的日期（2006-01-02），用于每天只提示一次
+var goalReachedDay string
 
-	// 获取应用程序目录
-	dir, err := filepath.Abs(filepath.Dir("./"))
-	if err != nil {
-		log.Printf("[ERROR] 无法获取应用程序目录: %v", err)
-		return
-	}
-
-	// 设置音频文件路径（倒计时结束）
-	alertSoundPath := filepath.Join(dir, "resources", "sounds", "alert.mp3")
-	log.Printf("[DEBUG] 音频文件路径: %s", alertSoundPath)
-
-	// 检查文件是否存在
-	if _, err := os.Stat(alertSoundPath); os.IsNotExist(err) {
-		log.Printf("[ERROR] 提示音文件不存在: %s", alertSoundPath)
-		// 创建resources/sounds目录
-		soundDir := filepath.Join(dir, "resources", "sounds")
-		if err := os.MkdirAll(soundDir, 0755); err != nil {
-			log.Printf("[ERROR] 无法创建声音目录: %v", err)
+// checkGoalReached 今日专注时长首次达到目标时播放提示音；启动时已经达成的不再提示
+func checkGoalReached(now time.Time, seconds int) {
+	today := now.Format("2006-01-02")
+	first := goalReachedDay == ""
+	if seconds < studyGoalSeconds || goalReachedDay == today {
+		if first {
+			goalReachedDay = "-"
 		}
-		// 显示错误消息
-		log.Printf("[WARNING] 请在 %s 放置一个名为alert.mp3的声音文件", soundDir)
 		return
 	}
-
-	// 创建音频播放器
-	alertPlayer = audio.NewAlertPlayer(alertSoundPath)
-	log.Printf("[DEBUG] 音频播放器已创建")
-
-	// 尝试初始化音频系统
-	err = alertPlayer.Init()
-	if err != nil {
-		log.Printf("[ERROR] 无法初始化音频系统: %v", err)
-		alertPlayer = nil // 设置为nil以防止后续使用
-		return
-	}
-
-	log.Printf("[DEBUG] 音频系统初始化成功")
-
-	// 预加载提示音文件
-	err = alertPlayer.LoadSound()
-	if err != nil {
-		log.Printf("[ERROR] 加载提示音文件失败: %v", err)
-		alertPlayer = nil // 设置为nil以防止后续使用
-	} else {
-		log.Printf("[DEBUG] 提示音文件已成功加载")
-	}
-
-	// -------------- 初始化随机提示音播放器 --------------
-	alarmSoundPath := filepath.Join(dir, "resources", "sounds", "alarm.mp3")
-	log.Printf("[DEBUG] 随机提示音文件路径: %s", alarmSoundPath)
-
-	if _, err := os.Stat(alarmSoundPath); os.IsNotExist(err) {
-		log.Printf("[WARNING] 随机提示音文件不存在: %s", alarmSoundPath)
-	} else {
-		hintPlayer = audio.NewAlertPlayer(alarmSoundPath)
-		if err := hintPlayer.Init(); err != nil {
-			log.Printf("[ERROR] 初始化随机提示音播放器失败: %v", err)
-			hintPlayer = nil
-		} else if err := hintPlayer.LoadSound(); err != nil {
-			log.Printf("[ERROR] 加载随机提示音文件失败: %v", err)
-			hintPlayer = nil
-		}
+	goalReachedDay = today
+	if !first {
+		log.Printf("[DEBUG] 今日%s目标已达成", studyGoalLabel)
+		playSound(audio.EventGoalReached)
 	}
 }
 
+// 全局设置
+var (
+	muteAlerts = false // 是否静音提示音
+)
+
 // 显示编辑专注记录的弹出式表单
 func showSessionEditDialog(session model.TimerSession, w fyne.Window, updateCallback func()) {
 	log.Printf("[DEBUG] 显示编辑对话框: session.ID=%d, Mode=%s, TaskID=%v",
@@ -242,23 +197,29 @@ func showSessionEditDialog(session model.TimerSession, w fyne.Window, updateCall
 		calculateDuration()
 	}
 
+	items := []*widget.FormItem{
+		widget.NewFormItem("关联任务", taskSelect),
+		widget.NewFormItem("计时模式", modeRadio),
+		widget.NewFormItem("目标时长(分钟)", targetMinuteEntry),
+		widget.NewFormItem("开始日期", startDate),
+		widget.NewFormItem("开始时间", startTime),
+		widget.NewFormItem("结束日期", endDate),
+		widget.NewFormItem("结束时间", endTime),
+		widget.NewFormItem("持续时间(小时)", hoursEntry),
+		widget.NewFormItem("持续时间(分钟)", minutesEntry),
+	}
+	// 正念铃声只读显示，便于回顾
+	if len(session.Bells) > 0 {
+		items = append(items, widget.NewFormItem("正念铃声", widget.NewLabel(bellsText(session.Bells))))
+	}
+
 	// 创建对话框
 	log.Printf("[DEBUG] 创建表单对话框")
 	form := dialog.NewForm(
 		"编辑专注记录",
 		"保存",
 		"取消",
-		[]*widget.FormItem{
-			widget.NewFormItem("关联任务", taskSelect),
-			widget.NewFormItem("计时模式", modeRadio),
-			widget.NewFormItem("目标时长(分钟)", targetMinuteEntry),
-			widget.NewFormItem("开始日期", startDate),
-			widget.NewFormItem("开始时间", startTime),
-			widget.NewFormItem("结束日期", endDate),
-			widget.NewFormItem("结束时间", endTime),
-			widget.NewFormItem("持续时间(小时)", hoursEntry),
-			widget.NewFormItem("持续时间(分钟)", minutesEntry),
-		},
+		items,
 		func(saved bool) {
 			if !saved {
 				log.Printf("[DEBUG] 用户取消表单")
@@ -352,6 +313,7 @@ func showSessionEditDialog(session model.TimerSession, w fyne.Window, updateCall
 				EndedAt:       endedAt,
 				Interrupted:   session.Interrupted,
 				DurationSec:   durationSec,
+				Bells:         session.Bells,
 			}
 
 			log.Printf("[DEBUG] 准备更新记录: ID=%d, TaskID=%v, Mode=%s, Duration=%d, Start=%v, End=%v",
@@ -381,43 +343,98 @@ func showSessionEditDialog(session model.TimerSession, w fyne.Window, updateCall
 
 // 函数已删除
 
-// 显示计时结束通知对话框
-func showTimerCompletedDialog(w fyne.Window, callback func()) {
+// breakMinutes 倒计时结束后“开始休息”的时长
+const breakMinutes = 5
+
+// 显示计时结束通知对话框，关闭对话框时停止闹铃；onBreak 为“开始休息”的回调，
+// snooze 大于 0 时提供“贪睡”按钮，点击后调用 onSnooze 在 snooze 之后再次提醒
+func showTimerCompletedDialog(w fyne.Window, snooze time.Duration, onBreak, onSnooze func()) {
 	log.Printf("[DEBUG] 显示计时完成对话框")
 
-	confirmDialog := dialog.NewCustomConfirm(
-		"番茄钟完成",
-		"确认",
-		"停止提示音",
-		widget.NewLabel("倒计时已结束！"),
-		func(confirmed bool) {
-			if confirmed {
-				// "确认"按钮被点击
-				log.Printf("[DEBUG] 用户点击了确认按钮")
-			} else {
-				// "停止提示音"按钮被点击
-				log.Printf("[DEBUG] 用户点击了停止提示音按钮，正在停止提示音...")
-				if alertPlayer != nil {
-					alertPlayer.Stop()
-					log.Printf("[DEBUG] 提示音已停止")
-				} else {
-					log.Printf("[DEBUG] alertPlayer为空，无法停止提示音")
-				}
+	d := dialog.NewCustomWithoutButtons("番茄钟完成", widget.NewLabel("倒计时已结束！"), w)
+	closeWith := func(next func()) func() {
+		return func() {
+			log.Printf("[DEBUG] 关闭计时完成对话框，正在停止提示音...")
+			d.Hide()
+			stopAlarm()
+			if next != nil {
+				next()
 			}
+		}
+	}
+	breakBtn := widget.NewButton(fmt.Sprintf("休息 %d 分钟", breakMinutes), closeWith(onBreak))
+	breakBtn.Importance = widget.HighImportance
+	buttons := []fyne.CanvasObject{widget.NewButton("关闭", closeWith(nil))}
+	if snooze > 0 && onSnooze != nil {
+		buttons = append(buttons, widget.NewButton(fmt.Sprintf("贪睡 %d 分钟", int(snooze/time.Minute)), closeWith(onSnooze)))
+	}
+	d.SetButtons(append(buttons, breakBtn))
+	d.Show()
+}
 
-			if callback != nil {
-				callback()
-			}
-		},
-		w,
-	)
-	confirmDialog.Show()
+// parseEstimate 解析预估番茄数输入，空字符串表示未预估
+func parseEstimate(s string) (int, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return 0, errors.New("预估番茄数必须是非负整数")
+	}
+	return n, nil
+}
+
+// taskTitleWithBadge 在任务标题前附加优先级标记，标题后附加 "实际/预估 🍅" 进度、重复规则、截止日期与备注标记
+func taskTitleWithBadge(t model.Task, f model.TaskFocus) string {
+	title := t.Title
+	if t.Priority > model.PriorityNone {
+		title = strings.Repeat("!", t.Priority) + " " + title
+	}
+	if t.EstimatePomodoros > 0 {
+		title = fmt.Sprintf("%s  %d/%d 🍅", title, f.Pomodoros, t.EstimatePomodoros)
+	} else if f.Pomodoros > 0 {
+		title = fmt.Sprintf("%s  %d 🍅", title, f.Pomodoros)
+	}
+	if spec, err := model.ParseRepeatRule(t.RepeatRule); err == nil && spec.IsRepeating() {
+		title += "  🔁" + spec.Describe()
+	}
+	if due := dueBadge(t, time.Now()); due != "" {
+		title += "  " + due
+	}
+	if note := noteBadge(t.Note); note != "" {
+		title += "  " + note
+	}
+	return title
+}
+
+// showEstimateReportDialog 显示已完成任务的预估准确度报告
+func showEstimateReportDialog(w fyne.Window) {
+	report := model.EstimateAccuracyReport()
+	if report.Total.Tasks == 0 {
+		dialog.ShowInformation("预估准确度", "暂无已完成且设置了预估的任务", w)
+		return
+	}
+
+	describe := func(a model.EstimateAccuracy) string {
+		return fmt.Sprintf("%s: %d个任务, 预估%d🍅 / 实际%d🍅 (%.0f%%), 低估%d 高估%d 准确%d",
+			a.Label, a.Tasks, a.EstimatedPomodoros, a.ActualPomodoros, a.Ratio*100,
+			a.Overran, a.Underran, a.Exact)
+	}
+
+	var lines []string
+	for _, a := range report.ByLabel {
+		lines = append(lines, describe(a))
+	}
+	lines = append(lines, "", describe(report.Total))
+
+	dialog.ShowInformation("预估准确度", strings.Join(lines, "\n"), w)
 }
 
 // updatePieCharts 更新两个饼图的数据
 func updatePieCharts() {
-	// 1. 更新24小时专注占比图
-	durations := model.Last24HoursFocusTimeByLabel()
+	// 1. 更新24小时专注占比图（按所选分组方式）
+	durations := model.Last24HoursFocusTimeByGroup(pieGroupMode)
 	var segments24h []PieChartSegment
 	for label, sec := range durations {
 		if sec > 0 {
@@ -455,6 +472,7 @@ func updatePieCharts() {
 
 	completed := float64(todayStudySec)
 	remaining := math.Max(0, float64(studyGoalSeconds)-completed)
+	checkGoalReached(now, todayStudySec)
 
 	segmentsToday := []PieChartSegment{
 		{Label: "已完成", Value: completed},
@@ -481,16 +499,36 @@ func createPieChartsPanel() fyne.CanvasObject {
 	// 立即进行一次初始更新
 	updatePieCharts()
 
-	grid := container.NewGridWithColumns(2, pieChart24h, pieChartToday)
+	var titles []string
+	for _, g := range pieGroupModes {
+		titles = append(titles, g.title)
+	}
+	groupSelect := widget.NewSelect(titles, func(s string) {
+		for _, g := range pieGroupModes {
+			if g.title == s {
+				pieGroupMode = g.mode
+			}
+		}
+		updatePieCharts()
+	})
+	groupSelect.SetSelected(titles[0])
+
+	grid := container.NewGridWithColumns(2,
+		container.NewBorder(groupSelect, nil, nil, nil, pieChart24h),
+		pieChartToday)
 	return grid
 }
 
 func NewMainWindow(app fyne.App) fyne.Window {
 	w := app.NewWindow("Tomato Clock")
 
-	// 初始化音频播放器
-	initAudioPlayer(app)
+	// 按声音方案加载提示音，无法加载时在窗口显示后提示
+	audioErr := loadSounds()
 
+	// 启动时先处理跨期的重复任务
+	if _, err := model.RollOverRecurring(time.Now()); err != nil {
+		log.Printf("[ERROR] 处理重复任务失败: %v", err)
+	}
 	tasks, err := model.ListTasks()
 	if err != nil {
 		log.Printf("加载任务失败: %v", err)
@@ -498,16 +536,100 @@ func NewMainWindow(app fyne.App) fyne.Window {
 
 	// 未来可保存所选任务以记录计时
 	var selectedTask *model.Task
+	// 各任务的实际番茄数与专注时长，在 updateHistory 中刷新
+	taskFocus := model.TaskFocusStats()
 
 	var updateHistory func()
 	var updateStats func()
+	var showHistoryDay func(day time.Time)
+
+	// 任务按父子关系组织为树，taskIndex/taskChildren 在 reloadTasks 中重建；
+	// taskChildren 只包含按当前排序与筛选条件需要显示的任务
+	var taskTree *widget.Tree
+	var taskIndex map[int64]int
+	var taskByID map[int64]model.Task
+	var taskChildren map[int64][]int64
+	var viewBar *taskViewBar
+	rebuildTaskIndex := func() {
+		var lastFocus map[int64]time.Time
+		if viewBar.view.SortMode == model.SortRecentFocus {
+			lastFocus = model.LastFocusTimes()
+		}
+		model.SortTasks(tasks, viewBar.view.SortMode, lastFocus)
+		taskIndex = make(map[int64]int, len(tasks))
+		taskByID = make(map[int64]model.Task, len(tasks))
+		for i, t := range tasks {
+			taskIndex[t.ID] = i
+			taskByID[t.ID] = t
+		}
+		visible := model.FilterTasks(tasks, viewBar.filter(), time.Now())
+		var shown []model.Task
+		for _, t := range tasks {
+			if visible[t.ID] && !t.Archived {
+				shown = append(shown, t)
+			}
+		}
+		taskChildren = model.ChildIndex(shown)
+		// 已完成的顶层任务（连同其子任务）移到末尾可折叠的“已完成”分组
+		var open, done []int64
+		for _, id := range taskChildren[0] {
+			if tasks[taskIndex[id]].IsDone {
+				done = append(done, id)
+			} else {
+				open = append(open, id)
+			}
+		}
+		taskChildren[0] = open
+		if len(done) > 0 {
+			taskChildren[0] = append(open, doneSectionKey)
+			taskChildren[doneSectionKey] = done
+		}
+	}
+	var reloadTasks func()
+	// 任务详情面板显示所选任务的备注，备注修改后刷新任务列表中的标记
+	notes := newNotePane(w, func() { reloadTasks() })
+	viewBar = newTaskViewBar(func() { reloadTasks() })
+	if archiveCompleted(viewBar.view.ArchiveAfterDays) {
+		tasks, _ = model.ListTasks()
+	}
+	rebuildTaskIndex()
+	reloadTasks = func() {
+		tasks, _ = model.ListTasks()
+		rebuildTaskIndex()
+		viewBar.refreshOptions()
+		if selectedTask != nil {
+			if i, ok := taskIndex[selectedTask.ID]; ok {
+				selectedTask = &tasks[i]
+			} else {
+				selectedTask = nil
+				taskTree.UnselectAll()
+			}
+		}
+		notes.show(selectedTask)
+		taskTree.Refresh()
+	}
 
-	var list *widget.List
-	list = widget.NewList(
-		func() int { return len(tasks) },
-		func() fyne.CanvasObject {
+	// showNewTaskDialog 新建任务，parentID 非 0 时默认作为该任务的子任务
+	var showNewTaskDialog func(parentID int64)
+
+	taskTree = widget.NewTree(
+		func(uid widget.TreeNodeID) []widget.TreeNodeID {
+			var ids []widget.TreeNodeID
+			for _, id := range taskChildren[parseTaskNodeID(uid)] {
+				ids = append(ids, taskNodeID(id))
+			}
+			return ids
+		},
+		func(uid widget.TreeNodeID) bool {
+			return uid == "" || len(taskChildren[parseTaskNodeID(uid)]) > 0
+		},
+		func(branch bool) fyne.CanvasObject {
+			handle := newDragHandle()
 			chk := widget.NewCheck("", nil)
 			lbl := widget.NewLabel("title")
+			sub := widget.NewButtonWithIcon("", theme.ContentAddIcon(), nil)
+			sub.Importance = widget.LowImportance
+			sub.Resize(fyne.NewSize(24, 24))
 			edit := widget.NewButtonWithIcon("", theme.DocumentCreateIcon(), nil)
 			edit.Importance = widget.LowImportance
 			edit.Resize(fyne.NewSize(24, 24))
@@ -516,48 +638,174 @@ func NewMainWindow(app fyne.App) fyne.Window {
 			del.Resize(fyne.NewSize(24, 24))
 			colorRect := canvas.NewRectangle(ColorForLabel(""))
 			colorRect.SetMinSize(fyne.NewSize(10, 10))
-			return container.NewHBox(chk, lbl, layout.NewSpacer(), edit, del, colorRect)
+			return container.NewHBox(handle, chk, lbl, layout.NewSpacer(), sub, edit, del, colorRect)
 		},
-		func(i widget.ListItemID, o fyne.CanvasObject) {
+		func(uid widget.TreeNodeID, branch bool, o fyne.CanvasObject) {
 			h := o.(*fyne.Container)
-			chk := h.Objects[0].(*widget.Check)
-			lbl := h.Objects[1].(*widget.Label)
-			editBtn := h.Objects[3].(*widget.Button)
-			delBtn := h.Objects[4].(*widget.Button)
-			colorRect := h.Objects[5].(*canvas.Rectangle)
-			if i < 0 || i >= len(tasks) {
+			handle := h.Objects[0].(*dragHandle)
+			chk := h.Objects[1].(*widget.Check)
+			lbl := h.Objects[2].(*widget.Label)
+			subBtn := h.Objects[4].(*widget.Button)
+			editBtn := h.Objects[5].(*widget.Button)
+			delBtn := h.Objects[6].(*widget.Button)
+			colorRect := h.Objects[7].(*canvas.Rectangle)
+
+			// “已完成”分组行只显示标题
+			section := parseTaskNodeID(uid) == doneSectionKey
+			for _, obj := range []fyne.CanvasObject{chk, subBtn, editBtn, delBtn, colorRect} {
+				if section {
+					obj.Hide()
+				} else {
+					obj.Show()
+				}
+			}
+			if section {
+				handle.Hide()
+				lbl.TextStyle = fyne.TextStyle{Bold: true}
+				lbl.Importance = widget.MediumImportance
+				lbl.SetText(fmt.Sprintf("已完成 (%d)", len(taskChildren[doneSectionKey])))
+				return
+			}
+			lbl.TextStyle = fyne.TextStyle{}
+
+			i, ok := taskIndex[parseTaskNodeID(uid)]
+			if !ok {
 				return
 			}
 			t := tasks[i]
+
+			// 只有手动排序时才能拖动，按拖动距离换算成移动的行数，在同级任务间调整顺序
+			if viewBar.manualOrder() {
+				handle.Show()
+			} else {
+				handle.Hide()
+			}
+			handle.OnDragEnd = func(dy float32) {
+				rowHeight := o.Size().Height + theme.Padding()
+				steps := int(math.Round(float64(dy / rowHeight)))
+				if steps == 0 || !viewBar.manualOrder() {
+					return
+				}
+				order := moveInList(siblingsOf(taskChildren, t.ID), t.ID, steps)
+				if err := model.SetTaskOrder(order); err != nil {
+					dialog.ShowError(err, w)
+					return
+				}
+				reloadTasks()
+			}
+			// 勾选框通过 model 保存完成状态与完成时间；先清除回调，避免 SetChecked 触发旧任务的回调
+			chk.OnChanged = nil
 			chk.SetChecked(t.IsDone)
-			lbl.SetText(t.Title)
+			chk.OnChanged = func(done bool) {
+				if done == t.IsDone {
+					return
+				}
+				if err := model.SetTaskDone(t.ID, done); err != nil {
+					dialog.ShowError(err, w)
+					return
+				}
+				reloadTasks()
+				if updateStats != nil {
+					updateStats()
+				}
+			}
+			// 逾期任务标题显示为警示色，已完成与被前置任务阻塞的任务显示为灰色，并注明在等待哪些任务
+			var blockers []model.Task
+			if !t.IsDone {
+				blockers = model.Blockers(t, taskByID)
+			}
+			lbl.Importance = widget.MediumImportance
+			if len(blockers) > 0 || t.IsDone {
+				lbl.Importance = widget.LowImportance
+			} else if model.IsOverdue(t, time.Now()) {
+				lbl.Importance = widget.DangerImportance
+			}
+			title := taskTitleWithBadge(t, taskFocus[t.ID])
+			if badge := blockedBadge(blockers); badge != "" {
+				title += "  " + badge
+			}
+			lbl.SetText(title)
 			colorRect.FillColor = ColorForLabel(t.Label)
 			colorRect.Refresh()
 
+			// 添加子任务按钮
+			subBtn.OnTapped = func() {
+				showNewTaskDialog(t.ID)
+			}
+
 			// 编辑任务按钮
 			editBtn.OnTapped = func() {
 				titleEntry := widget.NewEntry()
 				titleEntry.SetText(t.Title)
-				labelEntry := widget.NewEntry()
-				labelEntry.SetText(t.Label)
-				dialog.ShowForm("编辑任务", "保存", "取消",
-					[]*widget.FormItem{
-						widget.NewFormItem("标题", titleEntry),
-						widget.NewFormItem("标签", labelEntry),
-					},
+				tagEdit := newTagEditor(t.Tags, model.Tags())
+				projectSel := newProjectPicker(w, t.ProjectID)
+				estimateEntry := widget.NewEntry()
+				if t.EstimatePomodoros > 0 {
+					estimateEntry.SetText(strconv.Itoa(t.EstimatePomodoros))
+				}
+				estimateEntry.SetPlaceHolder("可选，如：4")
+				prioritySel := newPrioritySelect(t.Priority)
+				parentNames, parentIDs := parentOptions(tasks, t.ID)
+				parentSelect := widget.NewSelect(parentNames, nil)
+				parentSelect.SetSelected(parentOptionName(parentIDs, t.ParentID))
+				repeat := newRepeatPicker(t)
+				due := newDueDatePicker(t)
+				blockerSel := newBlockerPicker(tasks, t)
+				noteEntry := newNoteEntry(t.Note)
+				items := []*widget.FormItem{
+					widget.NewFormItem("标题", titleEntry),
+					widget.NewFormItem("项目", projectSel.sel),
+					widget.NewFormItem("标签", tagEdit.content),
+					widget.NewFormItem("优先级", prioritySel),
+					widget.NewFormItem("父任务", parentSelect),
+					widget.NewFormItem("前置任务", blockerSel.content),
+					widget.NewFormItem("预估番茄", estimateEntry),
+					widget.NewFormItem("备注", noteEntry),
+				}
+				items = append(items, due.formItems()...)
+				items = append(items, repeat.formItems()...)
+				if hist := repeatHistoryText(t); hist != "" {
+					items = append(items, widget.NewFormItem("完成记录", widget.NewLabel(hist)))
+				}
+				dialog.ShowForm("编辑任务", "保存", "取消", items,
 					func(confirm bool) {
 						if !confirm || titleEntry.Text == "" {
 							return
 						}
+						estimate, err := parseEstimate(estimateEntry.Text)
+						if err != nil {
+							dialog.ShowError(err, w)
+							return
+						}
 						updated := t
 						updated.Title = titleEntry.Text
-						updated.Label = labelEntry.Text
+						updated.ProjectID = projectSel.ProjectID()
+						tagEdit.apply(&updated)
+						updated.ParentID = parentIDs[parentSelect.Selected]
+						updated.EstimatePomodoros = estimate
+						updated.Priority = selectedPriority(prioritySel)
+						blockerSel.apply(&updated)
+						updated.Note = noteEntry.Text
+						if err := due.apply(&updated); err != nil {
+							dialog.ShowError(err, w)
+							return
+						}
+						if err := repeat.apply(&updated); err != nil {
+							dialog.ShowError(err, w)
+							return
+						}
 						if err := model.UpdateTask(updated); err != nil {
 							dialog.ShowError(err, w)
 							return
 						}
-						tasks, _ = model.ListTasks()
-						list.Refresh()
+						// 新规则立即确定当前这一期
+						if _, err := model.RollOverRecurring(time.Now()); err != nil {
+							log.Printf("[ERROR] 处理重复任务失败: %v", err)
+						}
+						reloadTasks()
+						if updated.ParentID != 0 {
+							taskTree.OpenBranch(taskNodeID(updated.ParentID))
+						}
 						if updateHistory != nil {
 							updateHistory()
 						}
@@ -565,105 +813,152 @@ func NewMainWindow(app fyne.App) fyne.Window {
 			}
 
 			delBtn.OnTapped = func() {
-				dialog.ShowConfirm("确认删除", fmt.Sprintf("删除任务 '%s'?", t.Title), func(ok bool) {
-					if !ok {
-						return
-					}
-					if err := model.DeleteTask(t.ID); err != nil {
-						dialog.ShowError(err, w)
-						return
-					}
-					tasks, _ = model.ListTasks()
-					list.Refresh()
+				showDeleteTaskDialog(w, t, tasks, func() {
+					reloadTasks()
 					updateHistory()
-				}, w)
+				})
 			}
 		},
 	)
 
-	list.OnSelected = func(id widget.ListItemID) {
-		if id >= 0 && id < len(tasks) {
-			selectedTask = &tasks[id]
+	// 每分钟检查一次重复任务是否进入新的一期（如跨过午夜），并归档完成较久的任务
+	var events *eventsPanel
+	go func() {
+		lastDay := time.Now().YearDay()
+		for now := range time.Tick(time.Minute) {
+			n, err := model.RollOverRecurring(now)
+			if err != nil {
+				log.Printf("[ERROR] 处理重复任务失败: %v", err)
+			}
+			newDay := now.YearDay() != lastDay
+			lastDay = now.YearDay()
+			runOnMain(func() {
+				if archiveCompleted(viewBar.view.ArchiveAfterDays) || n > 0 {
+					reloadTasks()
+				}
+				// 跨过午夜后倒数日的天数随之变化
+				if newDay && events != nil {
+					events.refresh()
+					updateStats()
+				}
+			})
+		}
+	}()
+
+	// 截止提醒与每日到期汇总
+	startReminderScheduler(app, w, reloadTasks)
+
+	// 父任务与子任务都可以被选中并开始计时
+	taskTree.OnSelected = func(uid widget.TreeNodeID) {
+		if i, ok := taskIndex[parseTaskNodeID(uid)]; ok {
+			selectedTask = &tasks[i]
+			notes.show(selectedTask)
+		}
+	}
+	taskTree.OnUnselected = func(uid widget.TreeNodeID) {
+		if selectedTask != nil && taskNodeID(selectedTask.ID) == uid {
+			selectedTask = nil
+			notes.show(nil)
 		}
 	}
 
-	addBtn := widget.NewButtonWithIcon("", theme.ContentAddIcon(), func() {
+	showNewTaskDialog = func(parentID int64) {
 		titleEntry := widget.NewEntry()
 		titleEntry.SetPlaceHolder("任务标题")
-		labelEntry := widget.NewEntry()
-		labelEntry.SetText("学习")
-		labelEntry.SetPlaceHolder("任务标签，如：学习/娱乐...")
-		dialog.ShowForm("新建任务", "创建", "取消",
-			[]*widget.FormItem{
-				widget.NewFormItem("标题", titleEntry),
-				widget.NewFormItem("标签", labelEntry),
-			},
+		// 子任务默认沿用父任务的标签与项目
+		defaultTags, defaultProject := []string{"学习"}, int64(0)
+		if i, ok := taskIndex[parentID]; ok {
+			defaultTags, defaultProject = tasks[i].Tags, tasks[i].ProjectID
+		}
+		tagEdit := newTagEditor(defaultTags, model.Tags())
+		projectSel := newProjectPicker(w, defaultProject)
+		estimateEntry := widget.NewEntry()
+		estimateEntry.SetPlaceHolder("可选，如：4")
+		prioritySel := newPrioritySelect(model.PriorityNone)
+		parentNames, parentIDs := parentOptions(tasks, 0)
+		parentSelect := widget.NewSelect(parentNames, nil)
+		parentSelect.SetSelected(parentOptionName(parentIDs, parentID))
+		repeat := newRepeatPicker(model.Task{RepeatRule: model.RepeatNone})
+		due := newDueDatePicker(model.Task{})
+		blockerSel := newBlockerPicker(tasks, model.Task{})
+		noteEntry := newNoteEntry("")
+		items := []*widget.FormItem{
+			widget.NewFormItem("标题", titleEntry),
+			widget.NewFormItem("项目", projectSel.sel),
+			widget.NewFormItem("标签", tagEdit.content),
+			widget.NewFormItem("优先级", prioritySel),
+			widget.NewFormItem("父任务", parentSelect),
+			widget.NewFormItem("前置任务", blockerSel.content),
+			widget.NewFormItem("预估番茄", estimateEntry),
+			widget.NewFormItem("备注", noteEntry),
+		}
+		title := "新建任务"
+		if parentID != 0 {
+			title = "新建子任务"
+		}
+		items = append(items, due.formItems()...)
+		dialog.ShowForm(title, "创建", "取消", append(items, repeat.formItems()...),
 			func(confirm bool) {
 				if !confirm || titleEntry.Text == "" {
 					return
 				}
+				estimate, err := parseEstimate(estimateEntry.Text)
+				if err != nil {
+					dialog.ShowError(err, w)
+					return
+				}
 				task := &model.Task{
-					Title:      titleEntry.Text,
-					IsDone:     false,
-					RepeatRule: model.RepeatNone,
-					Label:      labelEntry.Text,
+					Title:             titleEntry.Text,
+					IsDone:            false,
+					RepeatRule:        model.RepeatNone,
+					ProjectID:         projectSel.ProjectID(),
+					ParentID:          parentIDs[parentSelect.Selected],
+					EstimatePomodoros: estimate,
+					Priority:          selectedPriority(prioritySel),
+					Note:              noteEntry.Text,
+				}
+				tagEdit.apply(task)
+				blockerSel.apply(task)
+				if err := due.apply(task); err != nil {
+					dialog.ShowError(err, w)
+					return
+				}
+				if err := repeat.apply(task); err != nil {
+					dialog.ShowError(err, w)
+					return
 				}
 				if err := model.CreateTask(task); err != nil {
 					dialog.ShowError(err, w)
 					return
 				}
-				tasks, _ = model.ListTasks()
-				list.Refresh()
+				if _, err := model.RollOverRecurring(time.Now()); err != nil {
+					log.Printf("[ERROR] 处理重复任务失败: %v", err)
+				}
+				reloadTasks()
+				if task.ParentID != 0 {
+					taskTree.OpenBranch(taskNodeID(task.ParentID))
+				}
 				if updateHistory != nil {
 					updateHistory()
 				}
 			}, w)
+	}
+
+	addBtn := widget.NewButtonWithIcon("", theme.ContentAddIcon(), func() {
+		showNewTaskDialog(0)
 	})
 	addBtn.Importance = widget.LowImportance
 	// 使用 24x24 尺寸以保持与输入框同高
 	addBtn.Resize(fyne.NewSize(24, 24))
 
-	// 新建倒数日按钮
+	// 新建倒数日按钮，倒数日独立于任务保存，在左下方的“倒数日”面板中管理
 	countdownBtn := widget.NewButtonWithIcon("", theme.DocumentCreateIcon(), func() {
-		titleEntry := widget.NewEntry()
-		titleEntry.SetPlaceHolder("事件标题")
-		dateEntry := widget.NewEntry()
-		dateEntry.SetPlaceHolder("YYYY-MM-DD")
-		labelEntry := widget.NewEntry()
-		labelEntry.SetText("重要")
-		labelEntry.SetPlaceHolder("标签，可选")
-		dialog.ShowForm("新建倒数日", "创建", "取消",
-			[]*widget.FormItem{
-				widget.NewFormItem("标题", titleEntry),
-				widget.NewFormItem("日期", dateEntry),
-				widget.NewFormItem("标签", labelEntry),
-			},
-			func(confirm bool) {
-				if !confirm || titleEntry.Text == "" || dateEntry.Text == "" {
-					return
-				}
-				due, err := time.Parse("2006-01-02", dateEntry.Text)
-				if err != nil {
-					dialog.ShowError(err, w)
-					return
-				}
-				task := &model.Task{
-					Title:      titleEntry.Text,
-					IsDone:     false,
-					RepeatRule: model.RepeatNone,
-					Label:      labelEntry.Text,
-					DueDate:    &due,
-				}
-				if err := model.CreateTask(task); err != nil {
-					dialog.ShowError(err, w)
-					return
-				}
-				tasks, _ = model.ListTasks()
-				list.Refresh()
-				if updateStats != nil {
-					updateStats()
-				}
-			}, w)
+		showEventDialog(w, nil, func() {
+			events.refresh()
+			if updateStats != nil {
+				updateStats()
+			}
+		})
 	})
 	countdownBtn.Importance = widget.LowImportance
 	countdownBtn.Resize(fyne.NewSize(24, 24))
@@ -686,11 +981,30 @@ func NewMainWindow(app fyne.App) fyne.Window {
 	clearBtn.Importance = widget.LowImportance
 	clearBtn.Resize(fyne.NewSize(24, 24))
 
-	// 将三个小按钮包裹为固定24×24大小，保证与输入框在同一水平线
+	// 预估准确度按钮
+	estimateBtn := widget.NewButtonWithIcon("", theme.InfoIcon(), func() {
+		showEstimateReportDialog(w)
+	})
+	estimateBtn.Importance = widget.LowImportance
+	estimateBtn.Resize(fyne.NewSize(24, 24))
+
+	// 热力图按钮，点击某天时在历史记录中只显示当天
+	heatmapBtn := widget.NewButtonWithIcon("", theme.GridIcon(), func() {
+		showHeatmapWindow(app, func(day time.Time) {
+			showHistoryDay(day)
+			w.RequestFocus()
+		})
+	})
+	heatmapBtn.Importance = widget.LowImportance
+	heatmapBtn.Resize(fyne.NewSize(24, 24))
+
+	// 将小按钮包裹为固定24×24大小，保证与输入框在同一水平线
 	gridAdd := container.NewGridWrap(fyne.NewSize(24, 24), addBtn)
 	gridCountdown := container.NewGridWrap(fyne.NewSize(24, 24), countdownBtn)
 	gridClear := container.NewGridWrap(fyne.NewSize(24, 24), clearBtn)
-	smallBtns := container.NewHBox(gridAdd, gridCountdown, gridClear)
+	gridEstimate := container.NewGridWrap(fyne.NewSize(24, 24), estimateBtn)
+	gridHeatmap := container.NewGridWrap(fyne.NewSize(24, 24), heatmapBtn)
+	smallBtns := container.NewHBox(gridAdd, gridCountdown, gridClear, gridEstimate, gridHeatmap)
 
 	// 实时系统时间标签
 	clockLabel := widget.NewLabel("")
@@ -705,6 +1019,8 @@ func NewMainWindow(app fyne.App) fyne.Window {
 	var sessions []model.TimerSession
 	var taskTitleMap map[int64]string
 	var taskLabelMap map[int64]string
+	// historyDay 非零时历史记录只显示这一天（由热力图点击设置）
+	var historyDay time.Time
 
 	// 双击相关状态
 	var lastClickID widget.ListItemID = -1
@@ -780,6 +1096,9 @@ func NewMainWindow(app fyne.App) fyne.Window {
 
 			displayText := fmt.Sprintf("%s | %s | %s | %s",
 				s.EndedAt.Format("01-02 15:04"), taskTitle, durationStr, modeStr)
+			if len(s.Bells) > 0 {
+				displayText += fmt.Sprintf(" | 铃声 %d 次", len(s.Bells))
+			}
 
 			if i < 5 { // 只记录前几项，避免日志太多
 				log.Printf("[DEBUG] 渲染列表项 #%d: ID=%d, Text=%s", i, s.ID, displayText)
@@ -856,18 +1175,36 @@ func NewMainWindow(app fyne.App) fyne.Window {
 	updateHistory = func() {
 		log.Printf("[DEBUG] 开始更新历史记录列表")
 		sessions = model.CompletedSessions()
+		if !historyDay.IsZero() {
+			var dayList []model.TimerSession
+			for _, s := range sessions {
+				if model.SessionOverlap(s, historyDay, historyDay.AddDate(0, 0, 1)) > 0 {
+					dayList = append(dayList, s)
+				}
+			}
+			sessions = dayList
+		}
 		log.Printf("[DEBUG] 加载了%d条完成的专注记录", len(sessions))
 
 		// build task title map
 		taskTitleMap = map[int64]string{}
 		taskLabelMap = map[int64]string{}
 		allTasks, _ := model.ListTasks()
+		taskByID := make(map[int64]model.Task, len(allTasks))
+		for _, t := range allTasks {
+			taskByID[t.ID] = t
+		}
 		for _, t := range allTasks {
-			taskTitleMap[t.ID] = t.Title
+			// 子任务显示为 "父任务 / 子任务"
+			taskTitleMap[t.ID] = model.TaskPathTitle(taskByID, t.ID)
 			taskLabelMap[t.ID] = t.Label
 		}
 		model.PrintSessionsSummary() // 同步输出到终端
 
+		// 刷新任务列表中的番茄进度（按最近专注排序时顺序也会变化）
+		taskFocus = model.TaskFocusStats()
+		reloadTasks()
+
 		log.Printf("[DEBUG] 刷新列表显示")
 		sessionList.Refresh()
 		// 更新顶部统计信息
@@ -876,6 +1213,31 @@ func NewMainWindow(app fyne.App) fyne.Window {
 		}
 	}
 
+	// 按日期筛选历史记录的提示栏
+	historyFilterLabel := widget.NewLabel("")
+	historyFilterLabel.Wrapping = fyne.TextWrapWord
+	historyFilterBar := container.NewBorder(nil, nil, nil,
+		widget.NewButton("显示全部", func() { showHistoryDay(time.Time{}) }),
+		historyFilterLabel)
+	historyFilterBar.Hide()
+
+	showHistoryDay = func(day time.Time) {
+		historyDay = day
+		if day.IsZero() {
+			historyFilterBar.Hide()
+		} else {
+			text := fmt.Sprintf("仅显示 %s 的专注记录", day.Format("2006-01-02"))
+			if done := completedOnText(day); done != "" {
+				text += "；" + done
+			}
+			historyFilterLabel.SetText(text)
+			historyFilterBar.Show()
+		}
+		sessionList.UnselectAll()
+		sessionList.ScrollToTop()
+		updateHistory()
+	}
+
 	// 初始化一次
 	updateHistory()
 
@@ -892,28 +1254,72 @@ func NewMainWindow(app fyne.App) fyne.Window {
 	var currentSessionID int64
 	var timerLabel = widget.NewLabel("")
 	timerLabel.Hide()
-	var stopBtn *widget.Button
+	var stopBtn, pauseBtn *widget.Button
+	// resetPause 计时结束后恢复暂停按钮
+	resetPause := func() {
+		pauseBtn.SetText("暂停")
+		pauseBtn.SetIcon(theme.MediaPauseIcon())
+		pauseBtn.Disable()
+	}
 
-	// 随机提示音取消通道
+	// 正念铃声调度的取消通道
 	var randomHintCancel chan struct{}
 
-	startBtn := widget.NewButtonWithIcon("开始", theme.MediaPlayIcon(), func() {
-		if runningTimer != nil {
-			return // already running
-		}
-		var mode string
-		if modeRadio.Selected == "倒计时" {
-			mode = logic.ModeCountDown
-		} else {
-			mode = logic.ModeCountUp
+	// 休息不计入专注记录，只在结束时播放提示音并发送通知；开始新的计时会取消休息
+	var breakCancel chan struct{}
+	cancelBreak := func() {
+		if breakCancel != nil {
+			close(breakCancel)
+			breakCancel = nil
 		}
+	}
+	startBreak := func() {
+		cancelBreak()
+		cancelSnooze()
+		cancel := make(chan struct{})
+		breakCancel = cancel
+		end := time.Now().Add(breakMinutes * time.Minute)
+		timerLabel.SetText("休息至 " + end.Format("15:04"))
+		timerLabel.Show()
+		go func() {
+			select {
+			case <-time.After(time.Until(end)):
+				runOnMain(func() {
+					if breakCancel != cancel {
+						return
+					}
+					breakCancel = nil
+					timerLabel.Hide()
+					playSound(audio.EventBreakEnd)
+					app.SendNotification(fyne.NewNotification("休息结束", "可以开始下一个番茄了"))
+				})
+			case <-cancel:
+			}
+		}()
+	}
 
-		mins, _ := strconv.Atoi(minuteEntry.Text)
-		secs := mins * 60
-		if mode == logic.ModeCountDown && secs <= 0 {
-			dialog.ShowError(errors.New("请输入大于0的分钟数"), w)
-			return
+	// ringCountdownEnd 倒计时结束或贪睡到时播放闹铃并显示提示框；
+	// 贪睡只在到时后再次提醒，不开始计时，也不产生专注记录
+	var ringCountdownEnd func()
+	ringCountdownEnd = func() {
+		timerLabel.Hide()
+		profile := ringAlarm()
+		showTimerCompletedDialog(w, profile.Snooze, startBreak, func() {
+			log.Printf("[DEBUG] 贪睡 %v 后再次提醒", profile.Snooze)
+			timerLabel.SetText("贪睡至 " + time.Now().Add(profile.Snooze).Format("15:04"))
+			timerLabel.Show()
+			snoozeAlarm(profile.Snooze, ringCountdownEnd)
+		})
+	}
+
+	// startSession 按模式与目标秒数开始计时
+	startSession := func(mode string, secs int) {
+		if runningTimer != nil {
+			return // already running
 		}
+		cancelBreak()
+		cancelSnooze()
+		stopAlarm()
 
 		var taskIDPtr *int64
 		if selectedTask != nil {
@@ -932,59 +1338,22 @@ func NewMainWindow(app fyne.App) fyne.Window {
 		// show timer label & stop button
 		timerLabel.Show()
 		stopBtn.Enable()
+		pauseBtn.Enable()
+		playSound(audio.EventSessionStart)
+		startAmbience()
 
 		currentSessionID = sessionID
 
-		// 如果启用随机提示音功能，为本次计时创建调度
-		if randomHintEnabled {
-			randomHintCancel = make(chan struct{})
-			log.Printf("[RANDOM] 随机提示音调度已启动，模式=%s，目标时长=%d秒", mode, secs)
-			go func(t *logic.Timer, cancelCh <-chan struct{}) {
-				r := rand.New(rand.NewSource(time.Now().UnixNano()))
-				for {
-					// 生成5-10分钟的随机间隔
-					delayMin := r.Intn(6) + 5 // [5,10]
-					delay := time.Duration(delayMin) * time.Minute
-					log.Printf("[RANDOM] 下一次随机提示音将在 %d 分钟后触发", delayMin)
-					select {
-					case <-time.After(delay):
-						// 检查是否被取消
-						select {
-						case <-cancelCh:
-							log.Printf("[RANDOM] 收到取消信号，随机提示音调度结束")
-							return
-						default:
-						}
-
-						// 倒计时模式下，若已不足10分钟则不再播放，直接退出循环
-						if t.Mode == logic.ModeCountDown {
-							remain := t.TargetSeconds - t.ElapsedSeconds()
-							if remain <= 600 {
-								log.Printf("[RANDOM] 剩余时间 %d 秒 ≤ 600，跳过并结束随机提示音调度", remain)
-								return
-							}
-						}
-
-						if !muteAlerts && hintPlayer != nil {
-							if err := hintPlayer.PlayFor(10 * time.Second); err != nil {
-								log.Printf("[ERROR] 随机提示音播放失败: %v", err)
-							}
-						}
-
-						log.Printf("[RANDOM] 已播放随机提示音 10 秒片段")
-
-					case <-cancelCh:
-						log.Printf("[RANDOM] 收到取消信号，随机提示音调度结束")
-						return
-					}
-				}
-			}(runningTimer, randomHintCancel)
-		}
+		// 启用正念铃声时为本次计时创建调度
+		randomHintCancel = startBells(runningTimer, sessionID)
 
 		go func(sessID int64, mode string) {
 			for tick := range runningTimer.Chan() {
 				// update label
 				runOnMain(func() {
+					if !tick.Done {
+						updateTick(tick.ElapsedSeconds, tick.RemainSeconds, mode == logic.ModeCountDown)
+					}
 					if mode == logic.ModeCountDown {
 						timerLabel.SetText(fmt.Sprintf("%02d:%02d", tick.RemainSeconds/60, tick.RemainSeconds%60))
 					} else {
@@ -994,30 +1363,16 @@ func NewMainWindow(app fyne.App) fyne.Window {
 				if tick.Done {
 					// complete session
 					_ = model.EndSession(sessID, false)
+					runOnMain(stopAmbience)
+					runOnMain(stopTick)
 
 					// 如果是倒计时模式，播放提示音并显示通知
 					if mode == logic.ModeCountDown {
 						// 在主线程上处理提示音和对话框
 						runOnMain(func() {
-							// 先停止任何可能正在播放的提示音
-							if hintPlayer != nil {
-								hintPlayer.Stop()
-							}
-
-							// 如果没有静音，播放提示音
-							if !muteAlerts && hintPlayer != nil {
-								log.Printf("[DEBUG] 倒计时结束，开始播放提示音...")
-								if err := hintPlayer.PlayLoop(); err != nil {
-									log.Printf("[ERROR] 播放提示音失败: %v", err)
-								} else {
-									log.Printf("[DEBUG] 提示音开始播放")
-								}
-							} else if muteAlerts {
-								log.Printf("[DEBUG] 倒计时结束，但静音已启用，不播放提示音")
-							}
-
-							// 显示通知对话框
-							showTimerCompletedDialog(w, nil)
+							// 先停止可能正在播放的随机提示音，再按闹铃方式播放倒计时结束的提示音
+							stopSound(audio.EventRandomHint)
+							ringCountdownEnd()
 						})
 					}
 
@@ -1025,7 +1380,8 @@ func NewMainWindow(app fyne.App) fyne.Window {
 						runningTimer = nil
 						timerLabel.Hide()
 						stopBtn.Disable()
-						// 停止随机提示音调度
+						resetPause()
+						// 停止正念铃声调度
 						if randomHintCancel != nil {
 							close(randomHintCancel)
 							randomHintCancel = nil
@@ -1035,6 +1391,37 @@ func NewMainWindow(app fyne.App) fyne.Window {
 				}
 			}
 		}(sessionID, mode)
+	}
+	// 所选任务还有未完成的前置任务时先确认再开始
+	startBtn := widget.NewButtonWithIcon("开始", theme.MediaPlayIcon(), func() {
+		if runningTimer != nil {
+			return
+		}
+		var mode string
+		if modeRadio.Selected == "倒计时" {
+			mode = logic.ModeCountDown
+		} else {
+			mode = logic.ModeCountUp
+		}
+
+		mins, _ := strconv.Atoi(minuteEntry.Text)
+		secs := mins * 60
+		if mode == logic.ModeCountDown && secs <= 0 {
+			dialog.ShowError(errors.New("请输入大于0的分钟数"), w)
+			return
+		}
+		if selectedTask != nil {
+			if badge := blockedBadge(model.Blockers(*selectedTask, taskByID)); badge != "" && !selectedTask.IsDone {
+				msg := fmt.Sprintf("任务 '%s' 还在等待前置任务完成：\n%s\n仍然开始专注？", selectedTask.Title, badge)
+				dialog.ShowConfirm("任务被阻塞", msg, func(ok bool) {
+					if ok {
+						startSession(mode, secs)
+					}
+				}, w)
+				return
+			}
+		}
+		startSession(mode, secs)
 	})
 
 	// 停止按钮
@@ -1054,15 +1441,15 @@ func NewMainWindow(app fyne.App) fyne.Window {
 		runningTimer = nil
 		timerLabel.Hide()
 		stopBtn.Disable()
+		resetPause()
+		stopAmbience()
+		stopTick()
 
 		// 停止任何正在播放的提示音
-		if hintPlayer != nil {
-			log.Printf("[DEBUG] 停止按钮被点击，正在停止所有提示音")
-			hintPlayer.Stop()
-			log.Printf("[DEBUG] 提示音已停止")
-		}
+		log.Printf("[DEBUG] 停止按钮被点击，正在停止所有提示音")
+		stopAllSounds()
 
-		// 停止随机提示音调度
+		// 停止正念铃声调度
 		if randomHintCancel != nil {
 			close(randomHintCancel)
 			randomHintCancel = nil
@@ -1072,15 +1459,40 @@ func NewMainWindow(app fyne.App) fyne.Window {
 	})
 	stopBtn.Disable()
 
-	// 创建随机提示音按钮
-	var randomBtn *widget.Button
-	randomBtn = widget.NewButton("随机提示音: 关", func() {
-		randomHintEnabled = !randomHintEnabled
-		if randomHintEnabled {
-			randomBtn.SetText("随机提示音: 开")
+	// 暂停按钮：暂停的时间不计入专注，背景音随之暂停
+	pauseBtn = widget.NewButtonWithIcon("暂停", theme.MediaPauseIcon(), func() {
+		if runningTimer == nil {
+			return
+		}
+		runningTimer.Pause()
+		paused := runningTimer.Paused()
+		if paused {
+			pauseBtn.SetText("继续")
+			pauseBtn.SetIcon(theme.MediaPlayIcon())
 		} else {
-			randomBtn.SetText("随机提示音: 关")
+			pauseBtn.SetText("暂停")
+			pauseBtn.SetIcon(theme.MediaPauseIcon())
 		}
+		pauseAmbience(paused)
+		pauseTick(paused)
+	})
+	pauseBtn.Disable()
+
+	// 正念铃声开关，保存在配置文件中，下一次开始专注时生效
+	loadBell()
+	loadTick()
+	bellText := func() string {
+		if bellSetting.Enabled {
+			return "正念铃声: 开"
+		}
+		return "正念铃声: 关"
+	}
+	var randomBtn *widget.Button
+	randomBtn = widget.NewButton(bellText(), func() {
+		b := bellSetting
+		b.Enabled = !b.Enabled
+		setBell(b)
+		randomBtn.SetText(bellText())
 	})
 	randomBtn.Importance = widget.LowImportance
 
@@ -1093,11 +1505,7 @@ func NewMainWindow(app fyne.App) fyne.Window {
 			muteBtn.SetIcon(theme.VolumeMuteIcon())
 
 			// 立即停止正在播放的提示音
-			if hintPlayer != nil {
-				log.Printf("[DEBUG] 尝试停止正在播放的提示音")
-				hintPlayer.Stop()
-				log.Printf("[DEBUG] 提示音已停止")
-			}
+			stopAllSounds()
 		} else {
 			log.Printf("[DEBUG] 用户关闭静音，设置图标为有声")
 			muteBtn.SetIcon(theme.VolumeUpIcon())
@@ -1105,9 +1513,14 @@ func NewMainWindow(app fyne.App) fyne.Window {
 	})
 	muteBtn.Importance = widget.LowImportance
 
-	timeRow := container.NewHBox(layout.NewSpacer(), timerLabel, stopBtn, muteBtn, randomBtn)
+	soundBtn := widget.NewButtonWithIcon("", theme.SettingsIcon(), func() { showSoundSettings(w) })
+	soundBtn.Importance = widget.LowImportance
+
+	timeRow := container.NewHBox(layout.NewSpacer(), timerLabel, pauseBtn, stopBtn, muteBtn, randomBtn, soundBtn)
 
-	controlBar := container.NewVBox(container.NewHBox(modeRadio, widget.NewLabel("时长(分钟):"), minuteEntry, startBtn), timeRow)
+	controlBar := container.NewVBox(
+		container.NewHBox(modeRadio, widget.NewLabel("时长(分钟):"), minuteEntry, startBtn, layout.NewSpacer(), newAmbienceControl(w)),
+		timeRow)
 
 	// 创建统计信息标签，并封装更新函数
 	statsLabel := widget.NewLabel("")
@@ -1128,23 +1541,11 @@ func NewMainWindow(app fyne.App) fyne.Window {
 			}
 		}
 
-		// --- 倒数日统计 ---
-		now := time.Now()
-		allTasks, _ := model.ListTasks()
-		for _, t := range allTasks {
-			if t.DueDate == nil {
-				continue
-			}
-			days := int(t.DueDate.Sub(now).Hours() / 24)
-			if days < 0 {
-				continue // 已过期
-			}
-			weeks := int(math.Ceil(float64(days) / 7.0))
-			months := int(math.Ceil(float64(days) / 30.0))
-			parts = append(parts, fmt.Sprintf("距离%s: %d天 / %d周 / %d月", t.Title, days, weeks, months))
-		}
-
 		sort.Strings(parts)
+		// --- 置顶倒数日 ---
+		if top := topEventText(time.Now()); top != "" {
+			parts = append([]string{top}, parts...)
+		}
 		statsLabel.SetText(strings.Join(parts, "\n"))
 
 		// 更新饼图
@@ -1247,15 +1648,53 @@ func NewMainWindow(app fyne.App) fyne.Window {
 
 	// 主区域改为左右分栏：任务列表 + 饼图 + 历史记录
 	chartsPanel := createPieChartsPanel()
-	leftPanel := container.NewVSplit(list, chartsPanel)
+	// 任务树上方的展开/折叠按钮
+	expandBtn := widget.NewButton("全部展开", func() { taskTree.OpenAllBranches() })
+	collapseBtn := widget.NewButton("全部折叠", func() { taskTree.CloseAllBranches() })
+	expandBtn.Importance = widget.LowImportance
+	collapseBtn.Importance = widget.LowImportance
+	archivedBtn := widget.NewButton("已归档", func() {
+		showArchivedTasks(w, func() {
+			reloadTasks()
+			updateStats()
+		})
+	})
+	archivedBtn.Importance = widget.LowImportance
+	quickAdd := newQuickAddBar(func(t model.Task) {
+		reloadTasks()
+		updateStats()
+	}, func(err error) { dialog.ShowError(err, w) })
+	taskSplit := container.NewVSplit(taskTree, notes.content)
+	taskSplit.Offset = 0.65
+	taskPanel := container.NewBorder(
+		container.NewVBox(quickAdd, viewBar.content, container.NewHBox(archivedBtn, layout.NewSpacer(), expandBtn, collapseBtn)),
+		nil, nil, nil, taskSplit)
+	events = newEventsPanel(w, updateStats)
+	bottomTabs := container.NewAppTabs(
+		container.NewTabItem("专注占比", chartsPanel),
+		container.NewTabItem("倒数日", events.content),
+	)
+	leftPanel := container.NewVSplit(taskPanel, bottomTabs)
 	leftPanel.Offset = 0.7 // 70%给任务列表，30%给饼图
 
-	split := container.NewHSplit(leftPanel, sessionList)
+	split := container.NewHSplit(leftPanel, container.NewBorder(historyFilterBar, nil, nil, nil, sessionList))
 	split.Offset = 0.4 // 40%给左侧面板，60%给历史记录
 
 	content := container.NewBorder(topBar, controlBar, nil, nil, split)
 
 	w.SetContent(content)
+	if audioErr != nil {
+		dialog.ShowError(audioErr, w)
+	}
+	offerLegacyCountdowns(w, func() {
+		events.changed()
+		reloadTasks()
+	})
+	reloadAll := func() {
+		reloadTasks()
+		updateHistory()
+	}
+	w.SetMainMenu(fyne.NewMainMenu(newReportMenu(w), newDataMenu(w, reloadAll), newProjectMenu(w, reloadAll)))
 	w.Resize(fyne.NewSize(900, 600)) // 增大窗口尺寸以容纳新组件
 
 	return w